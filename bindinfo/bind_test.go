@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"testing"
@@ -38,7 +39,9 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	utilparser "github.com/pingcap/tidb/util/parser"
 	"github.com/pingcap/tidb/util/stmtsummary"
@@ -1092,6 +1095,132 @@ func (s *testSuite) TestCaptureBaselinesDefaultDB(c *C) {
 	c.Assert(len(tk.Se.GetSessionVars().StmtCtx.IndexNames), Equals, 0)
 }
 
+func (s *testSuite) TestPreparedStmtWithPlanCache(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer func() {
+		plannercore.SetPreparedPlanCache(orgEnable)
+	}()
+	plannercore.SetPreparedPlanCache(true)
+	var err error
+	tk.Se, err = session.CreateSession4TestWithOpt(s.store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, index idx_a(a), index idx_b(b))")
+	tk.MustExec("prepare stmt from 'select * from t where a > ? and b > ?'")
+	tk.MustExec("set @p = 1")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// Creating a binding invalidates the cached plan.
+	tk.MustExec("create global binding for select * from t where a > 1 and b > 1 using select * from t use index(idx_b) where a > 1 and b > 1")
+	tk.MustExec("execute stmt using @p, @p")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames, DeepEquals, []string{"t:idx_b"})
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 1"))
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("1 1"))
+
+	// Replacing the binding invalidates the cached plan.
+	tk.MustExec("create global binding for select * from t where a > 1 and b > 1 using select * from t use index(idx_a) where a > 1 and b > 1")
+	tk.MustExec("execute stmt using @p, @p")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames, DeepEquals, []string{"t:idx_a"})
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 1"))
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("1 1"))
+
+	// Disabling the plan baselines invalidates the cached plan.
+	tk.MustExec("set @@tidb_use_plan_baselines = 0")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 0"))
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("1 0"))
+	tk.MustExec("set @@tidb_use_plan_baselines = 1")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 1"))
+
+	// Dropping the binding invalidates the cached plan.
+	tk.MustExec("drop global binding for select * from t where a > 1 and b > 1")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 0"))
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("1 0"))
+
+	// Session bindings take effect as well.
+	tk.MustExec("create session binding for select * from t where a > 1 and b > 1 using select * from t use index(idx_b) where a > 1 and b > 1")
+	tk.MustExec("execute stmt using @p, @p")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames, DeepEquals, []string{"t:idx_b"})
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 1"))
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("1 1"))
+	tk.MustExec("drop session binding for select * from t where a > 1 and b > 1")
+	tk.MustExec("execute stmt using @p, @p")
+	tk.MustQuery("select @@last_plan_from_cache, @@last_plan_from_binding").Check(testkit.Rows("0 0"))
+}
+
+func (s *testSuite) TestPreparedPointPlanWithBinding(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, index idx_b(b))")
+	tk.MustExec("prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("set @p = 1")
+	stmtID, _, _, err := tk.Se.PrepareStmt("select * from t where a = ?")
+	c.Assert(err, IsNil)
+	preparedStmts := []*plannercore.CachedPrepareStmt{
+		tk.Se.GetSessionVars().PreparedStmts[tk.Se.GetSessionVars().PreparedStmtNameToID["stmt"]].(*plannercore.CachedPrepareStmt),
+		tk.Se.GetSessionVars().PreparedStmts[stmtID].(*plannercore.CachedPrepareStmt),
+	}
+	// execute runs the statements in both the text and the binary protocols, and checks the bind SQLs kept along
+	// with the cached point plans.
+	execute := func(bindSQL string) {
+		for i := 0; i < 2; i++ {
+			tk.MustExec("execute stmt using @p")
+			rs, err := tk.Se.ExecutePreparedStmt(context.TODO(), stmtID, []types.Datum{types.NewDatum(1)})
+			c.Assert(err, IsNil)
+			c.Assert(rs.Close(), IsNil)
+		}
+		for _, preparedStmt := range preparedStmts {
+			c.Assert(preparedStmt.PreparedAst.CachedPlan, NotNil)
+			c.Assert(preparedStmt.BindSQL, Equals, bindSQL)
+		}
+	}
+	execute("")
+
+	// Creating a binding invalidates the cached point plans.
+	tk.MustExec("create global binding for select * from t where a = 1 using select * from t use index(idx_b) where a = 1")
+	bindSQL := "SELECT * FROM `test`.`t` USE INDEX (`idx_b`) WHERE `a` = 1"
+	execute(bindSQL)
+
+	// Changing the other bindings keeps the cached point plans.
+	tk.MustExec("create global binding for select * from t where b = 1 using select * from t use index(idx_b) where b = 1")
+	plan := preparedStmts[0].PreparedAst.CachedPlan
+	execute(bindSQL)
+	c.Assert(preparedStmts[0].PreparedAst.CachedPlan, Equals, plan)
+
+	// Disabling the plan baselines invalidates the cached point plans.
+	tk.MustExec("set @@tidb_use_plan_baselines = 0")
+	execute("")
+	tk.MustExec("set @@tidb_use_plan_baselines = 1")
+	execute(bindSQL)
+
+	// Dropping the binding invalidates the cached point plans.
+	tk.MustExec("drop global binding for select * from t where a = 1")
+	execute("")
+	tk.MustExec("create session binding for select * from t where a = 1 using select * from t use index(idx_b) where a = 1")
+	execute(bindSQL)
+	tk.MustExec("drop session binding for select * from t where a = 1")
+	execute("")
+	tk.MustExec("drop global binding for select * from t where b = 1")
+}
+
 func (s *testSuite) TestCapturePreparedStmt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	s.cleanBindingEnv(tk)
@@ -14,6 +14,7 @@
 package bindinfo
 
 import (
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -84,6 +85,19 @@ func (b *Binding) SinceUpdateTime() (time.Duration, error) {
 // cache is a k-v map, key is original sql, value is a slice of BindRecord.
 type cache map[string][]*BindRecord
 
+// cacheVersion is increased after any binding cache is changed, so that the users of the bindings can tell
+// whether the bindings are changed without matching the statements again.
+var cacheVersion uint64
+
+// CacheVersion returns the version of the binding caches.
+func CacheVersion() uint64 {
+	return atomic.LoadUint64(&cacheVersion)
+}
+
+func increaseCacheVersion() {
+	atomic.AddUint64(&cacheVersion, 1)
+}
+
 // BindRecord represents a sql bind record retrieved from the storage.
 type BindRecord struct {
 	OriginalSQL string
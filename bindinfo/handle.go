@@ -146,6 +146,9 @@ func (h *BindHandle) Update(fullLoad bool) (err error) {
 	defer func() {
 		h.bindInfo.lastUpdateTime = lastUpdateTime
 		h.bindInfo.Value.Store(newCache)
+		if len(rows) > 0 {
+			increaseCacheVersion()
+		}
 		h.bindInfo.Unlock()
 	}()
 
@@ -525,6 +528,7 @@ func (h *BindHandle) setBindRecord(hash string, meta *BindRecord) {
 	oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
 	newCache.setBindRecord(hash, meta)
 	h.bindInfo.Value.Store(newCache)
+	increaseCacheVersion()
 	updateMetrics(metrics.ScopeGlobal, oldRecord, meta, false)
 }
 
@@ -536,6 +540,7 @@ func (h *BindHandle) appendBindRecord(hash string, meta *BindRecord) {
 	newRecord := merge(oldRecord, meta)
 	newCache.setBindRecord(hash, newRecord)
 	h.bindInfo.Value.Store(newCache)
+	increaseCacheVersion()
 	updateMetrics(metrics.ScopeGlobal, oldRecord, newRecord, false)
 }
 
@@ -545,6 +550,7 @@ func (h *BindHandle) removeBindRecord(hash string, meta *BindRecord) {
 	oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
 	newCache.removeDeletedBindRecord(hash, meta)
 	h.bindInfo.Value.Store(newCache)
+	increaseCacheVersion()
 	updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db), false)
 }
 
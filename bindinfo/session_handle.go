@@ -42,6 +42,7 @@ func NewSessionBindHandle(parser *parser.Parser) *SessionHandle {
 func (h *SessionHandle) appendBindRecord(hash string, meta *BindRecord) {
 	oldRecord := h.ch.getBindRecord(hash, meta.OriginalSQL, meta.Db)
 	h.ch.setBindRecord(hash, meta)
+	increaseCacheVersion()
 	updateMetrics(metrics.ScopeSession, oldRecord, meta, false)
 }
 
@@ -79,6 +80,7 @@ func (h *SessionHandle) DropBindRecord(originalSQL, db string, binding *Binding)
 		newRecord = record
 	}
 	h.ch.setBindRecord(parser.DigestNormalized(record.OriginalSQL).String(), newRecord)
+	increaseCacheVersion()
 	updateMetrics(metrics.ScopeSession, oldRecord, newRecord, false)
 	return nil
 }
@@ -318,6 +318,8 @@ type Log struct {
 	ExpensiveThreshold  uint   `toml:"expensive-threshold" json:"expensive-threshold"`
 	QueryLogMaxLen      uint64 `toml:"query-log-max-len" json:"query-log-max-len"`
 	RecordPlanInSlowLog uint32 `toml:"record-plan-in-slow-log" json:"record-plan-in-slow-log"`
+	// SlowQueryFormat is the format of the slow query log, one of text or json.
+	SlowQueryFormat string `toml:"slow-query-format" json:"slow-query-format"`
 }
 
 func (l *Log) getDisableTimestamp() bool {
@@ -365,6 +367,10 @@ type Security struct {
 	SpilledFileEncryptionMethod string `toml:"spilled-file-encryption-method" json:"spilled-file-encryption-method"`
 	// EnableSEM prevents SUPER users from having full access.
 	EnableSEM bool `toml:"enable-sem" json:"enable-sem"`
+	// RSAPrivateKeyPath and RSAPublicKeyPath are the RSA key pair used by caching_sha2_password and sha256_password
+	// to exchange the password over insecure connections, a key pair is generated if they are not set.
+	RSAPrivateKeyPath string `toml:"rsa-private-key-path" json:"rsa-private-key-path"`
+	RSAPublicKeyPath  string `toml:"rsa-public-key-path" json:"rsa-public-key-path"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -497,13 +503,22 @@ type PessimisticTxn struct {
 	MaxRetryCount uint `toml:"max-retry-count" json:"max-retry-count"`
 	// The max count of deadlock events that will be recorded in the information_schema.deadlocks table.
 	DeadlockHistoryCapacity uint `toml:"deadlock-history-capacity" json:"deadlock-history-capacity"`
+	// Enable persisting the deadlock events to mysql.deadlock_history.
+	DeadlockHistoryEnablePersistent bool `toml:"deadlock-history-enable-persistent" json:"deadlock-history-enable-persistent"`
+	// The interval of persisting deadlock events, it's counted in seconds.
+	DeadlockHistoryPersistInterval int `toml:"deadlock-history-persist-interval" json:"deadlock-history-persist-interval"`
+	// The maximum days to keep the persisted deadlock events.
+	DeadlockHistoryPersistRetentionDays int `toml:"deadlock-history-persist-retention-days" json:"deadlock-history-persist-retention-days"`
 }
 
 // DefaultPessimisticTxn returns the default configuration for PessimisticTxn
 func DefaultPessimisticTxn() PessimisticTxn {
 	return PessimisticTxn{
-		MaxRetryCount:           256,
-		DeadlockHistoryCapacity: 10,
+		MaxRetryCount:                       256,
+		DeadlockHistoryCapacity:             10,
+		DeadlockHistoryEnablePersistent:     false,
+		DeadlockHistoryPersistInterval:      60,
+		DeadlockHistoryPersistRetentionDays: 7,
 	}
 }
 
@@ -527,6 +542,12 @@ type StmtSummary struct {
 	RefreshInterval int `toml:"refresh-interval" json:"refresh-interval"`
 	// The maximum history size of statement summary.
 	HistorySize int `toml:"history-size" json:"history-size"`
+	// Enable persisting the expired statement summaries to mysql.statements_summary_history.
+	EnablePersistent bool `toml:"enable-persistent" json:"enable-persistent"`
+	// The interval of persisting statement summaries, it's counted in seconds.
+	PersistInterval int `toml:"persist-interval" json:"persist-interval"`
+	// The maximum days to keep the persisted statement summaries.
+	PersistRetentionDays int `toml:"persist-retention-days" json:"persist-retention-days"`
 }
 
 // IsolationRead is the config for isolation read.
@@ -593,6 +614,7 @@ var defaultConf = Config{
 		QueryLogMaxLen:      logutil.DefaultQueryLogMaxLen,
 		RecordPlanInSlowLog: logutil.DefaultRecordPlanInSlowLog,
 		EnableSlowLog:       logutil.DefaultTiDBEnableSlowLog,
+		SlowQueryFormat:     logutil.DefaultSlowLogFormat,
 	},
 	Status: Status{
 		ReportStatus:    true,
@@ -652,12 +674,15 @@ var defaultConf = Config{
 	},
 	PessimisticTxn: DefaultPessimisticTxn(),
 	StmtSummary: StmtSummary{
-		Enable:              true,
-		EnableInternalQuery: false,
-		MaxStmtCount:        3000,
-		MaxSQLLength:        4096,
-		RefreshInterval:     1800,
-		HistorySize:         24,
+		Enable:               true,
+		EnableInternalQuery:  false,
+		MaxStmtCount:         3000,
+		MaxSQLLength:         4096,
+		RefreshInterval:      1800,
+		HistorySize:          24,
+		EnablePersistent:     false,
+		PersistInterval:      60,
+		PersistRetentionDays: 7,
 	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
@@ -851,6 +876,9 @@ func (c *Config) Valid() error {
 	if c.Log.File.MaxSize > MaxLogFileSize {
 		return fmt.Errorf("invalid max log file size=%v which is larger than max=%v", c.Log.File.MaxSize, MaxLogFileSize)
 	}
+	if c.Log.SlowQueryFormat != logutil.SlowLogFormatText && c.Log.SlowQueryFormat != logutil.SlowLogFormatJSON {
+		return fmt.Errorf("unsupported slow query log format %v, TiDB only supports [%v, %v]", c.Log.SlowQueryFormat, logutil.SlowLogFormatText, logutil.SlowLogFormatJSON)
+	}
 	c.OOMAction = strings.ToLower(c.OOMAction)
 	if c.OOMAction != OOMActionLog && c.OOMAction != OOMActionCancel {
 		return fmt.Errorf("unsupported OOMAction %v, TiDB only supports [%v, %v]", c.OOMAction, OOMActionLog, OOMActionCancel)
@@ -891,6 +919,19 @@ func (c *Config) Valid() error {
 	if c.StmtSummary.RefreshInterval <= 0 {
 		return fmt.Errorf("refresh-interval in [stmt-summary] should be greater than 0")
 	}
+	if c.PessimisticTxn.DeadlockHistoryPersistInterval <= 0 {
+		return fmt.Errorf("deadlock-history-persist-interval in [pessimistic-txn] should be greater than 0")
+	}
+	if c.PessimisticTxn.DeadlockHistoryPersistRetentionDays <= 0 {
+		return fmt.Errorf("deadlock-history-persist-retention-days in [pessimistic-txn] should be greater than 0")
+	}
+
+	if c.StmtSummary.PersistInterval <= 0 {
+		return fmt.Errorf("persist-interval in [stmt-summary] should be greater than 0")
+	}
+	if c.StmtSummary.PersistRetentionDays <= 0 {
+		return fmt.Errorf("persist-retention-days in [stmt-summary] should be greater than 0")
+	}
 
 	if c.PreparedPlanCache.Capacity < 1 {
 		return fmt.Errorf("capacity in [prepared-plan-cache] should be at least 1")
@@ -953,7 +994,9 @@ var TableLockDelayClean = func() uint64 {
 
 // ToLogConfig converts *Log to *logutil.LogConfig.
 func (l *Log) ToLogConfig() *logutil.LogConfig {
-	return logutil.NewLogConfig(l.Level, l.Format, l.SlowQueryFile, l.File, l.getDisableTimestamp(), func(config *zaplog.Config) { config.DisableErrorVerbose = l.getDisableErrorStack() })
+	c := logutil.NewLogConfig(l.Level, l.Format, l.SlowQueryFile, l.File, l.getDisableTimestamp(), func(config *zaplog.Config) { config.DisableErrorVerbose = l.getDisableErrorStack() })
+	c.SlowQueryFormat = l.SlowQueryFormat
+	return c
 }
 
 // ToTracingConfig converts *OpenTracing to *tracing.Configuration.
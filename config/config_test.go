@@ -108,7 +108,9 @@ func (s *testConfigSuite) TestLogConfig(c *C) {
 		c.Assert(conf.Log.DisableErrorStack, Equals, expectedDisableErrorStack)
 		c.Assert(conf.Log.EnableTimestamp, Equals, expectedEnableTimestamp)
 		c.Assert(conf.Log.DisableTimestamp, Equals, expectedDisableTimestamp)
-		c.Assert(conf.Log.ToLogConfig(), DeepEquals, logutil.NewLogConfig("info", "text", "tidb-slow.log", conf.Log.File, resultedDisableTimestamp, func(config *zaplog.Config) { config.DisableErrorVerbose = resultedDisableErrorVerbose }))
+		expectedLogConfig := logutil.NewLogConfig("info", "text", "tidb-slow.log", conf.Log.File, resultedDisableTimestamp, func(config *zaplog.Config) { config.DisableErrorVerbose = resultedDisableErrorVerbose })
+		expectedLogConfig.SlowQueryFormat = "text"
+		c.Assert(conf.Log.ToLogConfig(), DeepEquals, expectedLogConfig)
 		err := f.Truncate(0)
 		c.Assert(err, IsNil)
 		_, err = f.Seek(0, 0)
@@ -220,6 +222,9 @@ max-stmt-count=1000
 max-sql-length=1024
 refresh-interval=100
 history-size=100
+enable-persistent=true
+persist-interval=30
+persist-retention-days=3
 [experimental]
 allow-expression-index = true
 [isolation-read]
@@ -232,6 +237,9 @@ zone= "dc-1"
 spilled-file-encryption-method = "plaintext"
 [pessimistic-txn]
 deadlock-history-capacity = 123
+deadlock-history-enable-persistent = true
+deadlock-history-persist-interval = 30
+deadlock-history-persist-retention-days = 3
 `)
 
 	c.Assert(err, IsNil)
@@ -267,6 +275,9 @@ deadlock-history-capacity = 123
 	c.Assert(conf.StmtSummary.MaxSQLLength, Equals, uint(1024))
 	c.Assert(conf.StmtSummary.RefreshInterval, Equals, 100)
 	c.Assert(conf.StmtSummary.HistorySize, Equals, 100)
+	c.Assert(conf.StmtSummary.EnablePersistent, Equals, true)
+	c.Assert(conf.StmtSummary.PersistInterval, Equals, 30)
+	c.Assert(conf.StmtSummary.PersistRetentionDays, Equals, 3)
 	c.Assert(conf.EnableBatchDML, Equals, true)
 	c.Assert(conf.RepairMode, Equals, true)
 	c.Assert(conf.MaxServerConnections, Equals, uint32(200))
@@ -287,6 +298,9 @@ deadlock-history-capacity = 123
 	c.Assert(conf.EnableForwarding, Equals, true)
 	c.Assert(conf.StoresRefreshInterval, Equals, uint64(30))
 	c.Assert(conf.PessimisticTxn.DeadlockHistoryCapacity, Equals, uint(123))
+	c.Assert(conf.PessimisticTxn.DeadlockHistoryEnablePersistent, Equals, true)
+	c.Assert(conf.PessimisticTxn.DeadlockHistoryPersistInterval, Equals, 30)
+	c.Assert(conf.PessimisticTxn.DeadlockHistoryPersistRetentionDays, Equals, 3)
 
 	_, err = f.WriteString(`
 [log.file]
@@ -341,7 +355,9 @@ spilled-file-encryption-method = "aes128-ctr"
 	c.Assert(conf, DeepEquals, GetGlobalConfig())
 
 	// Test for log config.
-	c.Assert(conf.Log.ToLogConfig(), DeepEquals, logutil.NewLogConfig("info", "text", "tidb-slow.log", conf.Log.File, false, func(config *zaplog.Config) { config.DisableErrorVerbose = conf.Log.getDisableErrorStack() }))
+	expectedLogConfig := logutil.NewLogConfig("info", "text", "tidb-slow.log", conf.Log.File, false, func(config *zaplog.Config) { config.DisableErrorVerbose = conf.Log.getDisableErrorStack() })
+	expectedLogConfig.SlowQueryFormat = "text"
+	c.Assert(conf.Log.ToLogConfig(), DeepEquals, expectedLogConfig)
 
 	// Test for tracing config.
 	tracingConf := &tracing.Configuration{
@@ -455,6 +471,24 @@ func (s *testConfigSuite) TestOOMActionValid(c *C) {
 	}
 }
 
+func (s *testConfigSuite) TestSlowQueryFormatValid(c *C) {
+	c1 := NewConfig()
+	c.Assert(c1.Log.SlowQueryFormat, Equals, "text")
+	tests := []struct {
+		format string
+		valid  bool
+	}{
+		{"text", true},
+		{"json", true},
+		{"", false},
+		{"yaml", false},
+	}
+	for _, tt := range tests {
+		c1.Log.SlowQueryFormat = tt.format
+		c.Assert(c1.Valid() == nil, Equals, tt.valid)
+	}
+}
+
 func (s *testConfigSuite) TestTxnTotalSizeLimitValid(c *C) {
 	conf := NewConfig()
 	tests := []struct {
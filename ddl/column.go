@@ -144,33 +144,35 @@ func createColumnInfo(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *
 	return colInfo, pos, offset, nil
 }
 
-func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, error) {
+func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, []*model.ConstraintInfo, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 	col := &model.ColumnInfo{}
 	pos := &ast.ColumnPosition{}
 	offset := 0
-	err = job.DecodeArgs(col, pos, &offset)
+	var constraints []*model.ConstraintInfo
+	err = job.DecodeArgs(col, pos, &offset, &constraints)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 
 	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
 	if columnInfo != nil {
-		if columnInfo.State == model.StatePublic {
+		// The column is public when its check constraints are being added.
+		if columnInfo.State == model.StatePublic && job.SchemaState != model.StatePublic {
 			// We already have a column with the same column name.
 			job.State = model.JobStateCancelled
-			return nil, nil, nil, nil, 0, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+			return nil, nil, nil, nil, 0, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 		}
 	}
-	return tblInfo, columnInfo, col, pos, offset, nil
+	return tblInfo, columnInfo, col, pos, offset, constraints, nil
 }
 
-func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+func (w *worker) onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// Handle the rolling back job.
 	if job.IsRollingback() {
 		ver, err = onDropColumn(t, job)
@@ -186,7 +188,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 	})
 
-	tblInfo, columnInfo, col, pos, offset, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, pos, offset, constraints, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -199,7 +201,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		logutil.BgLogger().Info("[ddl] run add column job", zap.String("job", job.String()), zap.Reflect("columnInfo", *columnInfo), zap.Int("offset", offset))
 		// Set offset arg to job.
 		if offset != 0 {
-			job.Args = []interface{}{columnInfo, pos, offset}
+			job.Args = []interface{}{columnInfo, pos, offset, constraints}
 		}
 		if err = checkAddColumnTooManyColumns(len(tblInfo.Columns)); err != nil {
 			job.State = model.JobStateCancelled
@@ -240,6 +242,21 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		// Adjust table column offset.
 		adjustColumnInfoInAddColumn(tblInfo, offset)
 		columnInfo.State = model.StatePublic
+		if len(constraints) > 0 {
+			// The check constraints are enforced on new rows with the column, then the existing rows are verified.
+			for _, constraint := range constraints {
+				tblInfo.MaxConstraintID++
+				constraint.ID = tblInfo.MaxConstraintID
+				constraint.State = model.StateWriteOnly
+				tblInfo.Constraints = append(tblInfo.Constraints, constraint)
+			}
+			ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.SchemaState = model.StatePublic
+			return ver, nil
+		}
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -248,6 +265,9 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		// Finish this job.
 		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
 		asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionAddColumn, TableInfo: tblInfo, ColumnInfos: []*model.ColumnInfo{columnInfo}})
+	case model.StatePublic:
+		// Verify the existing rows, then make the check constraints public.
+		return w.onAddColumnCheckConstraints(d, t, job, tblInfo, columnInfo, constraints)
 	default:
 		err = ErrInvalidDDLState.GenWithStackByArgs("column", columnInfo.State)
 	}
@@ -255,6 +275,62 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 	return ver, errors.Trace(err)
 }
 
+// onAddColumnCheckConstraints verifies the existing rows for the check constraints defined on the added column.
+// If a row violates the constraints, the constraints are removed and the job is converted to drop the column.
+func (w *worker) onAddColumnCheckConstraints(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	columnInfo *model.ColumnInfo, constraints []*model.ConstraintInfo) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	constraintInfos := make([]*model.ConstraintInfo, 0, len(constraints))
+	for _, constraint := range constraints {
+		constraintInfo := tblInfo.FindConstraintInfoByName(constraint.Name.L)
+		if constraintInfo == nil {
+			return ver, errors.Trace(ErrConstraintNotFound.GenWithStackByArgs(constraint.Name.O))
+		}
+		constraintInfos = append(constraintInfos, constraintInfo)
+	}
+	for _, constraintInfo := range constraintInfos {
+		if !constraintInfo.Enforced {
+			continue
+		}
+		if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo); err != nil {
+			if !table.ErrCheckConstraintViolated.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			// public -> write only, then the column is dropped as the rolling back job.
+			for _, c := range constraintInfos {
+				removeCheckConstraint(tblInfo, c.Name)
+			}
+			columnInfo.State = model.StateWriteOnly
+			if err1 := checkDropColumnForStatePublic(tblInfo, columnInfo); err1 != nil {
+				return ver, errors.Trace(err1)
+			}
+			job.SchemaState = model.StateWriteOnly
+			job.Args = []interface{}{columnInfo.Name}
+			ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+			if err1 != nil {
+				return ver, errors.Trace(err1)
+			}
+			job.State = model.JobStateRollingback
+			return ver, errors.Trace(err)
+		}
+	}
+	for _, constraintInfo := range constraintInfos {
+		constraintInfo.State = model.StatePublic
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	asyncNotifyEvent(d, &ddlutil.Event{Tp: model.ActionAddColumn, TableInfo: tblInfo, ColumnInfos: []*model.ColumnInfo{columnInfo}})
+	return ver, nil
+}
+
 func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.ColumnInfo, []*model.ColumnInfo, []*ast.ColumnPosition, []int, []bool, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
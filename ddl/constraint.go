@@ -0,0 +1,384 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// buildCheckConstraints builds the check constraints of the table in CREATE TABLE.
+func buildCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, constrs []*ast.Constraint) error {
+	namesMap := make(map[string]struct{}, len(constrs))
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := namesMap[name]; ok {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		namesMap[name] = struct{}{}
+	}
+	setEmptyCheckConstraintName(tblInfo.Name.L, namesMap, constrs)
+
+	for _, constr := range constrs {
+		constraintInfo, err := buildConstraintInfo(ctx, tblInfo, constr, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+	return nil
+}
+
+// buildAddColumnCheckConstraints builds the check constraints defined on the column in ADD COLUMN.
+// The constraints are checked against the table info with the new column.
+func buildAddColumnCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, colInfo *model.ColumnInfo, constrs []*ast.Constraint) ([]*model.ConstraintInfo, error) {
+	if len(constrs) == 0 {
+		return nil, nil
+	}
+	namesMap := make(map[string]struct{}, len(tblInfo.Constraints)+len(constrs))
+	for _, constraintInfo := range tblInfo.Constraints {
+		namesMap[constraintInfo.Name.L] = struct{}{}
+	}
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := namesMap[name]; ok {
+			return nil, ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		namesMap[name] = struct{}{}
+	}
+	setEmptyCheckConstraintName(tblInfo.Name.L, namesMap, constrs)
+
+	newTblInfo := tblInfo.Clone()
+	newColInfo := colInfo.Clone()
+	newColInfo.Offset = len(newTblInfo.Columns)
+	newColInfo.State = model.StatePublic
+	newTblInfo.Columns = append(newTblInfo.Columns, newColInfo)
+	constraintInfos := make([]*model.ConstraintInfo, 0, len(constrs))
+	for _, constr := range constrs {
+		constraintInfo, err := buildConstraintInfo(ctx, newTblInfo, constr, model.StateNone)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constraintInfos = append(constraintInfos, constraintInfo)
+	}
+	return constraintInfos, nil
+}
+
+// setEmptyCheckConstraintName sets the default names like `t_chk_1` for the unnamed check constraints.
+func setEmptyCheckConstraintName(tblLowerName string, namesMap map[string]struct{}, constrs []*ast.Constraint) {
+	num := 1
+	for _, constr := range constrs {
+		if constr.Name != "" {
+			continue
+		}
+		name := fmt.Sprintf("%s_chk_%d", tblLowerName, num)
+		for {
+			if _, ok := namesMap[name]; !ok {
+				break
+			}
+			num++
+			name = fmt.Sprintf("%s_chk_%d", tblLowerName, num)
+		}
+		namesMap[name] = struct{}{}
+		constr.Name = name
+		num++
+	}
+}
+
+// buildConstraintInfo checks the check constraint expression and builds the constraint info.
+func buildConstraintInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint, state model.SchemaState) (*model.ConstraintInfo, error) {
+	if err := checkIllegalFn4Generated(constr.Name, typeConstraint, constr.Expr); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	dependedCols := make([]model.CIStr, 0, 1)
+	dependedColsMap := make(map[string]struct{})
+	for _, colName := range findColumnNamesInExpr(constr.Expr) {
+		if _, ok := dependedColsMap[colName.Name.L]; ok {
+			continue
+		}
+		dependedColsMap[colName.Name.L] = struct{}{}
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		if col == nil {
+			return nil, ErrCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constr.Name, colName.Name.O)
+		}
+		if constr.InColumn && colName.Name.L != strings.ToLower(constr.InColumnName) {
+			return nil, ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constr.Name)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return nil, ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constr.Name)
+		}
+		dependedCols = append(dependedCols, col.Name)
+	}
+
+	// The expression must be evaluated as a boolean value.
+	expr, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, constr.Expr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !mysql.HasIsBooleanFlag(expr.GetType().Flag) {
+		return nil, ErrNonBooleanExprForCheckConstraint.GenWithStackByArgs(constr.Name)
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	restoreCtx := format.NewRestoreCtx(restoreFlags, &sb)
+	if err = constr.Expr.Restore(restoreCtx); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &model.ConstraintInfo{
+		Name:           model.NewCIStr(constr.Name),
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+		State:          state,
+	}, nil
+}
+
+// getColumnCheckConstraintInfo returns the first check constraint which refers to the column.
+func getColumnCheckConstraintInfo(colName string, constraintInfos []*model.ConstraintInfo) *model.ConstraintInfo {
+	for _, constraintInfo := range constraintInfos {
+		for _, col := range constraintInfo.ConstraintCols {
+			if col.L == colName {
+				return constraintInfo
+			}
+		}
+	}
+	return nil
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, constraintName model.CIStr) {
+	constraints := tblInfo.Constraints[:0]
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.Name.L != constraintName.L {
+			constraints = append(constraints, constraintInfo)
+		}
+	}
+	tblInfo.Constraints = constraints
+}
+
+func (w *worker) onAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constraintInfoInJob model.ConstraintInfo
+	if err = job.DecodeArgs(&constraintInfoInJob); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintInfoInJob.Name.L)
+	if constraintInfo == nil {
+		constraintInfo = constraintInfoInJob.Clone()
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		constraintInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	} else if constraintInfo.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StateNone:
+		// none -> write only
+		// All the servers should enforce the constraint on new rows before the existing rows are verified.
+		job.SchemaState = model.StateWriteOnly
+		constraintInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+	case model.StateWriteOnly:
+		// write only -> public
+		if constraintInfo.Enforced {
+			if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo); err != nil {
+				if table.ErrCheckConstraintViolated.Equal(err) {
+					removeCheckConstraint(tblInfo, constraintInfo.Name)
+					return rollbackCheckConstraintJob(t, job, tblInfo, err)
+				}
+				return ver, errors.Trace(err)
+			}
+		}
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constraintName model.CIStr
+	if err = job.DecodeArgs(&constraintName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constraintName.O)
+	}
+
+	// Dropping a constraint only relaxes the restriction on writes, so it is safe to drop it in one step.
+	// public -> none
+	removeCheckConstraint(tblInfo, constraintInfo.Name)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func (w *worker) onAlterCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var (
+		constraintName model.CIStr
+		enforced       bool
+	)
+	if err = job.DecodeArgs(&constraintName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constraintName.O)
+	}
+
+	switch {
+	case !enforced:
+		// Not enforcing a constraint only relaxes the restriction on writes, so finish it in one step.
+		constraintInfo.Enforced = false
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	case constraintInfo.Enforced && constraintInfo.State == model.StatePublic:
+		// The constraint is already enforced, nothing to do.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	case !constraintInfo.Enforced:
+		// public (not enforced) -> write only (enforced)
+		constraintInfo.Enforced = true
+		constraintInfo.State = model.StateWriteOnly
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	default:
+		// write only (enforced) -> public (enforced)
+		if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo); err != nil {
+			if table.ErrCheckConstraintViolated.Equal(err) {
+				constraintInfo.Enforced = false
+				constraintInfo.State = model.StatePublic
+				return rollbackCheckConstraintJob(t, job, tblInfo, err)
+			}
+			return ver, errors.Trace(err)
+		}
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	}
+	return ver, errors.Trace(err)
+}
+
+// rollbackCheckConstraintJob saves the restored table info and finishes the job as rolled back with the error.
+func rollbackCheckConstraintJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, err error) (ver int64, _ error) {
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, errors.Trace(err)
+}
+
+// verifyRemainRecordsForCheckConstraint checks whether the existing rows satisfy the check constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	// Get sessionctx from context resource pool.
+	var ctx sessionctx.Context
+	ctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(ctx)
+
+	// The expression is restored by ourselves, escape the '%' so that it isn't taken as a parameter.
+	sql := "select 1 from %n.%n where not (" + strings.ReplaceAll(constraintInfo.ExprString, "%", "%%") + ") limit 1"
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.Background(), sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return table.ErrCheckConstraintViolated.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	return nil
+}
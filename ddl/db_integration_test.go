@@ -2875,8 +2875,11 @@ func (s *testIntegrationSuite3) TestCreateTemporaryTable(c *C) {
 	tk.MustGetErrCode("create table t(id int) on commit delete rows", errno.ErrParse)
 	tk.MustGetErrCode("create table t(id int) on commit preserve rows", errno.ErrParse)
 
-	// Not support yet.
-	tk.MustGetErrCode("create global temporary table t (id int) on commit preserve rows", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create global temporary table t (id int) on commit preserve rows")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE GLOBAL TEMPORARY TABLE `t` (\n" +
+		"  `id` int(11) DEFAULT NULL\n" +
+		") ENGINE=memory DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin ON COMMIT PRESERVE ROWS"))
+	tk.MustExec("drop table t")
 	// Engine type can only be 'memory' or empty for now.
 	tk.MustGetErrCode("create global temporary table t (id int) engine = 'innodb' on commit delete rows", errno.ErrUnsupportedDDLOperation)
 	// Follow the behaviour of the old version TiDB: parse and ignore the 'temporary' keyword.
@@ -1282,6 +1282,146 @@ func (s *testIntegrationSuite5) TestAlterTableTruncatePartitionByListColumns(c *
 	tk.MustQuery("select * from t").Check(testkit.Rows())
 }
 
+func (s *testIntegrationSuite5) TestReorganizeRangePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec(`create table t (a int, b varchar(10), c int, unique key ua(a), key ib(b))
+		partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition pmax values less than (maxvalue));`)
+	tk.MustExec("insert into t values (1, 'a', 1), (11, 'b', 11), (21, 'c', 21), (31, 'd', 31), (100, 'e', 100)")
+
+	// Split the last partition.
+	tk.MustExec(`alter table t reorganize partition pmax into (
+		partition p2 values less than (30),
+		partition pmax values less than (maxvalue));`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("21"))
+	tk.MustQuery("select a from t partition (pmax) order by a").Check(testkit.Rows("31", "100"))
+	tk.MustQuery("select a from t use index(ib) where b = 'd'").Check(testkit.Rows("31"))
+	tk.MustGetErrCode("insert into t values (21, 'f', 0)", tmysql.ErrDupEntry)
+
+	// Merge the partitions.
+	tk.MustExec(`alter table t reorganize partition p0, p1 into (partition p01 values less than (20))`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p01) order by a").Check(testkit.Rows("1", "11"))
+	ctx := tk.Se.(sessionctx.Context)
+	tbl, err := domain.GetDomain(ctx).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	part := tbl.Meta().Partition
+	c.Assert(part.Definitions, HasLen, 3)
+	c.Assert(part.Definitions[0].Name.L, Equals, "p01")
+	c.Assert(part.Definitions[1].Name.L, Equals, "p2")
+	c.Assert(part.AddingDefinitions, HasLen, 0)
+	c.Assert(part.DroppingDefinitions, HasLen, 0)
+
+	// The last partition can extend its range.
+	tk.MustExec("drop table if exists t1;")
+	tk.MustExec(`create table t1 (a int) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20));`)
+	tk.MustExec("insert into t1 values (1), (15)")
+	tk.MustExec(`alter table t1 reorganize partition p1 into (
+		partition p1 values less than (15),
+		partition p2 values less than (30));`)
+	tk.MustQuery("select a from t1 partition (p2)").Check(testkit.Rows("15"))
+	tk.MustExec("insert into t1 values (25)")
+	tk.MustExec("admin check table t1")
+
+	tk.MustGetErrCode("alter table t1 reorganize partition p3 into (partition p3 values less than (40))", tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode(`alter table t1 reorganize partition p0, p2 into (partition p0 values less than (30))`, tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode(`alter table t1 reorganize partition p0 into (partition p0 values less than (5))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t1 reorganize partition p1 into (partition p1 values less than (20))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t1 reorganize partition p0 into (
+		partition p00 values less than (5),
+		partition p01 values less than (4))`, tmysql.ErrRangeNotIncreasing)
+	tk.MustGetErrCode("alter table t1 reorganize partition", tmysql.ErrReorgNoParam)
+	tk.MustExec("drop table if exists t, t1;")
+}
+
+func (s *testIntegrationSuite5) TestReorganizeListPartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec(`create table t (id int, key(id)) partition by list (id) (
+		partition p0 values in (1, 2, 3),
+		partition p1 values in (4, 5, null));`)
+	tk.MustExec("insert into t values (1), (2), (4), (null)")
+	tk.MustExec(`alter table t reorganize partition p0, p1 into (
+		partition p0 values in (1, 4, null),
+		partition p1 values in (2, 3, 5, 6));`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select id from t partition (p0) order by id").Check(testkit.Rows("<nil>", "1", "4"))
+	tk.MustQuery("select id from t partition (p1)").Check(testkit.Rows("2"))
+	tk.MustExec("insert into t values (6)")
+
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (partition p0 values in (1, 4))`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (
+		partition p00 values in (1, 4),
+		partition p01 values in (null, 2))`, tmysql.ErrMultipleDefConstInListPart)
+	tk.MustExec("drop table if exists t;")
+}
+
+func (s *testIntegrationSuite5) TestAlterTablePartitioning(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int, b varchar(10), primary key (a) nonclustered, key ib(b))")
+	tk.MustExec("insert into t values (1, 'a'), (11, 'b'), (21, 'c')")
+
+	tk.MustExec(`alter table t partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (maxvalue))`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p1)").Check(testkit.Rows("11"))
+	tk.MustQuery("select a from t use index(ib) where b = 'c'").Check(testkit.Rows("21"))
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	c.Assert(rows[0][3], Equals, "alter table partition by")
+	c.Assert(rows[0][7], Equals, "3")
+	tk.MustGetErrCode("insert into t values (11, 'd')", tmysql.ErrDupEntry)
+
+	// A partitioned table can be partitioned again by the same method.
+	tk.MustExec(`alter table t partition by range (a) (
+		partition p0 values less than (20),
+		partition p1 values less than (maxvalue))`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p0) order by a").Check(testkit.Rows("1", "11"))
+	tk.MustGetErrCode("alter table t partition by hash(a) partitions 2", tmysql.ErrUnsupportedDDLOperation)
+
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("1", "11", "21"))
+	rows = tk.MustQuery("admin show ddl jobs 1").Rows()
+	c.Assert(rows[0][3], Equals, "remove partitioning")
+	ctx := tk.Se.(sessionctx.Context)
+	tbl, err := domain.GetDomain(ctx).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Partition, IsNil)
+
+	tk.MustExec("alter table t partition by hash(a) partitions 3")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("11"))
+	tk.MustExec("insert into t values (2, 'e')")
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("2", "11"))
+
+	tk.MustExec("drop table if exists t1;")
+	tk.MustExec("create table t1 (a int, b int, unique key ub(b))")
+	tk.MustExec("insert into t1 values (1, 1), (30, 30)")
+	tk.MustGetErrCode("alter table t1 partition by hash(a) partitions 2", tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	// The job is rolled back if a row doesn't belong to any new partition.
+	tk.MustGetErrCode("alter table t1 partition by range (b) (partition p0 values less than (10))", tmysql.ErrNoPartitionForGivenValue)
+	tbl, err = domain.GetDomain(ctx).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Partition, IsNil)
+	tk.MustExec("admin check table t1")
+	tk.MustQuery("select b from t1 order by b").Check(testkit.Rows("1", "30"))
+	tk.MustExec("drop table if exists t, t1;")
+}
+
 func (s *testIntegrationSuite3) TestCreateTableWithKeyPartition(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test;")
@@ -1294,6 +1434,54 @@ func (s *testIntegrationSuite3) TestCreateTableWithKeyPartition(c *C) {
 
 	tk.MustExec(`drop table if exists tm2`)
 	tk.MustExec(`create table tm2 (a char(5), unique key(a(5))) partition by key() partitions 5;`)
+
+	ctx := tk.Se.(sessionctx.Context)
+	is := domain.GetDomain(ctx).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("tm1"))
+	c.Assert(err, IsNil)
+	pi := tbl.Meta().Partition
+	c.Assert(pi, NotNil)
+	c.Assert(pi.Type, Equals, model.PartitionTypeKey)
+	c.Assert(pi.Columns, DeepEquals, []model.CIStr{model.NewCIStr("s1")})
+	c.Assert(pi.Num, Equals, uint64(10))
+	c.Assert(pi.Definitions, HasLen, 10)
+	// PARTITION BY KEY() uses the columns of the unique key if there is no primary key.
+	tbl, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("tm2"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Partition.Columns, DeepEquals, []model.CIStr{model.NewCIStr("a")})
+
+	tk.MustExec(`drop table if exists tm3`)
+	tk.MustExec(`create table tm3 (a int, b varchar(10), c datetime, d decimal(10, 2), primary key(b, a))
+	partition by key(a, b) (partition p0, partition p1, partition p2)`)
+	tk.MustQuery("show create table tm3").Check(testkit.Rows("tm3 CREATE TABLE `tm3` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(10) NOT NULL,\n" +
+		"  `c` datetime DEFAULT NULL,\n" +
+		"  `d` decimal(10,2) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`b`,`a`) /*T![clustered_index] NONCLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY KEY( a,b )\n" +
+		"PARTITIONS 3"))
+	tk.MustQuery("select partition_name, partition_method, partition_expression from information_schema.partitions where table_name = 'tm3'").Check(
+		testkit.Rows("p0 KEY a,b", "p1 KEY a,b", "p2 KEY a,b"))
+	tk.MustExec("insert into tm3 values (1, 'a', '2021-01-01', 1.1), (2, 'b', null, null), (3, 'c', '2021-01-03', 3.3)")
+	tk.MustExec("admin check table tm3")
+	tk.MustQuery("select a from tm3 where a = 2 and b = 'b'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from tm3 order by a").Check(testkit.Rows("1", "2", "3"))
+
+	// Columns of any type except BLOB, TEXT and JSON can be used.
+	tk.MustExec(`drop table if exists tm4`)
+	tk.MustGetErrCode(`create table tm4 (a text) partition by key(a) partitions 2`, tmysql.ErrBlobFieldInPartFunc)
+	tk.MustGetErrCode(`create table tm4 (a json) partition by key(a) partitions 2`, tmysql.ErrBlobFieldInPartFunc)
+	tk.MustGetErrCode(`create table tm4 (a int) partition by key() partitions 2`, tmysql.ErrFieldNotFoundPart)
+	tk.MustGetErrCode(`create table tm4 (a int) partition by key(b) partitions 2`, tmysql.ErrFieldNotFoundPart)
+	tk.MustGetErrCode(`create table tm4 (a int, b int, primary key(a)) partition by key(b) partitions 2`, tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustGetErrCode(`create table tm4 (a int) partition by key(a) partitions 0`, tmysql.ErrNoParts)
+	// Linear key partitioning isn't supported, the table is created without partitions.
+	tk.MustExec(`create table tm4 (a int) partition by linear key(a) partitions 2`)
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8200 Unsupported partition type, treat as normal table"))
+	tk.MustGetErrCode(`alter table tm1 coalesce partition 2`, tmysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("drop table if exists tm1, tm2, tm3, tm4")
 }
 
 func (s *testIntegrationSuite5) TestAlterTableAddPartition(c *C) {
@@ -3048,14 +3236,15 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	_, err = tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
+	tk.MustGetErrCode(`alter table employees reorganize partition p0, p1 into (
 			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("create table t_nopart (a int)")
+	tk.MustGetErrCode("alter table t_nopart remove partitioning;", tmysql.ErrPartitionMgmtOnNonpartitioned)
 
 	// Reduce the impact on DML when executing partition DDL
 	tk1 := testkit.NewTestKit(c, s.store)
@@ -3206,7 +3395,7 @@ func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
 	`)
 
 	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	c.Assert(err, ErrorMatches, ".*changing the partitioning method of a partitioned table, remove partitioning first")
 }
 
 func (s *testSerialDBSuite1) TestCommitWhenSchemaChange(c *C) {
@@ -3452,3 +3641,152 @@ func (s *testSerialDBSuite1) TestTruncatePartitionMultipleTimes(c *C) {
 	<-done2
 	c.Assert(errCount, LessEqual, int32(1))
 }
+
+func (s *testSerialDBSuite1) TestReorganizePartitionWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec(`create table t (a int, b int, unique key ua(a), key ib(b)) partition by range (a) (
+		partition p0 values less than (100),
+		partition p1 values less than (maxvalue));`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (101, 101), (102, 102), (201, 201)")
+
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	dom.DDL().SetHook(hook)
+	var checkErr error
+	states := make(map[model.SchemaState]struct{})
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionReorganizePartition || checkErr != nil {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// Change the rows of both the reorganized partition and the others in every state.
+		base := 1000 * (len(states) + 1)
+		sqls := []string{
+			fmt.Sprintf("insert into t values (%d, %d), (%d, %d)", base, base, base+100, base+100),
+			fmt.Sprintf("update t set a = a + %d, b = b + 1 where a = %d", base+500, len(states)),
+			fmt.Sprintf("update t set b = b + 1 where a = %d", base+100),
+			fmt.Sprintf("delete from t where a = %d", 100+len(states)),
+		}
+		for _, sql := range sqls {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				return
+			}
+		}
+	}
+	tk.MustExec(`alter table t reorganize partition p1 into (
+		partition p1 values less than (2000),
+		partition p2 values less than (4000),
+		partition p3 values less than (maxvalue));`)
+	c.Assert(checkErr, IsNil)
+	c.Assert(states, HasLen, 5)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("13"))
+	tk.MustQuery("select a from t partition (p0)").Check(testkit.Rows())
+	tk.MustQuery("select a, b from t partition (p1)").Check(testkit.Rows("201 201"))
+	tk.MustQuery("select a, b from t partition (p2) order by a").Check(testkit.Rows("2000 2000", "2100 2101", "2501 2", "3000 3000", "3100 3101", "3502 3"))
+	tk.MustQuery("select a from t use index(ib) where b = 6101").Check(testkit.Rows("6100"))
+
+	// The job can be cancelled before the new partitions replace the old ones.
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionReorganizePartition || job.State != model.JobStateRunning ||
+			job.SchemaState != model.StateWriteReorganization || job.SnapshotVer != 0 || checkErr != nil {
+			return
+		}
+		hookCtx := mock.NewContext()
+		hookCtx.Store = s.store
+		if checkErr = hookCtx.NewTxn(context.Background()); checkErr != nil {
+			return
+		}
+		txn, err := hookCtx.Txn(true)
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		errs, err := admin.CancelJobs(txn, []int64{job.ID})
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		if errs[0] != nil {
+			checkErr = errors.Trace(errs[0])
+			return
+		}
+		checkErr = txn.Commit(context.Background())
+	}
+	tk.MustGetErrCode(`alter table t reorganize partition p0 into (
+		partition p00 values less than (50),
+		partition p0 values less than (100));`, errno.ErrCancelledDDLJob)
+	c.Assert(checkErr, IsNil)
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	part := tbl.Meta().Partition
+	c.Assert(part.Definitions, HasLen, 4)
+	c.Assert(part.Definitions[0].Name.L, Equals, "p0")
+	c.Assert(part.AddingDefinitions, HasLen, 0)
+	c.Assert(part.DroppingDefinitions, HasLen, 0)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("13"))
+}
+
+func (s *testSerialDBSuite1) TestAlterTablePartitioningWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int, b int, unique key ua(a), key ib(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	dom.DDL().SetHook(hook)
+	var checkErr error
+	var states map[model.SchemaState]struct{}
+	next := 100
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if (job.Type != model.ActionAlterTablePartitioning && job.Type != model.ActionRemovePartitioning) || checkErr != nil {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// Insert a row, then move it, update it, and delete another row in every state.
+		next++
+		sqls := []string{
+			fmt.Sprintf("insert into t values (%d, %d)", next, next),
+			fmt.Sprintf("update t set a = a + 1000 where a = %d", next),
+			fmt.Sprintf("update t set b = b + 1 where a = %d", next+1000),
+			fmt.Sprintf("delete from t where a = %d", len(states)),
+		}
+		for _, sql := range sqls {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				return
+			}
+		}
+	}
+
+	states = make(map[model.SchemaState]struct{})
+	tk.MustExec("alter table t partition by hash(a) partitions 3")
+	c.Assert(checkErr, IsNil)
+	c.Assert(states, HasLen, 5)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a, b from t order by a").Check(testkit.Rows("1101 102", "1102 103", "1103 104", "1104 105", "1105 106"))
+	tk.MustQuery("select a from t partition (p1) order by a").Check(testkit.Rows("1102", "1105"))
+
+	states = make(map[model.SchemaState]struct{})
+	tk.MustExec("alter table t remove partitioning")
+	c.Assert(checkErr, IsNil)
+	c.Assert(states, HasLen, 5)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("10"))
+	tk.MustQuery("select a, b from t where a > 1105 order by a").Check(testkit.Rows("1106 107", "1107 108", "1108 109", "1109 110", "1110 111"))
+	tk.MustQuery("select a from t use index(ib) where b = 111").Check(testkit.Rows("1110"))
+}
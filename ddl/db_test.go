@@ -6089,56 +6089,162 @@ func (s *testDBSuite4) TestColumnCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists column_check")
-	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1))")
+	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1), b int constraint b_positive check (b > 0) not enforced)")
 	defer tk.MustExec("drop table if exists column_check")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustQuery("show create table column_check").Check(testutil.RowsWithSep("|", ""+
+		"column_check CREATE TABLE `column_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `column_check_chk_1` CHECK ((`a` > 1)),\n"+
+		"  CONSTRAINT `b_positive` CHECK ((`b` > 0)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	tk.MustExec("insert into column_check values (1, 2, -1), (2, null, null)")
+	tk.MustGetErrCode("insert into column_check values (3, 1, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update column_check set a = 0 where pk = 1", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert ignore into column_check values (3, 1, 1), (4, 5, 5)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|3819|Check constraint 'column_check_chk_1' is violated."))
+	tk.MustExec("update ignore column_check set a = a - 2")
+	tk.MustQuery("select * from column_check order by pk").Check(testkit.Rows("1 2 -1", "2 <nil> <nil>", "4 3 5"))
+
+	tk.MustGetErrCode("create table t_column_check (a int check (a > b), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t_column_check (a int check (c > 0))", errno.ErrCheckConstraintRefersUnknownColumn)
+	tk.MustGetErrCode("create table t_column_check (a int auto_increment primary key check (a > 0))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t_column_check (a int check (a + 1))", errno.ErrNonBooleanExprForCheckConstraint)
+	tk.MustGetErrCode("create table t_column_check (a int check (a > @x))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("create table t_column_check (a int check (a > rand()))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_column_check (a int constraint c1 check (a > 0), b int constraint c1 check (b > 0))", errno.ErrCheckConstraintDupName)
 }
 
 func (s *testDBSuite5) TestAlterCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("create table alter_check (pk int primary key)")
+	tk.MustExec("create table alter_check (pk int primary key, a int, constraint crcn check (a > 1) not enforced)")
 	defer tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("alter table alter_check alter check crcn ENFORCED")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ALTER CHECK is not supported"))
+	tk.MustGetErrCode("alter table alter_check alter check unknown_check enforced", errno.ErrConstraintNotFound)
+
+	tk.MustExec("insert into alter_check values (1, 0)")
+	// The existing rows violate the constraint, so it can't be enforced.
+	tk.MustGetErrCode("alter table alter_check alter check crcn enforced", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into alter_check values (2, 0)")
+
+	tk.MustExec("delete from alter_check")
+	tk.MustExec("alter table alter_check alter check crcn enforced")
+	tk.MustGetErrCode("insert into alter_check values (3, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table alter_check alter check crcn not enforced")
+	tk.MustExec("insert into alter_check values (3, 0)")
+	tk.MustQuery("show create table alter_check").Check(testutil.RowsWithSep("|", ""+
+		"alter_check CREATE TABLE `alter_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `crcn` CHECK ((`a` > 1)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 }
 
 func (s *testDBSuite6) TestDropCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("create table drop_check (pk int primary key)")
+	tk.MustExec("create table drop_check (pk int primary key, a int, constraint crcn check (a > 1))")
 	defer tk.MustExec("drop table if exists drop_check")
+	tk.MustGetErrCode("insert into drop_check values (1, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("alter table drop_check drop column a", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check rename column a to b", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check change column a b int", errno.ErrDependentByCheckConstraint)
+	tk.MustExec("alter table drop_check modify column a bigint")
+
 	tk.MustExec("alter table drop_check drop check crcn")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|DROP CHECK is not supported"))
+	tk.MustExec("insert into drop_check values (1, 0)")
+	tk.MustGetErrCode("alter table drop_check drop check crcn", errno.ErrConstraintNotFound)
+	tk.MustExec("alter table drop_check drop column a")
 }
 
 func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists add_constraint_check")
-	tk.MustExec("create table add_constraint_check (pk int primary key, a int)")
+	tk.MustExec("create table add_constraint_check (pk int primary key, a int, b varchar(10))")
 	defer tk.MustExec("drop table if exists add_constraint_check")
-	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1)")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ADD CONSTRAINT CHECK is not supported"))
+	tk.MustExec("insert into add_constraint_check values (1, 1, 'a%'), (2, 5, 'b')")
+
+	// The existing rows are verified when the constraint is added.
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 1)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_name = 'crn'").Check(testkit.Rows("0"))
+	tk.MustExec("insert into add_constraint_check values (3, 0, 'c')")
+	tk.MustExec("alter table add_constraint_check add constraint crn check (a >= 0)")
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a < 10)", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("alter table add_constraint_check add check (b not like '%\\%')", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table add_constraint_check add check (b not like 'z%')")
+	tk.MustGetErrCode("alter table add_constraint_check add check (c > 0)", errno.ErrCheckConstraintRefersUnknownColumn)
+	tk.MustExec("alter table add_constraint_check add check (a < 10) not enforced")
+
+	tk.MustGetErrCode("insert into add_constraint_check values (4, -1, 'd')", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into add_constraint_check values (4, 1, 'z1')", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into add_constraint_check values (4, 11, 'd')")
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_name like 'add_constraint_check%' or constraint_name = 'crn' order by constraint_name").Check(testkit.Rows(
+		"add_constraint_check_chk_1 (`b` not like _utf8mb4'z%')",
+		"add_constraint_check_chk_2 (`a` < 10)",
+		"crn (`a` >= 0)"))
+	tk.MustQuery("select constraint_name, constraint_type from information_schema.table_constraints where table_name = 'add_constraint_check' and constraint_type = 'CHECK' order by constraint_name").Check(testkit.Rows(
+		"add_constraint_check_chk_1 CHECK",
+		"add_constraint_check_chk_2 CHECK",
+		"crn CHECK"))
+}
+
+func (s *testDBSuite7) TestAddColumnWithCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists add_column_check")
+	tk.MustExec("create table add_column_check (pk int primary key, a int, constraint add_column_check_chk_1 check (a > 0))")
+	defer tk.MustExec("drop table if exists add_column_check")
+	tk.MustExec("insert into add_column_check values (1, 1), (2, 2)")
+
+	// The existing rows are verified with the default value of the new column.
+	tk.MustGetErrCode("alter table add_column_check add column b int default 0 check (b > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select * from add_column_check order by pk").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustGetErrCode("alter table add_column_check add column b int check (b > a)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("alter table add_column_check add column b int constraint add_column_check_chk_1 check (b > 0)", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("alter table add_column_check add column b int check (b > 0), add column c int", errno.ErrUnsupportedConstraintCheck)
+
+	tk.MustExec("alter table add_column_check add column b int default 1 check (b > 0) first")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustExec("alter table add_column_check add column c int constraint c_positive check (c > 0) not enforced")
+	tk.MustGetErrCode("insert into add_column_check values (0, 3, 3, 3)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into add_column_check values (1, 3, 3, -3)")
+	tk.MustQuery("select * from add_column_check order by pk").Check(testkit.Rows("1 1 1 <nil>", "1 2 2 <nil>", "1 3 3 -3"))
+	tk.MustQuery("show create table add_column_check").Check(testutil.RowsWithSep("|", ""+
+		"add_column_check CREATE TABLE `add_column_check` (\n"+
+		"  `b` int(11) DEFAULT '1',\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `c` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `add_column_check_chk_1` CHECK ((`a` > 0)),\n"+
+		"  CONSTRAINT `add_column_check_chk_2` CHECK ((`b` > 0)),\n"+
+		"  CONSTRAINT `c_positive` CHECK ((`c` > 0)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 }
 
-func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
+func (s *testDBSuite7) TestCreateTableWithCheckConstraint(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
-	tk.MustExec("drop table if exists table_constraint_check")
+	tk.MustExec("drop table if exists admin_user")
 	tk.MustExec("CREATE TABLE admin_user (enable bool, CHECK (enable IN (0, 1)));")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	defer tk.MustExec("drop table if exists admin_user")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
 	tk.MustQuery("show create table admin_user").Check(testutil.RowsWithSep("|", ""+
 		"admin_user CREATE TABLE `admin_user` (\n"+
-		"  `enable` tinyint(1) DEFAULT NULL\n"+
+		"  `enable` tinyint(1) DEFAULT NULL,\n"+
+		"  CONSTRAINT `admin_user_chk_1` CHECK ((`enable` in (0,1)))\n"+
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into admin_user values (1), (0), (null)")
+	tk.MustGetErrCode("insert into admin_user values (2)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select count(*) from admin_user").Check(testkit.Rows("3"))
 }
 
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
@@ -265,6 +265,7 @@ type illegalFunctionChecker struct {
 	hasAggFunc     bool
 	hasRowVal      bool // hasRowVal checks whether the functional index refers to a row value
 	hasWindowFunc  bool
+	hasVariable    bool
 	otherErr       error
 }
 
@@ -282,10 +283,14 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 			c.otherErr = err
 			return inNode, true
 		}
-	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.VariableExpr:
-		// Subquery & `values(x)` & variable is not allowed
+	case *ast.SubqueryExpr, *ast.ValuesExpr:
+		// Subquery & `values(x)` is not allowed
 		c.hasIllegalFunc = true
 		return inNode, true
+	case *ast.VariableExpr:
+		// Variable is not allowed
+		c.hasVariable = true
+		return inNode, true
 	case *ast.AggregateFuncExpr:
 		// Aggregate function is not allowed
 		c.hasAggFunc = true
@@ -307,6 +312,7 @@ func (c *illegalFunctionChecker) Leave(inNode ast.Node) (node ast.Node, ok bool)
 const (
 	typeColumn = iota
 	typeIndex
+	typeConstraint
 )
 
 func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error {
@@ -315,12 +321,17 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 	}
 	var c illegalFunctionChecker
 	expr.Accept(&c)
-	if c.hasIllegalFunc {
+	if c.hasVariable && genType == typeConstraint {
+		return ErrCheckConstraintVariables.GenWithStackByArgs(name)
+	}
+	if c.hasIllegalFunc || c.hasVariable {
 		switch genType {
 		case typeColumn:
 			return ErrGeneratedColumnFunctionIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(name)
+		case typeConstraint:
+			return ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(name)
 		}
 	}
 	if c.hasAggFunc {
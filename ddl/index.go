@@ -1392,14 +1392,18 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 		return true, nil
 	}
 
-	var pid int64
+	pid := int64(0)
 	for i, pi := range partitionIDs {
 		if pi == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
+	}
+	if pid == 0 {
+		return false, errors.Errorf("partition id not found %d", reorg.PhysicalTableID)
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
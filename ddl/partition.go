@@ -36,10 +36,12 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -48,8 +50,11 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/slice"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -325,6 +330,12 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 		if !s.Linear && s.Sub == nil {
 			enable = true
 		}
+	case model.PartitionTypeKey:
+		// Partition by key is enabled by default.
+		// Note that linear key is not enabled.
+		if !s.Linear && s.Sub == nil {
+			enable = true
+		}
 	case model.PartitionTypeList:
 		// Partition by list is enabled only when tidb_enable_list_partition is 'ON'.
 		enable = ctx.GetSessionVars().EnableListTablePartition
@@ -351,6 +362,22 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 			return err
 		}
 		pi.Expr = buf.String()
+	} else if s.Tp == model.PartitionTypeKey {
+		if len(s.ColumnNames) > 0 {
+			pi.Columns = make([]model.CIStr, 0, len(s.ColumnNames))
+			for _, cn := range s.ColumnNames {
+				pi.Columns = append(pi.Columns, cn.Name)
+			}
+		} else {
+			// PARTITION BY KEY() uses the columns of the primary key, or of the unique key if there is no primary key.
+			pi.Columns = getDefaultKeyPartitionColumns(tbInfo)
+			if len(pi.Columns) == 0 {
+				return errors.Trace(ErrFieldNotFoundPart)
+			}
+		}
+		if err := checkKeyPartitionColumns(tbInfo); err != nil {
+			return err
+		}
 	} else if s.ColumnNames != nil {
 		pi.Columns = make([]model.CIStr, 0, len(s.ColumnNames))
 		for _, cn := range s.ColumnNames {
@@ -375,7 +402,7 @@ func buildPartitionDefinitionsInfo(ctx sessionctx.Context, defs []*ast.Partition
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
 		return buildRangePartitionDefinitions(ctx, defs, tbInfo)
-	case model.PartitionTypeHash:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
 		return buildHashPartitionDefinitions(ctx, defs, tbInfo)
 	case model.PartitionTypeList:
 		return buildListPartitionDefinitions(ctx, defs, tbInfo)
@@ -383,6 +410,57 @@ func buildPartitionDefinitionsInfo(ctx sessionctx.Context, defs []*ast.Partition
 	return nil, nil
 }
 
+// getDefaultKeyPartitionColumns returns the partitioning columns of 'PARTITION BY KEY()', which are the columns
+// of the primary key, or the columns of the first unique key if the table has no primary key.
+func getDefaultKeyPartitionColumns(tbInfo *model.TableInfo) []model.CIStr {
+	if tbInfo.PKIsHandle {
+		return []model.CIStr{tbInfo.GetPkName()}
+	}
+	uniqueKey := getDefaultKeyPartitionIndex(tbInfo)
+	if uniqueKey == nil {
+		return nil
+	}
+	cols := make([]model.CIStr, 0, len(uniqueKey.Columns))
+	for _, col := range uniqueKey.Columns {
+		cols = append(cols, col.Name)
+	}
+	return cols
+}
+
+// getDefaultKeyPartitionIndex returns the index whose columns are used by 'PARTITION BY KEY()'.
+// It returns nil if the primary key is the handle, or if there are no unique keys.
+func getDefaultKeyPartitionIndex(tbInfo *model.TableInfo) *model.IndexInfo {
+	if tbInfo.PKIsHandle {
+		return nil
+	}
+	var uniqueKey *model.IndexInfo
+	for _, idx := range tbInfo.Indices {
+		if idx.Primary {
+			return idx
+		}
+		if idx.Unique && uniqueKey == nil {
+			uniqueKey = idx
+		}
+	}
+	return uniqueKey
+}
+
+// checkKeyPartitionColumns checks the columns of a "BY KEY" partition. Columns of any type except BLOB, TEXT
+// and JSON can be used, since the rows are hashed by the encoded (collation aware) values of the columns.
+func checkKeyPartitionColumns(tbInfo *model.TableInfo) error {
+	for _, col := range tbInfo.Partition.Columns {
+		colInfo := getColumnInfoByName(tbInfo, col.L)
+		if colInfo == nil {
+			return errors.Trace(ErrFieldNotFoundPart)
+		}
+		switch colInfo.FieldType.Tp {
+		case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeJSON:
+			return errors.Trace(ErrBlobFieldInPartFunc)
+		}
+	}
+	return nil
+}
+
 func buildHashPartitionDefinitions(_ sessionctx.Context, defs []*ast.PartitionDefinition, tbInfo *model.TableInfo) ([]model.PartitionDefinition, error) {
 	if err := checkAddPartitionTooManyPartitions(tbInfo.Partition.Num); err != nil {
 		return nil, err
@@ -581,8 +659,8 @@ func checkAndOverridePartitionID(newTableInfo, oldTableInfo *model.TableInfo) er
 	if newTableInfo.Partition.Type != oldTableInfo.Partition.Type {
 		return ErrRepairTableFail.GenWithStackByArgs("Partition type should be the same")
 	}
-	// Check whether partitionType is hash partition.
-	if newTableInfo.Partition.Type == model.PartitionTypeHash {
+	// Check whether partitionType is hash or key partition.
+	if newTableInfo.Partition.Type == model.PartitionTypeHash || newTableInfo.Partition.Type == model.PartitionTypeKey {
 		if newTableInfo.Partition.Num != oldTableInfo.Partition.Num {
 			return ErrRepairTableFail.GenWithStackByArgs("Hash partition num should be the same")
 		}
@@ -1040,6 +1118,585 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	return ver, errors.Trace(err)
 }
 
+// checkReorganizePartition checks whether the consecutive partitions partNames can be reorganized into
+// the partitions of partInfo, and returns the definitions of the reorganized partitions.
+func checkReorganizePartition(ctx sessionctx.Context, tblInfo *model.TableInfo, partNames []string, partInfo *model.PartitionInfo) ([]model.PartitionDefinition, error) {
+	pi := tblInfo.Partition
+	first := -1
+	for i, name := range partNames {
+		idx := -1
+		for j := range pi.Definitions {
+			if pi.Definitions[j].Name.L == name {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+		if i == 0 {
+			first = idx
+		} else if idx != first+i {
+			return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+	}
+	oldDefs := pi.Definitions[first : first+len(partNames)]
+	newDefs := partInfo.Definitions
+
+	// Check the partitions after reorganization, it may simplify the range definitions of partInfo.
+	clonedMeta := tblInfo.Clone()
+	np := *pi
+	np.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, oldDefs, newDefs)
+	clonedMeta.Partition = &np
+	if err := checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var err error
+	if pi.Type == model.PartitionTypeRange {
+		isLast := first+len(partNames) == len(pi.Definitions)
+		err = checkReorganizeRangeBound(ctx, clonedMeta, &oldDefs[len(oldDefs)-1], &newDefs[len(newDefs)-1], isLast)
+	} else {
+		err = checkReorganizeListValues(ctx, clonedMeta, oldDefs, newDefs)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return oldDefs, nil
+}
+
+// checkReorganizeRangeBound checks the new RANGE partitions cover the same range as the old ones,
+// only the last partition of the table can extend its range.
+func checkReorganizeRangeBound(ctx sessionctx.Context, tblInfo *model.TableInfo, oldDef, newDef *model.PartitionDefinition, isLast bool) error {
+	pi := tblInfo.Partition
+	var greater, less bool
+	var err error
+	if len(pi.Columns) > 0 {
+		if greater, err = checkTwoRangeColumns(ctx, newDef, oldDef, pi, tblInfo); err != nil {
+			return errors.Trace(err)
+		}
+		if less, err = checkTwoRangeColumns(ctx, oldDef, newDef, pi, tblInfo); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		oldMax := strings.EqualFold(oldDef.LessThan[0], partitionMaxValue)
+		newMax := strings.EqualFold(newDef.LessThan[0], partitionMaxValue)
+		switch {
+		case oldMax || newMax:
+			greater, less = newMax && !oldMax, oldMax && !newMax
+		default:
+			isUnsigned := isColUnsigned(tblInfo.Columns, pi) && !ctx.GetSessionVars().SQLMode.HasNoUnsignedSubtractionMode()
+			oldValue, _, err := getRangeValue(ctx, oldDef.LessThan[0], isUnsigned)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			newValue, _, err := getRangeValue(ctx, newDef.LessThan[0], isUnsigned)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if isUnsigned {
+				greater, less = newValue.(uint64) > oldValue.(uint64), newValue.(uint64) < oldValue.(uint64)
+			} else {
+				greater, less = newValue.(int64) > oldValue.(int64), newValue.(int64) < oldValue.(int64)
+			}
+		}
+	}
+	if less || (greater && !isLast) {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	return nil
+}
+
+// checkReorganizeListValues checks the new LIST partitions accept all the values of the old ones.
+func checkReorganizeListValues(ctx sessionctx.Context, tblInfo *model.TableInfo, oldDefs, newDefs []model.PartitionDefinition) error {
+	formatValues := func(defs []model.PartitionDefinition) ([]string, error) {
+		nt := *tblInfo
+		np := *tblInfo.Partition
+		np.Definitions = defs
+		nt.Partition = &np
+		return formatListPartitionValue(ctx, &nt)
+	}
+	oldValues, err := formatValues(oldDefs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newValues, err := formatValues(newDefs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newValueSet := make(map[string]struct{}, len(newValues))
+	for _, v := range newValues {
+		newValueSet[v] = struct{}{}
+	}
+	for _, v := range oldValues {
+		if _, ok := newValueSet[v]; !ok {
+			return errors.Trace(table.ErrNoPartitionForGivenValue.GenWithStackByArgs(v))
+		}
+	}
+	return nil
+}
+
+// getReorganizedTableInfo returns a copy of tblInfo whose partitions are the ones after reorganization.
+func getReorganizedTableInfo(tblInfo *model.TableInfo) *model.TableInfo {
+	pi := tblInfo.Partition
+	np := *pi
+	setPartitionDefinitions(&np, tables.ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions))
+	np.AddingDefinitions, np.DroppingDefinitions, np.States = nil, nil, nil
+	nt := *tblInfo
+	nt.Partition = &np
+	return &nt
+}
+
+// setPartitionDefinitions sets the definitions of pi, and keeps the number of HASH or KEY partitions consistent with them.
+func setPartitionDefinitions(pi *model.PartitionInfo, defs []model.PartitionDefinition) {
+	pi.Definitions = defs
+	if pi.Type == model.PartitionTypeHash || pi.Type == model.PartitionTypeKey {
+		pi.Num = uint64(len(defs))
+	}
+}
+
+// collapsedPartitionName is the name of the partition which holds all the rows of a table that isn't partitioned,
+// while the table is being partitioned by 'ALTER TABLE ... PARTITION BY' or 'ALTER TABLE ... REMOVE PARTITIONING'.
+const collapsedPartitionName = "CollapsedPartitions"
+
+// buildCollapsedPartition builds the partition whose physical table is the table itself, and whose values
+// cover all the partitions of pi. So the rows of the table that isn't partitioned can be described as a
+// partition in the partitioning of pi.
+func buildCollapsedPartition(tblInfo *model.TableInfo, pi *model.PartitionInfo) model.PartitionDefinition {
+	def := model.PartitionDefinition{ID: tblInfo.ID, Name: model.NewCIStr(collapsedPartitionName)}
+	switch pi.Type {
+	case model.PartitionTypeRange:
+		def.LessThan = append([]string(nil), pi.Definitions[len(pi.Definitions)-1].LessThan...)
+	case model.PartitionTypeList:
+		for _, d := range pi.Definitions {
+			def.InValues = append(def.InValues, d.InValues...)
+		}
+	}
+	return def
+}
+
+// isCollapsedPartition checks whether the table only has the partition built by buildCollapsedPartition.
+func isCollapsedPartition(tblInfo *model.TableInfo) bool {
+	pi := tblInfo.GetPartitionInfo()
+	return pi != nil && len(pi.Definitions) == 1 && pi.Definitions[0].ID == tblInfo.ID
+}
+
+// isSamePartitioningMethod checks whether the partitions of pi and newPi are located by the same method.
+func isSamePartitioningMethod(pi, newPi *model.PartitionInfo) bool {
+	if pi.Type != newPi.Type || pi.Expr != newPi.Expr || len(pi.Columns) != len(newPi.Columns) {
+		return false
+	}
+	for i := range pi.Columns {
+		if pi.Columns[i].L != newPi.Columns[i].L {
+			return false
+		}
+	}
+	return true
+}
+
+// prepareReorganizePartition sets the partitions to be replaced and the new partitions of the job in tblInfo.
+// A table that isn't partitioned is regarded as a table with the collapsed partition, and the table after
+// 'REMOVE PARTITIONING' is regarded as a table with the collapsed partition as well, so all of these jobs
+// run in the same way.
+func prepareReorganizePartition(d *ddlCtx, job *model.Job, tblInfo *model.TableInfo, partNames []string, partInfo *model.PartitionInfo) error {
+	pi := tblInfo.GetPartitionInfo()
+	switch job.Type {
+	case model.ActionReorganizePartition:
+		if pi == nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		// The partitions may have been changed since the job was submitted.
+		droppingDefs, err := checkReorganizePartition(newContext(d.store), tblInfo, partNames, partInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pi.DroppingDefinitions = append([]model.PartitionDefinition(nil), droppingDefs...)
+		pi.AddingDefinitions = partInfo.Definitions
+	case model.ActionAlterTablePartitioning:
+		if pi == nil {
+			np := *partInfo
+			collapsed := buildCollapsedPartition(tblInfo, partInfo)
+			setPartitionDefinitions(&np, []model.PartitionDefinition{collapsed})
+			np.DroppingDefinitions = []model.PartitionDefinition{collapsed}
+			np.AddingDefinitions = partInfo.Definitions
+			tblInfo.Partition = &np
+			pi = &np
+			break
+		}
+		if !isSamePartitioningMethod(pi, partInfo) {
+			return errors.Trace(errUnsupportedRepartition)
+		}
+		pi.DroppingDefinitions = append([]model.PartitionDefinition(nil), pi.Definitions...)
+		pi.AddingDefinitions = partInfo.Definitions
+	case model.ActionRemovePartitioning:
+		if pi == nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		pi.DroppingDefinitions = append([]model.PartitionDefinition(nil), pi.Definitions...)
+		pi.AddingDefinitions = []model.PartitionDefinition{buildCollapsedPartition(tblInfo, pi)}
+	}
+	setReorganizePartitionState(pi, model.StateDeleteOnly)
+	return nil
+}
+
+// setReorganizePartitionState records the schema state of reorganization in the adding partitions,
+// it's used by the table to decide how to write the reorganized partitions.
+func setReorganizePartitionState(pi *model.PartitionInfo, state model.SchemaState) {
+	pi.States = make([]model.PartitionState, 0, len(pi.AddingDefinitions))
+	for _, def := range pi.AddingDefinitions {
+		pi.States = append(pi.States, model.PartitionState{ID: def.ID, State: state})
+	}
+}
+
+// onReorganizePartition reorganizes the partitions of a table into the new partitions,
+// it runs the jobs of 'REORGANIZE PARTITION', 'PARTITION BY' and 'REMOVE PARTITIONING'.
+// The schema state changes: none -> delete only -> write only -> write reorganization -> delete reorganization -> none.
+// Until the write reorganization state finishes, the writes to the old partitions are also applied to the
+// new partitions, and the rows of the old partitions are backfilled into the new ones in the write
+// reorganization state. Then the new partitions replace the old ones, and the writes to the new partitions
+// are also applied to the old ones in the delete reorganization state.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	// Handle the rolling back job.
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(t, job)
+	}
+
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	var err error
+	switch job.Type {
+	case model.ActionReorganizePartition:
+		err = job.DecodeArgs(&partNames, partInfo)
+	case model.ActionAlterTablePartitioning:
+		err = job.DecodeArgs(partInfo)
+	}
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil && job.SchemaState != model.StateNone {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+
+	originalState := job.SchemaState
+	switch job.SchemaState {
+	case model.StateNone:
+		err = prepareReorganizePartition(d, job, tblInfo, partNames, partInfo)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		setReorganizePartitionState(pi, model.StateWriteOnly)
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteOnly:
+		// write only -> write reorganization
+		setReorganizePartitionState(pi, model.StateWriteReorganization)
+		job.SchemaState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteReorganization:
+		tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		// The element is only used to record the reorg handle.
+		elements := []*meta.Element{{ID: tblInfo.Columns[0].ID, TypeKey: meta.ColumnElementKey}}
+		reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+		if err != nil || reorgInfo.first {
+			// If we run reorg firstly, we should update the job snapshot version
+			// and then run the reorg next time.
+			return ver, errors.Trace(err)
+		}
+		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (reorgErr error) {
+			defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+				func() {
+					reorgErr = errCancelledDDLJob.GenWithStack("reorganize table `%v` partitions panic", tblInfo.Name)
+				}, false)
+			return w.reorgPartitionData(tbl.(table.PartitionedTable), physicalTableIDs, reorgInfo)
+		})
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// If timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			if kv.IsTxnRetryableError(err) {
+				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+				w.reorgCtx.cleanNotifyReorgCancel()
+				return ver, errors.Trace(err)
+			}
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+					zap.String("job", job.String()), zap.Error(err1))
+			}
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+
+		// Replace the old partitions with the new ones.
+		setPartitionDefinitions(pi, getReorganizedTableInfo(tblInfo).Partition.Definitions)
+		setReorganizePartitionState(pi, model.StateDeleteReorganization)
+		// write reorganization -> delete reorganization
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+	case model.StateDeleteReorganization:
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		if err = dropRuleBundles(d, physicalTableIDs); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		addingDefinitions := pi.AddingDefinitions
+		pi.AddingDefinitions, pi.DroppingDefinitions, pi.States = nil, nil, nil
+		if job.Type == model.ActionRemovePartitioning {
+			// The rows of the collapsed partition are the rows of the table itself.
+			tblInfo.Partition = nil
+		}
+		// used by ApplyDiff in updateSchemaVersion
+		job.CtxVars = []interface{}{physicalTableIDs}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		if job.Type != model.ActionRemovePartitioning {
+			asyncNotifyEvent(d, &util.Event{Tp: model.ActionAddTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: addingDefinitions}})
+		}
+		// A background job will be created to delete old partition data.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// reorgPartitionData backfills the rows of the reorganized partitions into the new partitions, one partition at a time.
+func (w *worker) reorgPartitionData(tbl table.PartitionedTable, physicalTableIDs []int64, reorgInfo *reorgInfo) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, physicalTableIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	// reorgedTbl is the table after reorganization, it's used to locate the new partition of a row.
+	reorgedTbl    table.PartitionedTable
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*reorgPartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+type reorgPartitionRecord struct {
+	key       kv.Key // It's the record key in the old partition, it's used to lock the record.
+	handle    kv.Handle
+	vals      []byte
+	row       []types.Datum
+	partition table.PhysicalTable // It's the new partition of the record.
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column) (*reorgPartitionWorker, error) {
+	reorgedTbl, err := tables.TableFromMeta(nil, getReorganizedTableInfo(t.Meta()))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The rows have been checked by the old partitions, so the unique keys needn't be checked again.
+	sessCtx.GetSessionVars().StmtCtx.BatchCheck = true
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgedTbl:     reorgedTbl.(table.PartitionedTable),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	sysZone := timeutil.SystemLocation()
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, sysZone, w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for _, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			// The column is added after the row is written.
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if val.Kind() == types.KindMysqlTime {
+				t := val.GetMysqlTime()
+				if t.Type() == mysql.TypeTimestamp && sysZone != time.UTC {
+					if err := t.ConvertTimeZone(sysZone, time.UTC); err != nil {
+						return errors.Trace(err)
+					}
+					val.SetMysqlTime(t)
+				}
+			}
+		}
+		row[col.Offset] = val
+	}
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+
+	p, err := w.reorgedTbl.GetPartitionByRow(w.sessCtx, row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowRecords = append(w.rowRecords, &reorgPartitionRecord{
+		key:       recordKey,
+		handle:    handle,
+		vals:      append([]byte(nil), rawRow...),
+		row:       row,
+		partition: p,
+	})
+	return nil
+}
+
+// getNextKey gets next handle of entry that we are going to process.
+func (w *reorgPartitionWorker) getNextKey(taskRange reorgBackfillTask,
+	taskDone bool, lastAccessedHandle kv.Key) (nextHandle kv.Key) {
+	if !taskDone {
+		// The task is not done. So we need to pick the last processed entry's handle and add one.
+		return lastAccessedHandle.Next()
+	}
+
+	return taskRange.endKey.Next()
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the reorged handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	return w.rowRecords, w.getNextKey(taskRange, taskDone, lastAccessedHandle), taskDone, errors.Trace(err)
+}
+
+// BackfillDataInTxn will backfill the rows and the index entries of the new partitions in a transaction.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		isCommonHandle := w.table.Meta().IsCommonHandle
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+
+			// Lock the record in the old partition, so the DML which changes the record concurrently
+			// conflicts with the backfilling. The DML applies its change to the new partition itself.
+			err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = txn.Set(tablecodec.EncodeRecordKey(record.partition.RecordPrefix(), record.handle), record.vals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, idx := range record.partition.Indices() {
+				if isCommonHandle && idx.Meta().Primary {
+					continue
+				}
+				idxVals, err := idx.FetchValues(record.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				rsData := tables.TryGetHandleRestoredDataWrapper(record.partition, record.row, nil, idx.Meta())
+				if _, err = idx.Create(w.sessCtx, txn, idxVals, record.handle, rsData); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "BackfillDataInTxn", 3000)
+
+	return
+}
+
 // onTruncateTablePartition truncates old partition meta.
 func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
 	var ver int64
@@ -1497,23 +2154,31 @@ func getPartitionIDs(table *model.TableInfo) []int64 {
 }
 
 // checkPartitioningKeysConstraints checks that the range partitioning key is included in the table constraint.
-func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTableStmt, tblInfo *model.TableInfo) error {
+func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.PartitionOptions, tblInfo *model.TableInfo) error {
 	// Returns directly if there are no unique keys in the table.
 	if len(tblInfo.Indices) == 0 && !tblInfo.PKIsHandle {
 		return nil
 	}
 
 	var partCols stringSlice
-	if s.Partition.Expr != nil {
+	var keyIndex *model.IndexInfo
+	if s.Expr != nil {
 		extractCols := newPartitionExprChecker(sctx, tblInfo)
-		s.Partition.Expr.Accept(extractCols)
+		s.Expr.Accept(extractCols)
 		partColumns, err := extractCols.columns, extractCols.err
 		if err != nil {
 			return err
 		}
 		partCols = columnInfoSlice(partColumns)
-	} else if len(s.Partition.ColumnNames) > 0 {
-		partCols = columnNameSlice(s.Partition.ColumnNames)
+	} else if s.Tp == model.PartitionTypeKey {
+		partCols = ciStrSlice(tblInfo.Partition.Columns)
+		if len(s.ColumnNames) == 0 {
+			// The columns of 'PARTITION BY KEY()' are taken from the primary key or a unique key,
+			// so the key itself always includes them.
+			keyIndex = getDefaultKeyPartitionIndex(tblInfo)
+		}
+	} else if len(s.ColumnNames) > 0 {
+		partCols = columnNameSlice(s.ColumnNames)
 	} else {
 		// TODO: Check keys constraints for list, key partition type and so on.
 		return nil
@@ -1523,6 +2188,9 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 	// Every unique key on the table must use every column in the table's partitioning expression.
 	// See https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations-partitioning-keys-unique-keys.html
 	for _, index := range tblInfo.Indices {
+		if index == keyIndex {
+			continue
+		}
 		if index.Unique && !checkUniqueKeyIncludePartKey(partCols, index.Columns) {
 			if index.Primary {
 				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
@@ -1674,6 +2342,17 @@ func (cns columnNameSlice) At(i int) string {
 	return cns[i].Name.L
 }
 
+// ciStrSlice implements the stringSlice interface.
+type ciStrSlice []model.CIStr
+
+func (css ciStrSlice) Len() int {
+	return len(css)
+}
+
+func (css ciStrSlice) At(i int) string {
+	return css[i].L
+}
+
 // isColUnsigned returns true if the partitioning key column is unsigned.
 func isColUnsigned(cols []*model.ColumnInfo, pi *model.PartitionInfo) bool {
 	for _, col := range cols {
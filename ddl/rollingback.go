@@ -151,8 +151,56 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, errCancelledDDLJob
 }
 
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
+		if job.SnapshotVer != 0 {
+			// The reorg workers are started, we have to ask them to exit.
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			// Give the this kind of ddl one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+			return w.onReorganizePartition(d, t, job)
+		}
+	case model.StateDeleteReorganization:
+		// The new partitions have replaced the old ones, the job couldn't be cancelled.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	// The job has been in its middle state (but the reorg worker hasn't started) and we roll it back here.
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
+// rollbackReorganizePartition removes the adding partitions of the reorganize partition jobs,
+// the data of the adding partitions will be deleted by delete-range.
+func rollbackReorganizePartition(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.Partition
+	addingIDs := getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+	pi.AddingDefinitions, pi.DroppingDefinitions, pi.States = nil, nil, nil
+	if job.Type == model.ActionAlterTablePartitioning && isCollapsedPartition(tblInfo) {
+		// The table wasn't partitioned.
+		tblInfo.Partition = nil
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	job.Args = []interface{}{addingIDs}
+	return ver, nil
+}
+
 func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, _, _, _, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -433,12 +481,15 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition:
+		model.ActionExchangeTablePartition, model.ActionAddCheckConstraint,
+		model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
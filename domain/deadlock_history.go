@@ -0,0 +1,99 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// DeadlockHistoryPersistLoop creates a goroutine that persists the deadlock events of this instance to
+// mysql.deadlock_history periodically, it should be called only once in BootstrapSession if the persistence
+// is enabled.
+func (do *Domain) DeadlockHistoryPersistLoop(ctx sessionctx.Context) {
+	interval := time.Duration(config.GetGlobalConfig().PessimisticTxn.DeadlockHistoryPersistInterval) * time.Second
+	// The IDs of deadlock records are allocated from 1 after restarting, and the history is empty then.
+	var lastPersistedID uint64
+	do.persistLoop("DeadlockHistoryPersistLoop", ctx, interval, func(ctx sessionctx.Context) (err error) {
+		lastPersistedID, err = do.PersistDeadlockHistory(ctx, deadlockhistory.GlobalDeadlockHistory, lastPersistedID)
+		return err
+	})
+}
+
+// PersistDeadlockHistory writes the deadlock records in the history whose IDs are greater than lastPersistedID
+// to mysql.deadlock_history, and removes the persisted ones which are out of retention. It returns the max ID of
+// the persisted records.
+func (do *Domain) PersistDeadlockHistory(ctx sessionctx.Context, history *deadlockhistory.DeadlockHistory, lastPersistedID uint64) (uint64, error) {
+	instanceAddr, err := getInstanceAddr()
+	if err != nil {
+		return lastPersistedID, err
+	}
+	var rows [][]interface{}
+	maxID := lastPersistedID
+	for _, rec := range history.GetAll() {
+		if rec.ID <= lastPersistedID {
+			continue
+		}
+		if rec.ID > maxID {
+			maxID = rec.ID
+		}
+		chainKey := rec.ChainKey()
+		occurTime := types.NewTime(types.FromGoTime(rec.OccurTime), mysql.TypeTimestamp, types.MaxFsp).String()
+		for _, item := range rec.WaitChain {
+			rows = append(rows, []interface{}{
+				instanceAddr,
+				rec.ID,
+				occurTime,
+				rec.IsRetryable,
+				item.TryLockTxn,
+				emptyToNil(item.SQLDigest),
+				emptyToNil(strings.ToUpper(hex.EncodeToString(item.Key))),
+				item.TxnHoldingLock,
+				emptyToNil(strings.ToUpper(hex.EncodeToString(item.ResourceGroupTag))),
+				chainKey,
+			})
+		}
+	}
+
+	// All the records are written in one transaction, so they won't be persisted twice if it fails halfway.
+	err = insertInTxn(ctx, "deadlock_history", []string{"INSTANCE", "DEADLOCK_ID", "OCCUR_TIME", "RETRYABLE", "TRY_LOCK_TRX_ID",
+		"CURRENT_SQL_DIGEST", "KEY", "TRX_HOLDING_LOCK", "RESOURCE_GROUP_TAG", "DEADLOCK_KEY"}, rows)
+	if err != nil {
+		return lastPersistedID, err
+	}
+
+	retention := time.Duration(config.GetGlobalConfig().PessimisticTxn.DeadlockHistoryPersistRetentionDays) * 24 * time.Hour
+	expiredTime := time.Now().Add(-retention).Format(types.TimeFormat)
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	if err = execRestrictedSQL(exec, "DELETE FROM mysql.deadlock_history WHERE OCCUR_TIME < %?", expiredTime); err != nil {
+		// The records have been persisted, so they won't be persisted again.
+		return maxID, err
+	}
+	return maxID, nil
+}
+
+func emptyToNil(s string) interface{} {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
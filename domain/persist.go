@@ -0,0 +1,135 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// persistBatchSize is the max number of rows inserted by one statement when persisting in-memory records.
+const persistBatchSize = 100
+
+// persistLoop creates a goroutine named `name` that calls persist every interval until the domain exits.
+// ctx is used by persist only, and it should not be shared with others.
+func (do *Domain) persistLoop(name string, ctx sessionctx.Context, interval time.Duration, persist func(ctx sessionctx.Context) error) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info(name + " exited.")
+			util.Recover(metrics.LabelDomain, name, nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(interval):
+				if err := persist(ctx); err != nil {
+					logutil.BgLogger().Warn("persist failed", zap.String("name", name), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// insertInTxn inserts the rows into the table in one transaction, so either all or none of them are persisted.
+func insertInTxn(ctx sessionctx.Context, table string, cols []string, rows [][]interface{}) (err error) {
+	if len(rows) == 0 {
+		return nil
+	}
+	exec := ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(context.TODO(), "BEGIN")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_, err1 := exec.ExecuteInternal(context.TODO(), "ROLLBACK")
+			terror.Log(err1)
+			return
+		}
+		_, err = exec.ExecuteInternal(context.TODO(), "COMMIT")
+	}()
+
+	for len(rows) > 0 {
+		batch := rows
+		if len(batch) > persistBatchSize {
+			batch = batch[:persistBatchSize]
+		}
+		rows = rows[len(batch):]
+
+		var sql strings.Builder
+		args := make([]interface{}, 0, len(cols)*(len(batch)+1)+1)
+		sql.WriteString("INSERT INTO mysql.%n (")
+		args = append(args, table)
+		for i, col := range cols {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("%n")
+			args = append(args, col)
+		}
+		sql.WriteString(") VALUES ")
+		for i, row := range batch {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("(")
+			for j := range row {
+				if j > 0 {
+					sql.WriteString(", ")
+				}
+				sql.WriteString("%?")
+			}
+			sql.WriteString(")")
+			args = append(args, row...)
+		}
+		if _, err = exec.ExecuteInternal(context.TODO(), sql.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getInstanceAddr returns the address of this instance, which is the same as the INSTANCE column of cluster tables.
+func getInstanceAddr() (string, error) {
+	serverInfo, err := infosync.GetServerInfo()
+	if err != nil {
+		return "", err
+	}
+	return serverInfo.IP + ":" + strconv.FormatUint(uint64(serverInfo.StatusPort), 10), nil
+}
+
+func execRestrictedSQL(exec sqlexec.RestrictedSQLExecutor, sql string, args ...interface{}) error {
+	stmt, err := exec.ParseWithParams(context.Background(), sql, args...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err = exec.ExecRestrictedStmt(context.Background(), stmt)
+	return errors.Trace(err)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
+)
+
+// StmtSummaryPersistLoop creates a goroutine that persists the expired statement summaries of this instance
+// to mysql.statements_summary_history periodically, it should be called only once in BootstrapSession
+// if the persistence is enabled.
+func (do *Domain) StmtSummaryPersistLoop(ctx sessionctx.Context) {
+	interval := time.Duration(config.GetGlobalConfig().StmtSummary.PersistInterval) * time.Second
+	// The statement summaries are kept in memory, so nothing is left to persist after restarting.
+	var lastPersistTime int64
+	do.persistLoop("StmtSummaryPersistLoop", ctx, interval, func(ctx sessionctx.Context) (err error) {
+		lastPersistTime, err = do.PersistStmtSummary(ctx, lastPersistTime, time.Now().Unix())
+		return err
+	})
+}
+
+// PersistStmtSummary writes the statement summaries of this instance which expired in (after, before]
+// to mysql.statements_summary_history, and removes the persisted ones which are out of retention.
+// The summaries of each interval are written in one transaction, and it returns the end time of the last
+// persisted interval, so the summaries won't be persisted twice if it fails halfway.
+func (do *Domain) PersistStmtSummary(ctx sessionctx.Context, after, before int64) (int64, error) {
+	instanceAddr, err := getInstanceAddr()
+	if err != nil {
+		return after, err
+	}
+	cols := infoschema.GetMemTableColumns(infoschema.ClusterTableStatementsSummaryHistory)
+	colNames := make([]string, 0, len(cols))
+	for _, col := range cols {
+		colNames = append(colNames, col.Name.O)
+	}
+	reader := stmtsummary.NewStmtSummaryReader(nil, true, cols, instanceAddr)
+	rowsByEndTime := reader.GetExpiredStmtSummaryHistoryRows(after, before)
+	endTimes := make([]int64, 0, len(rowsByEndTime))
+	for endTime := range rowsByEndTime {
+		endTimes = append(endTimes, endTime)
+	}
+	sort.Slice(endTimes, func(i, j int) bool { return endTimes[i] < endTimes[j] })
+
+	persisted := after
+	for _, endTime := range endTimes {
+		rows := make([][]interface{}, 0, len(rowsByEndTime[endTime]))
+		for _, datums := range rowsByEndTime[endTime] {
+			row := make([]interface{}, 0, len(datums))
+			for i := range datums {
+				row = append(row, persistedStmtSummaryValue(&datums[i]))
+			}
+			rows = append(rows, row)
+		}
+		if err = insertInTxn(ctx, "statements_summary_history", colNames, rows); err != nil {
+			return persisted, err
+		}
+		persisted = endTime
+	}
+
+	retention := time.Duration(config.GetGlobalConfig().StmtSummary.PersistRetentionDays) * 24 * time.Hour
+	expiredTime := time.Unix(before, 0).Add(-retention).Format(types.TimeFormat)
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	if err = execRestrictedSQL(exec, "DELETE FROM mysql.statements_summary_history WHERE SUMMARY_END_TIME < %?", expiredTime); err != nil {
+		// The summaries have been persisted, so they won't be persisted again.
+		return before, err
+	}
+	return before, nil
+}
+
+// persistedStmtSummaryValue converts the datum read from statement summary to an argument of sqlexec.EscapeSQL.
+func persistedStmtSummaryValue(d *types.Datum) interface{} {
+	if d.Kind() == types.KindMysqlTime {
+		return d.GetMysqlTime().String()
+	}
+	return d.GetValue()
+}
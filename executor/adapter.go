@@ -995,6 +995,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		Succ:              succ,
 		Plan:              getPlanTree(a.Ctx, a.Plan),
 		PlanDigest:        planDigest.String(),
+		QueryAttributes:   sessVars.QueryAttributes,
 		Prepared:          a.isPreparedStmt,
 		HasMoreResults:    hasMoreResults,
 		PlanFromCache:     sessVars.FoundInPlanCache,
@@ -1015,10 +1016,14 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	if trace.IsEnabled() {
 		trace.Log(a.GoCtx, "details", sessVars.SlowLogFormat(slowItems))
 	}
+	slowLogFormat := sessVars.SlowLogFormat
+	if cfg.Log.SlowQueryFormat == logutil.SlowLogFormatJSON {
+		slowLogFormat = sessVars.SlowLogJSONFormat
+	}
 	if costTime < threshold {
-		logutil.SlowQueryLogger.Debug(sessVars.SlowLogFormat(slowItems))
+		logutil.SlowQueryLogger.Debug(slowLogFormat(slowItems))
 	} else {
-		logutil.SlowQueryLogger.Warn(sessVars.SlowLogFormat(slowItems))
+		logutil.SlowQueryLogger.Warn(slowLogFormat(slowItems))
 		if sessVars.InRestrictedSQL {
 			totalQueryProcHistogramInternal.Observe(costTime.Seconds())
 			totalCopProcHistogramInternal.Observe(execDetail.TimeDetail.ProcessTime.Seconds())
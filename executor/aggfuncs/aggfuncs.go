@@ -93,6 +93,7 @@ var (
 	// All the AggFunc implementations for "GROUP_CONCAT" are listed here.
 	_ AggFunc = (*groupConcatDistinct)(nil)
 	_ AggFunc = (*groupConcat)(nil)
+	_ AggFunc = (*groupConcatSliding)(nil)
 
 	// All the AggFunc implementations for "BIT_OR" are listed here.
 	_ AggFunc = (*bitOrUint64)(nil)
@@ -105,6 +106,10 @@ var (
 
 	// All the AggFunc implementations for "JSON_OBJECTAGG" are listed here
 	_ AggFunc = (*jsonObjectAgg)(nil)
+	_ AggFunc = (*jsonObjectAggSliding)(nil)
+
+	// All the AggFunc implementations for "JSON_ARRAYAGG" are listed here
+	_ AggFunc = (*jsonArrayagg)(nil)
 )
 
 const (
@@ -35,6 +35,10 @@ import (
 const (
 	// DefPartialResult4GroupConcatSize is the size of partialResult4GroupConcat
 	DefPartialResult4GroupConcatSize = int64(unsafe.Sizeof(partialResult4GroupConcat{}))
+	// DefPartialResult4GroupConcatSlidingSize is the size of partialResult4GroupConcatSliding
+	DefPartialResult4GroupConcatSlidingSize = int64(unsafe.Sizeof(partialResult4GroupConcatSliding{}))
+	// DefGroupConcatValueSize is the size of groupConcatValue
+	DefGroupConcatValueSize = int64(unsafe.Sizeof(groupConcatValue{}))
 	// DefPartialResult4GroupConcatDistinctSize is the size of partialResult4GroupConcatDistinct
 	DefPartialResult4GroupConcatDistinctSize = int64(unsafe.Sizeof(partialResult4GroupConcatDistinct{}))
 	// DefPartialResult4GroupConcatOrderSize is the size of partialResult4GroupConcatOrder
@@ -183,6 +187,156 @@ func (e *groupConcat) GetTruncated() *int32 {
 	return e.truncated
 }
 
+type groupConcatValue struct {
+	val    string
+	isNull bool
+	// byItems are the values of the ORDER BY items of GROUP_CONCAT.
+	byItems []types.Datum
+}
+
+type partialResult4GroupConcatSliding struct {
+	// vals are the values of the rows in the frame, in the order of the rows.
+	vals    []groupConcatValue
+	valsBuf *bytes.Buffer
+}
+
+// groupConcatSliding is used by the window function GROUP_CONCAT without DISTINCT. It keeps the value of
+// every row in the frame rather than the concatenated result, so that the leading values can be dropped
+// when the frame slides. The values are sorted by the ORDER BY items when the final result is built.
+type groupConcatSliding struct {
+	baseGroupConcat4String
+}
+
+func (e *groupConcatSliding) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	p := &partialResult4GroupConcatSliding{valsBuf: &bytes.Buffer{}}
+	return PartialResult(p), DefPartialResult4GroupConcatSlidingSize + DefBytesBufferSize
+}
+
+func (e *groupConcatSliding) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	p.vals = p.vals[:0]
+}
+
+func (e *groupConcatSliding) evalValue(sctx sessionctx.Context, row chunk.Row, valsBuf *bytes.Buffer) (groupConcatValue, error) {
+	valsBuf.Reset()
+	for _, arg := range e.args {
+		v, isNull, err := arg.EvalString(sctx, row)
+		if err != nil {
+			return groupConcatValue{}, err
+		}
+		if isNull {
+			return groupConcatValue{isNull: true}, nil
+		}
+		valsBuf.WriteString(v)
+	}
+	value := groupConcatValue{val: valsBuf.String()}
+	if len(e.byItems) > 0 {
+		value.byItems = make([]types.Datum, 0, len(e.byItems))
+		for _, byItem := range e.byItems {
+			d, err := byItem.Expr.Eval(row)
+			if err != nil {
+				return groupConcatValue{}, err
+			}
+			value.byItems = append(value.byItems, *d.Clone())
+		}
+	}
+	return value, nil
+}
+
+// sortedValues returns the non-null values in the frame, sorted by the ORDER BY items.
+// The values with equal ORDER BY items are kept in the order of the rows.
+func (e *groupConcatSliding) sortedValues(sctx sessionctx.Context, vals []groupConcatValue) ([]groupConcatValue, error) {
+	sorted := make([]groupConcatValue, 0, len(vals))
+	for _, v := range vals {
+		if !v.isNull {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(e.byItems) == 0 {
+		return sorted, nil
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	var err error
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for k, byItem := range e.byItems {
+			ret, err1 := sorted[i].byItems[k].CompareDatum(sc, &sorted[j].byItems[k])
+			if err1 != nil {
+				err = err1
+				return false
+			}
+			if byItem.Desc {
+				ret = -ret
+			}
+			if ret != 0 {
+				return ret < 0
+			}
+		}
+		return false
+	})
+	return sorted, err
+}
+
+func (e *groupConcatSliding) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	for _, row := range rowsInGroup {
+		v, err := e.evalValue(sctx, row, p.valsBuf)
+		if err != nil {
+			return memDelta, err
+		}
+		p.vals = append(p.vals, v)
+		memDelta += DefGroupConcatValueSize + int64(len(v.val))
+		for i := range v.byItems {
+			memDelta += GetDatumMemSize(&v.byItems[i])
+		}
+	}
+	return memDelta, nil
+}
+
+var _ SlidingWindowAggFunc = &groupConcatSliding{}
+
+func (e *groupConcatSliding) Slide(sctx sessionctx.Context, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		v, err := e.evalValue(sctx, getRow(lastEnd+i), p.valsBuf)
+		if err != nil {
+			return err
+		}
+		p.vals = append(p.vals, v)
+	}
+	// Every row has a value, and the frame may move past the rows which are never added when it is empty.
+	if shiftStart > uint64(len(p.vals)) {
+		shiftStart = uint64(len(p.vals))
+	}
+	p.vals = p.vals[shiftStart:]
+	return nil
+}
+
+func (e *groupConcatSliding) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcatSliding)(pr)
+	vals, err := e.sortedValues(sctx, p.vals)
+	if err != nil {
+		return err
+	}
+	var buffer *bytes.Buffer
+	for _, v := range vals {
+		if buffer == nil {
+			buffer = &bytes.Buffer{}
+		} else {
+			buffer.WriteString(e.sep)
+		}
+		buffer.WriteString(v.val)
+	}
+	if buffer == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	if err := e.truncatePartialResultIfNeed(sctx, buffer); err != nil {
+		return err
+	}
+	chk.AppendString(e.ordinal, buffer.String())
+	return nil
+}
+
 type partialResult4GroupConcatDistinct struct {
 	basePartialResult4GroupConcat
 	valSet            set.StringSetWithMemoryUsage
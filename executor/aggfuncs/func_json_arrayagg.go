@@ -0,0 +1,80 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"unsafe"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// DefPartialResult4JsonArrayagg is the size of partialResult4JsonArrayagg
+const DefPartialResult4JsonArrayagg = int64(unsafe.Sizeof(partialResult4JsonArrayagg{}))
+
+type partialResult4JsonArrayagg struct {
+	// entries holds the aggregated elements, a nil element stands for the JSON null literal.
+	entries []interface{}
+}
+
+type jsonArrayagg struct {
+	baseAggFunc
+}
+
+func (e *jsonArrayagg) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	p := partialResult4JsonArrayagg{}
+	return PartialResult(&p), DefPartialResult4JsonArrayagg
+}
+
+func (e *jsonArrayagg) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4JsonArrayagg)(pr)
+	p.entries = nil
+}
+
+func (e *jsonArrayagg) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4JsonArrayagg)(pr)
+	if len(p.entries) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendJSON(e.ordinal, json.CreateBinary(p.entries))
+	return nil
+}
+
+func (e *jsonArrayagg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4JsonArrayagg)(pr)
+	for _, row := range rowsInGroup {
+		// The argument has been wrapped with a cast as json function, so NULL is
+		// collected as the JSON null literal just like JSON_ARRAY does.
+		val, isNull, err := e.args[0].EvalJSON(sctx, row)
+		if err != nil {
+			return memDelta, errors.Trace(err)
+		}
+		var entry interface{}
+		if !isNull {
+			entry = val.Copy()
+		}
+		p.entries = append(p.entries, entry)
+		memDelta += getValMemDelta(entry)
+	}
+	return memDelta, nil
+}
+
+func (e *jsonArrayagg) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
+	p1, p2 := (*partialResult4JsonArrayagg)(src), (*partialResult4JsonArrayagg)(dst)
+	p2.entries = append(p2.entries, p1.entries...)
+	return 0, nil
+}
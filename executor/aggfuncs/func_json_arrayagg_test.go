@@ -0,0 +1,58 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testSuite) TestMergePartialResult4JsonArrayagg(c *C) {
+	numRows := 5
+	var entries1, entries2 []interface{}
+	for i := 0; i < numRows; i++ {
+		entries1 = append(entries1, int64(i))
+		if i >= 2 {
+			entries2 = append(entries2, int64(i))
+		}
+	}
+	// The last row of the source chunk is NULL, which is aggregated as the JSON null literal.
+	entries1 = append(entries1, nil)
+	entries2 = append(entries2, nil)
+	entries3 := append(append([]interface{}{}, entries1...), entries2...)
+
+	test := buildAggTester(ast.AggFuncJsonArrayagg, mysql.TypeJSON, numRows, json.CreateBinary(entries1), json.CreateBinary(entries2), json.CreateBinary(entries3))
+	s.testMergePartialResult(c, test)
+}
+
+func (s *testSuite) TestMemJsonArrayagg(c *C) {
+	memDeltaGens := func(srcChk *chunk.Chunk, dataType *types.FieldType) (memDeltas []int64, err error) {
+		for i := 0; i < srcChk.NumRows(); i++ {
+			memDelta := aggfuncs.DefInterfaceSize
+			if row := srcChk.GetRow(i); !row.IsNull(0) {
+				// +1 for the memory usage of the TypeCode of json
+				memDelta += int64(len(row.GetJSON(0).Value) + 1)
+			}
+			memDeltas = append(memDeltas, memDelta)
+		}
+		return memDeltas, nil
+	}
+	test := buildAggMemTester(ast.AggFuncJsonArrayagg, mysql.TypeJSON, 5, aggfuncs.DefPartialResult4JsonArrayagg, memDeltaGens, false)
+	s.testAggMemFunc(c, test)
+}
@@ -16,6 +16,7 @@ package aggfuncs
 import (
 	"unsafe"
 
+	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -28,6 +29,8 @@ import (
 const (
 	// DefPartialResult4JsonObjectAgg is the size of partialResult4JsonObject
 	DefPartialResult4JsonObjectAgg = int64(unsafe.Sizeof(partialResult4JsonObjectAgg{}))
+	// DefPartialResult4JsonObjectAggSliding is the size of partialResult4JsonObjectAggSliding
+	DefPartialResult4JsonObjectAggSliding = int64(unsafe.Sizeof(partialResult4JsonObjectAggSliding{}))
 	// DefMapStringInterfaceBucketSize = bucketSize*(1+unsafe.Sizeof(string) + unsafe.Sizeof(interface{}))+2*ptrSize
 	DefMapStringInterfaceBucketSize = 8*(1+16+16) + 16
 )
@@ -61,66 +64,80 @@ func (e *jsonObjectAgg) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Part
 		return nil
 	}
 
+	return appendJSONObject(chk, e.ordinal, p.entries)
+}
+
+// appendJSONObject creates a JSON object from the entries and appends it to the chunk.
+func appendJSONObject(chk *chunk.Chunk, ordinal int, entries map[string]interface{}) error {
 	// appendBinary does not support some type such as uint8、types.time，so convert is needed here
-	for key, val := range p.entries {
+	for key, val := range entries {
 		switch x := val.(type) {
 		case *types.MyDecimal:
 			float64Val, err := x.ToFloat64()
 			if err != nil {
 				return errors.Trace(err)
 			}
-			p.entries[key] = float64Val
+			entries[key] = float64Val
 		case []uint8, types.Time, types.Duration:
 			strVal, err := types.ToString(x)
 			if err != nil {
 				return errors.Trace(err)
 			}
-			p.entries[key] = strVal
+			entries[key] = strVal
 		}
 	}
 
-	chk.AppendJSON(e.ordinal, json.CreateBinary(p.entries))
+	chk.AppendJSON(ordinal, json.CreateBinary(entries))
 	return nil
 }
 
-func (e *jsonObjectAgg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4JsonObjectAgg)(pr)
-	for _, row := range rowsInGroup {
-		key, err := e.args[0].Eval(row)
-		if err != nil {
-			return 0, errors.Trace(err)
-		}
+// evalEntry evaluates the key and the value of the JSON object entry on the row.
+func (e *jsonObjectAgg) evalEntry(row chunk.Row) (string, interface{}, error) {
+	key, err := e.args[0].Eval(row)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
 
-		value, err := e.args[1].Eval(row)
-		if err != nil {
-			return 0, errors.Trace(err)
-		}
+	value, err := e.args[1].Eval(row)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
 
-		if key.IsNull() {
-			return 0, json.ErrJSONDocumentNULLKey
-		}
+	if key.IsNull() {
+		return "", nil, json.ErrJSONDocumentNULLKey
+	}
 
-		// the result json's key is string, so it needs to convert the first arg to string
-		keyString, err := key.ToString()
+	// the result json's key is string, so it needs to convert the first arg to string
+	keyString, err := key.ToString()
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	keyString = stringutil.Copy(keyString)
+
+	realVal := value.Clone().GetValue()
+	switch x := realVal.(type) {
+	case nil, bool, int64, uint64, float64, string, json.BinaryJSON, *types.MyDecimal, []uint8, types.Time, types.Duration:
+		return keyString, realVal, nil
+	default:
+		return "", nil, json.ErrUnsupportedSecondArgumentType.GenWithStackByArgs(x)
+	}
+}
+
+func (e *jsonObjectAgg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4JsonObjectAgg)(pr)
+	for _, row := range rowsInGroup {
+		keyString, realVal, err := e.evalEntry(row)
 		if err != nil {
-			return 0, errors.Trace(err)
+			return 0, err
 		}
-		keyString = stringutil.Copy(keyString)
-
-		realVal := value.Clone().GetValue()
-		switch x := realVal.(type) {
-		case nil, bool, int64, uint64, float64, string, json.BinaryJSON, *types.MyDecimal, []uint8, types.Time, types.Duration:
-			if _, ok := p.entries[keyString]; !ok {
-				memDelta += int64(len(keyString)) + getValMemDelta(realVal)
-				if len(p.entries)+1 > (1<<p.bInMap)*hack.LoadFactorNum/hack.LoadFactorDen {
-					memDelta += (1 << p.bInMap) * DefMapStringInterfaceBucketSize
-					p.bInMap++
-				}
+		if _, ok := p.entries[keyString]; !ok {
+			memDelta += int64(len(keyString)) + getValMemDelta(realVal)
+			if len(p.entries)+1 > (1<<p.bInMap)*hack.LoadFactorNum/hack.LoadFactorDen {
+				memDelta += (1 << p.bInMap) * DefMapStringInterfaceBucketSize
+				p.bInMap++
 			}
-			p.entries[keyString] = realVal
-		default:
-			return 0, json.ErrUnsupportedSecondArgumentType.GenWithStackByArgs(x)
 		}
+		p.entries[keyString] = realVal
 	}
 	return memDelta, nil
 }
@@ -167,3 +184,71 @@ func (e *jsonObjectAgg) MergePartialResult(sctx sessionctx.Context, src, dst Par
 	}
 	return 0, nil
 }
+
+type jsonObjectEntry struct {
+	key string
+	val interface{}
+}
+
+type partialResult4JsonObjectAggSliding struct {
+	// entries are the entries of the rows in the frame, in the order of the rows.
+	entries []jsonObjectEntry
+}
+
+// jsonObjectAggSliding is used by the window function JSON_OBJECTAGG. It keeps the entry of every
+// row in the frame, so that the leading entries can be dropped when the frame slides.
+type jsonObjectAggSliding struct {
+	jsonObjectAgg
+}
+
+func (e *jsonObjectAggSliding) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	return PartialResult(&partialResult4JsonObjectAggSliding{}), DefPartialResult4JsonObjectAggSliding
+}
+
+func (e *jsonObjectAggSliding) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4JsonObjectAggSliding)(pr)
+	p.entries = p.entries[:0]
+}
+
+func (e *jsonObjectAggSliding) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4JsonObjectAggSliding)(pr)
+	for _, row := range rowsInGroup {
+		key, val, err := e.evalEntry(row)
+		if err != nil {
+			return 0, err
+		}
+		p.entries = append(p.entries, jsonObjectEntry{key: key, val: val})
+		memDelta += int64(len(key)) + getValMemDelta(val)
+	}
+	return memDelta, nil
+}
+
+var _ SlidingWindowAggFunc = &jsonObjectAggSliding{}
+
+func (e *jsonObjectAggSliding) Slide(sctx sessionctx.Context, getRow func(uint64) chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4JsonObjectAggSliding)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		key, val, err := e.evalEntry(getRow(lastEnd + i))
+		if err != nil {
+			return err
+		}
+		p.entries = append(p.entries, jsonObjectEntry{key: key, val: val})
+	}
+	// Every row has an entry, and the frame may move past the rows which are never added when it is empty.
+	p.entries = p.entries[mathutil.MinUint64(shiftStart, uint64(len(p.entries))):]
+	return nil
+}
+
+func (e *jsonObjectAggSliding) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4JsonObjectAggSliding)(pr)
+	if len(p.entries) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	// The values having duplicate keys are discarded, and only the last one in the frame is used.
+	entries := make(map[string]interface{}, len(p.entries))
+	for _, entry := range p.entries {
+		entries[entry.key] = entry.val
+	}
+	return appendJSONObject(chk, e.ordinal, entries)
+}
@@ -14,6 +14,7 @@
 package aggfuncs
 
 import (
+	"sort"
 	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
@@ -32,11 +33,15 @@ type baseLeadLag struct {
 
 	defaultExpr expression.Expression
 	offset      uint64
+	ignoreNull  bool
 }
 
 type partialResult4LeadLag struct {
 	rows   []chunk.Row
 	curIdx uint64
+	// nonNullIdxs records the indexes of the rows whose argument is not NULL, it is only
+	// used when IGNORE NULLS is specified.
+	nonNullIdxs []uint64
 }
 
 func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -47,15 +52,54 @@ func (v *baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4LeadLag)(pr)
 	p.rows = p.rows[:0]
 	p.curIdx = 0
+	p.nonNullIdxs = p.nonNullIdxs[:0]
 }
 
 func (v *baseLeadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
+	if v.ignoreNull {
+		for i, row := range rowsInGroup {
+			isNull, err := isNullValue(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if !isNull {
+				p.nonNullIdxs = append(p.nonNullIdxs, uint64(len(p.rows)+i))
+				memDelta += DefUint64Size
+			}
+		}
+	}
 	p.rows = append(p.rows, rowsInGroup...)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+// findRowWithOffset returns the index of the row which is offset rows after (or before if
+// preceding is true) the current row, the rows whose argument is NULL are skipped if IGNORE NULLS
+// is specified. ok is false if there is no such row in the partition.
+func (v *baseLeadLag) findRowWithOffset(p *partialResult4LeadLag, preceding bool) (idx uint64, ok bool) {
+	if !v.ignoreNull || v.offset == 0 {
+		if preceding {
+			return p.curIdx - v.offset, p.curIdx >= v.offset
+		}
+		return p.curIdx + v.offset, p.curIdx+v.offset < uint64(len(p.rows))
+	}
+	if preceding {
+		// k is the number of non-NULL rows before the current row.
+		k := uint64(sort.Search(len(p.nonNullIdxs), func(i int) bool { return p.nonNullIdxs[i] >= p.curIdx }))
+		if k < v.offset {
+			return 0, false
+		}
+		return p.nonNullIdxs[k-v.offset], true
+	}
+	// k is the number of non-NULL rows up to and including the current row.
+	k := uint64(sort.Search(len(p.nonNullIdxs), func(i int) bool { return p.nonNullIdxs[i] > p.curIdx }))
+	if k+v.offset-1 >= uint64(len(p.nonNullIdxs)) {
+		return 0, false
+	}
+	return p.nonNullIdxs[k+v.offset-1], true
+}
+
 type lead struct {
 	baseLeadLag
 }
@@ -63,8 +107,8 @@ type lead struct {
 func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx+v.offset < uint64(len(p.rows)) {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
+	if idx, ok := v.findRowWithOffset(p, false); ok {
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows[idx])
 	} else {
 		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
 	}
@@ -83,8 +127,8 @@ type lag struct {
 func (v *lag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx >= v.offset {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
+	if idx, ok := v.findRowWithOffset(p, true); ok {
+		_, err = v.evaluateRow(sctx, v.args[0], p.rows[idx])
 	} else {
 		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
 	}
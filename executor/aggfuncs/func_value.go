@@ -237,10 +237,21 @@ func buildValueEvaluator(tp *types.FieldType) (ve valueEvaluator, memDelta int64
 	return nil, 0
 }
 
+// isNullValue checks whether the argument of the window function is NULL on the row,
+// it is used to skip the NULL values when IGNORE NULLS is specified.
+func isNullValue(expr expression.Expression, row chunk.Row) (bool, error) {
+	d, err := expr.Eval(row)
+	if err != nil {
+		return false, err
+	}
+	return d.IsNull(), nil
+}
+
 type firstValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4FirstValue struct {
@@ -264,14 +275,20 @@ func (v *firstValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []
 	if p.gotFirstValue {
 		return 0, nil
 	}
-	if len(rowsInGroup) > 0 {
-		p.gotFirstValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[0])
-		if err != nil {
-			return 0, err
+	for _, row := range rowsInGroup {
+		if v.ignoreNull {
+			isNull, err := isNullValue(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
 		}
+		p.gotFirstValue = true
+		return p.evaluator.evaluateRow(sctx, v.args[0], row)
 	}
-	return memDelta, nil
+	return 0, nil
 }
 
 func (v *firstValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
@@ -287,7 +304,8 @@ func (v *firstValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Partial
 type lastValue struct {
 	baseAggFunc
 
-	tp *types.FieldType
+	tp         *types.FieldType
+	ignoreNull bool
 }
 
 type partialResult4LastValue struct {
@@ -297,7 +315,7 @@ type partialResult4LastValue struct {
 
 func (v *lastValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4LastValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4LastValueSize + veMemDelta
 }
 
@@ -308,14 +326,20 @@ func (v *lastValue) ResetPartialResult(pr PartialResult) {
 
 func (v *lastValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LastValue)(pr)
-	if len(rowsInGroup) > 0 {
-		p.gotLastValue = true
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[len(rowsInGroup)-1])
-		if err != nil {
-			return 0, err
+	for i := len(rowsInGroup) - 1; i >= 0; i-- {
+		if v.ignoreNull {
+			isNull, err := isNullValue(v.args[0], rowsInGroup[i])
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
 		}
+		p.gotLastValue = true
+		return p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[i])
 	}
-	return memDelta, nil
+	return 0, nil
 }
 
 func (v *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
@@ -331,24 +355,30 @@ func (v *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialR
 type nthValue struct {
 	baseAggFunc
 
-	tp  *types.FieldType
-	nth uint64
+	tp         *types.FieldType
+	nth        uint64
+	ignoreNull bool
+	// fromLast indicates that the rows are counted from the last row of the frame.
+	fromLast bool
 }
 
 type partialResult4NthValue struct {
 	seenRows  uint64
 	evaluator valueEvaluator
+	// lastRows keeps the last nth rows seen so far, it is only used when counting from the last row.
+	lastRows []chunk.Row
 }
 
 func (v *nthValue) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	ve, veMemDelta := buildValueEvaluator(v.tp)
-	p := &partialResult4FirstValue{evaluator: ve}
+	p := &partialResult4NthValue{evaluator: ve}
 	return PartialResult(p), DefPartialResult4NthValueSize + veMemDelta
 }
 
 func (v *nthValue) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4NthValue)(pr)
 	p.seenRows = 0
+	p.lastRows = p.lastRows[:0]
 }
 
 func (v *nthValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
@@ -356,14 +386,43 @@ func (v *nthValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []ch
 		return 0, nil
 	}
 	p := (*partialResult4NthValue)(pr)
-	numRows := uint64(len(rowsInGroup))
-	if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
-		memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
-		if err != nil {
-			return 0, err
+	if !v.ignoreNull && !v.fromLast {
+		numRows := uint64(len(rowsInGroup))
+		if v.nth > p.seenRows && v.nth-p.seenRows <= numRows {
+			memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], rowsInGroup[v.nth-p.seenRows-1])
+			if err != nil {
+				return 0, err
+			}
+		}
+		p.seenRows += numRows
+		return memDelta, nil
+	}
+	for _, row := range rowsInGroup {
+		if v.ignoreNull {
+			isNull, err := isNullValue(v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+			if isNull {
+				continue
+			}
 		}
+		p.seenRows++
+		if v.fromLast {
+			p.lastRows = append(p.lastRows, row)
+			memDelta += DefRowSize
+		} else if p.seenRows == v.nth {
+			memDelta, err = p.evaluator.evaluateRow(sctx, v.args[0], row)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	if n := uint64(len(p.lastRows)); n > v.nth {
+		copy(p.lastRows, p.lastRows[n-v.nth:])
+		p.lastRows = p.lastRows[:v.nth]
+		memDelta -= int64(n-v.nth) * DefRowSize
 	}
-	p.seenRows += numRows
 	return memDelta, nil
 }
 
@@ -371,8 +430,14 @@ func (v *nthValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialRe
 	p := (*partialResult4NthValue)(pr)
 	if v.nth == 0 || p.seenRows < v.nth {
 		chk.AppendNull(v.ordinal)
-	} else {
-		p.evaluator.appendResult(chk, v.ordinal)
+		return nil
+	}
+	if v.fromLast {
+		// lastRows holds exactly the last nth rows, so the first one is the nth row from the last.
+		if _, err := p.evaluator.evaluateRow(sctx, v.args[0], p.lastRows[0]); err != nil {
+			return err
+		}
 	}
+	p.evaluator.appendResult(chk, v.ordinal)
 	return nil
 }
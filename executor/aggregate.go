@@ -26,6 +26,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
@@ -34,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
@@ -93,6 +96,17 @@ type HashAggPartialWorker struct {
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
+
+	// inSpillMode points to HashAggExec.inSpillMode, it is nil if spilling is disabled.
+	inSpillMode *uint32
+	// spilledChunks stores the rows whose groups are not in partialResultsMap after the executor
+	// turns into spill mode, spilledChunks[i] holds the rows which belong to the i-th final worker.
+	spilledChunks []*chunk.ListInDisk
+	// tmpChksForSpill buffers the rows to be spilled into spilledChunks.
+	tmpChksForSpill []*chunk.Chunk
+	// isSpilling indicates whether this worker has observed the spill mode.
+	// Once it is set, it is never reset, so that a group is either aggregated in memory or spilled.
+	isSpilling bool
 }
 
 // HashAggFinalWorker indicates the final workers of parallel hash agg execution,
@@ -108,6 +122,23 @@ type HashAggFinalWorker struct {
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
 	groupKeys           [][]byte
+
+	// inSpillMode points to HashAggExec.inSpillMode, it is nil if spilling is disabled.
+	inSpillMode *uint32
+	// intermDataWaitGroup is done after the worker has consumed all the intermediate data.
+	intermDataWaitGroup *sync.WaitGroup
+	// spillRound synchronizes the rounds of re-aggregating spilled rows among the final workers.
+	spillRound *aggSpillRound
+	// The following fields are used to re-aggregate the spilled rows in several rounds.
+	// In each round, the groups which can not be held in memory are spilled to listInDisk again.
+	groupByItems    []expression.Expression
+	partialAggFuncs []aggfuncs.AggFunc
+	// spilledInput holds the rows spilled by the partial workers which belong to this worker.
+	spilledInput []*chunk.ListInDisk
+	listInDisk   *chunk.ListInDisk
+	// numOfSpilledChks is the number of chunks in listInDisk when the current round starts.
+	numOfSpilledChks int
+	tmpChkForSpill   *chunk.Chunk
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -191,7 +222,27 @@ type HashAggExec struct {
 	prepared                bool
 	executed                bool
 
-	memTracker *memory.Tracker // track memory usage.
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *disk.Tracker   // track disk usage.
+
+	// spillAction save the Action for spilling, it is nil if spilling is disabled.
+	spillAction *AggSpillDiskAction
+	// inSpillMode indicates whether HashAgg is in `spill mode`.
+	// When HashAgg is in `spill mode`, the number of groups held in memory is no longer growing,
+	// and the rows belonging to the other groups are spilled to disk and aggregated in later rounds.
+	inSpillMode uint32
+	// listInDisk is the chunks to store row values for spilled data of unparallel execution.
+	// The HashAggExec may be set to `spill mode` multiple times, and all spilled data will be appended to listInDisk.
+	listInDisk *chunk.ListInDisk
+	// numOfSpilledChks indicates the number of all the spilled chunks when the current round starts.
+	numOfSpilledChks int
+	// offsetOfSpilledChks indicates the offset of the chunk to be read from the disk.
+	// In each round of processing, we need to re-fetch all the chunks spilled in the last one.
+	offsetOfSpilledChks int
+	// tmpChkForSpill is the temp chunk for spilling.
+	tmpChkForSpill *chunk.Chunk
+	// isChildDrained indicates whether all the data from child has been taken out.
+	isChildDrained bool
 
 	stats *HashAggRuntimeStats
 }
@@ -227,13 +278,21 @@ func (d *HashAggIntermData) getPartialResultBatch(sc *stmtctx.StatementContext,
 // Close implements the Executor Close interface.
 func (e *HashAggExec) Close() error {
 	if e.isUnparallelExec {
+		var firstErr error
 		e.childResult = nil
 		e.groupSet, _ = set.NewStringSetWithMemoryUsage()
 		e.partialResultMap = nil
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
-		return e.baseExecutor.Close()
+		if e.listInDisk != nil {
+			firstErr = e.listInDisk.Close()
+		}
+		e.tmpChkForSpill = nil
+		if err := e.baseExecutor.Close(); firstErr == nil {
+			firstErr = err
+		}
+		return firstErr
 	}
 	if e.parallelExecInitialized {
 		// `Close` may be called after `Open` without calling `Next` in test.
@@ -262,10 +321,27 @@ func (e *HashAggExec) Close() error {
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
+		e.closeSpilledChunks()
 	}
 	return e.baseExecutor.Close()
 }
 
+// closeSpilledChunks releases the disk resources used by the workers of parallel execution.
+func (e *HashAggExec) closeSpilledChunks() {
+	for i := range e.partialWorkers {
+		for _, l := range e.partialWorkers[i].spilledChunks {
+			terror.Call(l.Close)
+		}
+		e.partialWorkers[i].spilledChunks = nil
+	}
+	for i := range e.finalWorkers {
+		if l := e.finalWorkers[i].listInDisk; l != nil {
+			terror.Call(l.Close)
+		}
+		e.finalWorkers[i].listInDisk = nil
+	}
+}
+
 // Open implements the Executor Open interface.
 func (e *HashAggExec) Open(ctx context.Context) error {
 	if err := e.baseExecutor.Open(ctx); err != nil {
@@ -282,6 +358,16 @@ func (e *HashAggExec) Open(ctx context.Context) error {
 	if e.ctx.GetSessionVars().TrackAggregateMemoryUsage {
 		e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 	}
+	atomic.StoreUint32(&e.inSpillMode, 0)
+	// Spilling relies on the memory usage of aggregate functions being tracked by the statement,
+	// otherwise the Action would be triggered by the memory consumed by other executors only.
+	// The Action is registered only once, since the executor may be reopened many times, e.g. as the inner side of Apply.
+	if e.spillAction == nil && e.ctx.GetSessionVars().TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+		e.spillAction = &AggSpillDiskAction{e: e}
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+	}
 
 	if e.isUnparallelExec {
 		e.initForUnparallelExec()
@@ -301,6 +387,15 @@ func (e *HashAggExec) initForUnparallelExec() {
 	e.groupKeyBuffer = make([][]byte, 0, 8)
 	e.childResult = newFirstChunk(e.children[0])
 	e.memTracker.Consume(e.childResult.MemoryUsage())
+
+	e.offsetOfSpilledChks, e.numOfSpilledChks = 0, 0
+	e.executed, e.isChildDrained = false, false
+	e.listInDisk = nil
+	if e.spillAction != nil {
+		e.listInDisk = chunk.NewListInDisk(retTypes(e.children[0]))
+		e.listInDisk.GetDiskTracker().AttachTo(e.diskTracker)
+		e.tmpChkForSpill = newFirstChunk(e.children[0])
+	}
 }
 
 func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
@@ -338,9 +433,23 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 8),
 		}
+		if e.spillAction != nil {
+			// The partial results of this worker are released after being merged by final workers,
+			// so the worker uses its own tracker.
+			w.memTracker = memory.NewTracker(memory.LabelForHashAggWorker, -1)
+			w.memTracker.AttachTo(e.memTracker)
+			w.inSpillMode = &e.inSpillMode
+			w.spilledChunks = make([]*chunk.ListInDisk, finalConcurrency)
+			w.tmpChksForSpill = make([]*chunk.Chunk, finalConcurrency)
+			for j := range w.spilledChunks {
+				w.spilledChunks[j] = chunk.NewListInDisk(retTypes(e.children[0]))
+				w.spilledChunks[j].GetDiskTracker().AttachTo(e.diskTracker)
+				w.tmpChksForSpill[j] = newFirstChunk(e.children[0])
+			}
+		}
 		// There is a bucket in the empty partialResultsMap.
 		failpoint.Inject("ConsumeRandomPanic", nil)
-		e.memTracker.Consume(defBucketMemoryUsage * (1 << w.BInMap))
+		w.memTracker.Consume(defBucketMemoryUsage * (1 << w.BInMap))
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.PartialStats = append(e.stats.PartialStats, w.stats)
@@ -369,8 +478,23 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
 		}
+		if e.spillAction != nil {
+			// The final results of this worker are released after each round of spilling,
+			// so the worker uses its own tracker.
+			w.memTracker = memory.NewTracker(memory.LabelForHashAggWorker, -1)
+			w.memTracker.AttachTo(e.memTracker)
+			w.inSpillMode = &e.inSpillMode
+			w.groupByItems = e.GroupByItems
+			w.partialAggFuncs = e.PartialAggFuncs
+			w.listInDisk = chunk.NewListInDisk(retTypes(e.children[0]))
+			w.listInDisk.GetDiskTracker().AttachTo(e.diskTracker)
+			w.tmpChkForSpill = newFirstChunk(e.children[0])
+			for j := range e.partialWorkers {
+				w.spilledInput = append(w.spilledInput, e.partialWorkers[j].spilledChunks[i])
+			}
+		}
 		// There is a bucket in the empty partialResultsMap.
-		e.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
+		w.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.FinalStats = append(e.stats.FinalStats, w.stats)
@@ -412,6 +536,9 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if r := recover(); r != nil {
 			recoveryHashAgg(w.globalOutputCh, r)
 		}
+		if err := w.flushSpilledRows(); err != nil {
+			w.globalOutputCh <- &AfFinalResult{err: err}
+		}
 		if needShuffle {
 			w.shuffleIntermData(sc, finalConcurrency)
 		}
@@ -463,13 +590,28 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		return err
 	}
 
-	partialResults := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
 	numRows := chk.NumRows()
+	groupKey, rowIdx := w.groupKey[:numRows], []int(nil)
+	if w.inSpillMode != nil && !w.isSpilling {
+		w.isSpilling = atomic.LoadUint32(w.inSpillMode) == 1
+	}
+	// Keep at least one group in memory to make sure the aggregation can make progress.
+	if w.isSpilling && len(w.partialResultsMap) > 0 {
+		groupKey, rowIdx, err = w.spillUnseenGroups(chk, groupKey)
+		if err != nil {
+			return err
+		}
+	}
+	partialResults := w.getPartialResult(sc, groupKey, w.partialResultsMap)
 	rows := make([]chunk.Row, 1)
 	allMemDelta := int64(0)
-	for i := 0; i < numRows; i++ {
-		for j, af := range w.aggFuncs {
+	for i := range groupKey {
+		if rowIdx != nil {
+			rows[0] = chk.GetRow(rowIdx[i])
+		} else {
 			rows[0] = chk.GetRow(i)
+		}
+		for j, af := range w.aggFuncs {
 			memDelta, err := af.UpdatePartialResult(ctx, rows, partialResults[i][j])
 			if err != nil {
 				return err
@@ -481,6 +623,44 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 	return nil
 }
 
+// spillUnseenGroups spills the rows whose groups are not in partialResultsMap to the disk partition
+// of the corresponding final worker, it returns the group keys and the row indexes of the other rows.
+func (w *HashAggPartialWorker) spillUnseenGroups(chk *chunk.Chunk, groupKey [][]byte) (keptKeys [][]byte, keptRows []int, err error) {
+	keptKeys = make([][]byte, 0, len(groupKey))
+	keptRows = make([]int, 0, len(groupKey))
+	for i, key := range groupKey {
+		if _, ok := w.partialResultsMap[string(key)]; ok {
+			keptKeys = append(keptKeys, key)
+			keptRows = append(keptRows, i)
+			continue
+		}
+		finalWorkerIdx := int(murmur3.Sum32(key)) % len(w.outputChs)
+		tmpChk := w.tmpChksForSpill[finalWorkerIdx]
+		tmpChk.AppendRow(chk.GetRow(i))
+		if tmpChk.IsFull() {
+			if err = w.spilledChunks[finalWorkerIdx].Add(tmpChk); err != nil {
+				return nil, nil, err
+			}
+			tmpChk.Reset()
+		}
+	}
+	return keptKeys, keptRows, nil
+}
+
+// flushSpilledRows writes the buffered rows to be spilled to disk.
+func (w *HashAggPartialWorker) flushSpilledRows() error {
+	for i, tmpChk := range w.tmpChksForSpill {
+		if tmpChk.NumRows() == 0 {
+			continue
+		}
+		if err := w.spilledChunks[i].Add(tmpChk); err != nil {
+			return err
+		}
+		tmpChk.Reset()
+	}
+	return nil
+}
+
 // shuffleIntermData shuffles the intermediate data of partial workers to corresponded final workers.
 // We only support parallel execution for single-machine, so process of encode and decode can be skipped.
 func (w *HashAggPartialWorker) shuffleIntermData(sc *stmtctx.StatementContext, finalConcurrency int) {
@@ -689,19 +869,236 @@ func (w *HashAggFinalWorker) receiveFinalResultHolder() (*chunk.Chunk, bool) {
 
 func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGroup) {
 	start := time.Now()
+	intermDataConsumed := false
 	defer func() {
 		if r := recover(); r != nil {
 			recoveryHashAgg(w.outputCh, r)
 		}
+		if w.intermDataWaitGroup != nil && !intermDataConsumed {
+			w.intermDataWaitGroup.Done()
+		}
 		if w.stats != nil {
 			w.stats.WorkerTime += int64(time.Since(start))
 		}
 		waitGroup.Done()
 	}()
-	if err := w.consumeIntermData(ctx); err != nil {
+	err := w.consumeIntermData(ctx)
+	if w.intermDataWaitGroup != nil {
+		intermDataConsumed = true
+		w.intermDataWaitGroup.Done()
+	}
+	if err != nil {
 		w.outputCh <- &AfFinalResult{err: err}
 	}
-	w.getFinalResult(ctx)
+	if w.inSpillMode == nil {
+		w.getFinalResult(ctx)
+		return
+	}
+	// Re-aggregate the spilled rows round by round, the groups which can not be
+	// held in memory in this round are spilled again and handled in the next one.
+	defer w.spillRound.leave()
+	spilledChks := w.spilledChunksOfFirstRound()
+	for {
+		if err := w.consumeSpilledData(ctx, spilledChks); err != nil {
+			w.outputCh <- &AfFinalResult{err: err}
+			return
+		}
+		w.getFinalResult(ctx)
+		select {
+		case <-w.finishCh:
+			return
+		default:
+		}
+		if spilledChks = w.spilledChunksOfNextRound(); len(spilledChks) == 0 {
+			return
+		}
+		if !w.spillRound.finish(w.finishCh) {
+			return
+		}
+	}
+}
+
+// aggSpillRound synchronizes the rounds of re-aggregating spilled rows among the final workers.
+// The spill mode is shared by all the workers, so it can only be reset after every worker has
+// finished the current round, otherwise the rows still being spilled by the other workers would
+// be aggregated in memory.
+type aggSpillRound struct {
+	mu          sync.Mutex
+	inSpillMode *uint32
+	// active is the number of workers which have not finished re-aggregating.
+	active  int
+	arrived int
+	// done is closed when all the active workers have finished the current round.
+	done chan struct{}
+}
+
+func newAggSpillRound(inSpillMode *uint32, workers int) *aggSpillRound {
+	return &aggSpillRound{inSpillMode: inSpillMode, active: workers, done: make(chan struct{})}
+}
+
+// finish waits for the other workers to finish the current round, it returns false if the executor is closed.
+func (r *aggSpillRound) finish(finishCh <-chan struct{}) bool {
+	r.mu.Lock()
+	r.arrived++
+	done := r.done
+	r.nextRoundIfAllArrived()
+	r.mu.Unlock()
+	select {
+	case <-done:
+		return true
+	case <-finishCh:
+		return false
+	}
+}
+
+// leave is called when the worker has no more spilled rows to re-aggregate.
+func (r *aggSpillRound) leave() {
+	r.mu.Lock()
+	r.active--
+	r.nextRoundIfAllArrived()
+	r.mu.Unlock()
+}
+
+func (r *aggSpillRound) nextRoundIfAllArrived() {
+	if r.arrived == 0 || r.arrived < r.active {
+		return
+	}
+	atomic.StoreUint32(r.inSpillMode, 0)
+	r.arrived = 0
+	close(r.done)
+	r.done = make(chan struct{})
+}
+
+// spilledChunk indicates a chunk spilled to disk.
+type spilledChunk struct {
+	list   *chunk.ListInDisk
+	chkIdx int
+}
+
+func (w *HashAggFinalWorker) spilledChunksOfFirstRound() []spilledChunk {
+	var spilledChks []spilledChunk
+	for _, l := range w.spilledInput {
+		for i := 0; i < l.NumChunks(); i++ {
+			spilledChks = append(spilledChks, spilledChunk{list: l, chkIdx: i})
+		}
+	}
+	return spilledChks
+}
+
+// spilledChunksOfNextRound resets the in-memory results of the last round,
+// and returns the chunks spilled in the last round. The spill mode is reset by spillRound.
+func (w *HashAggFinalWorker) spilledChunksOfNextRound() []spilledChunk {
+	numChks := w.listInDisk.NumChunks()
+	if numChks == w.numOfSpilledChks {
+		// No data is spilled again, all data have been processed.
+		return nil
+	}
+	spilledChks := make([]spilledChunk, 0, numChks-w.numOfSpilledChks)
+	for i := w.numOfSpilledChks; i < numChks; i++ {
+		spilledChks = append(spilledChks, spilledChunk{list: w.listInDisk, chkIdx: i})
+	}
+	w.numOfSpilledChks = numChks
+
+	var setSize int64
+	w.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	w.partialResultMap = make(aggPartialResultMapper)
+	w.BInMap = 0
+	w.memTracker.ReplaceBytesUsed(defBucketMemoryUsage*(1<<w.BInMap) + setSize + getGroupKeyMemUsage(w.groupKeys))
+	return spilledChks
+}
+
+// consumeSpilledData aggregates the spilled rows into the final results, the rows whose groups
+// are not in memory are spilled again if the executor is in spill mode.
+func (w *HashAggFinalWorker) consumeSpilledData(sctx sessionctx.Context, spilledChks []spilledChunk) error {
+	var (
+		sc         = sctx.GetSessionVars().StmtCtx
+		isSpilling bool
+		groupKey   [][]byte
+		rows       = make([]chunk.Row, 1)
+	)
+	for _, spilledChk := range spilledChks {
+		chk, err := spilledChk.list.GetChunk(spilledChk.chkIdx)
+		if err != nil {
+			return err
+		}
+		groupKey, err = getGroupKey(w.ctx, chk, groupKey, w.groupByItems)
+		if err != nil {
+			return err
+		}
+		// Rows are aggregated by the partial aggregate functions first,
+		// and then merged into the final results like the intermediate data.
+		partialResultMap := make(aggPartialResultMapper)
+		keys := make([]string, 0, chk.NumRows())
+		for i := 0; i < chk.NumRows(); i++ {
+			key := string(groupKey[i])
+			partialResults, ok := partialResultMap[key]
+			if !ok {
+				if !isSpilling {
+					isSpilling = atomic.LoadUint32(w.inSpillMode) == 1
+				}
+				if isSpilling && !w.groupSet.Exist(key) && w.groupSet.Count() > 0 {
+					if err = w.spillRow(chk.GetRow(i)); err != nil {
+						return err
+					}
+					continue
+				}
+				partialResults = make([]aggfuncs.PartialResult, 0, len(w.partialAggFuncs))
+				for _, af := range w.partialAggFuncs {
+					partialResult, _ := af.AllocPartialResult()
+					partialResults = append(partialResults, partialResult)
+				}
+				partialResultMap[key] = partialResults
+				keys = append(keys, key)
+			}
+			rows[0] = chk.GetRow(i)
+			for j, af := range w.partialAggFuncs {
+				if _, err = af.UpdatePartialResult(sctx, rows, partialResults[j]); err != nil {
+					return err
+				}
+			}
+		}
+		memSize := getGroupKeyMemUsage(w.groupKeys)
+		w.groupKeys = w.groupKeys[:0]
+		for _, key := range keys {
+			w.groupKeys = append(w.groupKeys, []byte(key))
+		}
+		w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
+		finalPartialResults := w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
+		allMemDelta := int64(0)
+		for i, key := range keys {
+			if !w.groupSet.Exist(key) {
+				allMemDelta += w.groupSet.Insert(key)
+			}
+			prs := partialResultMap[key]
+			for j, af := range w.aggFuncs {
+				memDelta, err := af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j])
+				if err != nil {
+					return err
+				}
+				allMemDelta += memDelta
+			}
+		}
+		w.memTracker.Consume(allMemDelta)
+	}
+	if w.tmpChkForSpill.NumRows() > 0 {
+		if err := w.listInDisk.Add(w.tmpChkForSpill); err != nil {
+			return err
+		}
+		w.tmpChkForSpill.Reset()
+	}
+	return nil
+}
+
+func (w *HashAggFinalWorker) spillRow(row chunk.Row) error {
+	w.tmpChkForSpill.AppendRow(row)
+	if !w.tmpChkForSpill.IsFull() {
+		return nil
+	}
+	if err := w.listInDisk.Add(w.tmpChkForSpill); err != nil {
+		return err
+	}
+	w.tmpChkForSpill.Reset()
+	return nil
 }
 
 // Next implements the Executor Next interface.
@@ -793,6 +1190,18 @@ func (e *HashAggExec) prepare4ParallelExec(ctx context.Context) {
 	}()
 	finalWorkerWaitGroup := &sync.WaitGroup{}
 	finalWorkerWaitGroup.Add(len(e.finalWorkers))
+	if e.spillAction != nil {
+		intermDataWaitGroup := &sync.WaitGroup{}
+		intermDataWaitGroup.Add(len(e.finalWorkers))
+		for i := range e.finalWorkers {
+			e.finalWorkers[i].intermDataWaitGroup = intermDataWaitGroup
+		}
+		go releasePartialResults(e.partialWorkers, intermDataWaitGroup)
+		spillRound := newAggSpillRound(&e.inSpillMode, len(e.finalWorkers))
+		for i := range e.finalWorkers {
+			e.finalWorkers[i].spillRound = spillRound
+		}
+	}
 	finalStart := time.Now()
 	for i := range e.finalWorkers {
 		go e.finalWorkers[i].run(e.ctx, finalWorkerWaitGroup)
@@ -809,6 +1218,16 @@ func (e *HashAggExec) prepare4ParallelExec(ctx context.Context) {
 	go e.waitAllWorkersAndCloseFinalOutputCh(fetchChildWorkerWaitGroup, partialWorkerWaitGroup, finalWorkerWaitGroup)
 }
 
+// releasePartialResults releases the memory of partial results after they have been merged by all the final workers,
+// so that the memory can be used by the re-aggregation of spilled data.
+func releasePartialResults(partialWorkers []HashAggPartialWorker, intermDataWaitGroup *sync.WaitGroup) {
+	intermDataWaitGroup.Wait()
+	for i := range partialWorkers {
+		partialWorkers[i].partialResultsMap = nil
+		partialWorkers[i].memTracker.ReplaceBytesUsed(0)
+	}
+}
+
 // HashAggExec employs one input reader, M partial workers and N final workers to execute parallelly.
 // The parallel execution flow is:
 // 1. input reader reads data from child executor and send them to partial workers.
@@ -853,10 +1272,32 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 
 // unparallelExec executes hash aggregation algorithm in single thread.
 func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) error {
-	// In this stage we consider all data from src as a single group.
-	if !e.prepared {
-		err := e.execute(ctx)
-		if err != nil {
+	chk.Reset()
+	for {
+		if e.prepared {
+			// Since we return e.maxChunkSize rows every time, so we should not traverse
+			// `groupSet` because of its randomness.
+			for ; e.cursor4GroupKey < len(e.groupKeys); e.cursor4GroupKey++ {
+				partialResults := e.getPartialResults(e.groupKeys[e.cursor4GroupKey])
+				if len(e.PartialAggFuncs) == 0 {
+					chk.SetNumVirtualRows(chk.NumRows() + 1)
+				}
+				for i, af := range e.PartialAggFuncs {
+					if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
+						return err
+					}
+				}
+				if chk.IsFull() {
+					e.cursor4GroupKey++
+					return nil
+				}
+			}
+			e.resetSpillMode()
+		}
+		if e.executed {
+			return nil
+		}
+		if err := e.execute(ctx); err != nil {
 			return err
 		}
 		if (len(e.groupSet.StringSet) == 0) && len(e.GroupByItems) == 0 {
@@ -869,33 +1310,42 @@ func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) erro
 		}
 		e.prepared = true
 	}
-	chk.Reset()
+}
 
-	// Since we return e.maxChunkSize rows every time, so we should not traverse
-	// `groupSet` because of its randomness.
-	for ; e.cursor4GroupKey < len(e.groupKeys); e.cursor4GroupKey++ {
-		partialResults := e.getPartialResults(e.groupKeys[e.cursor4GroupKey])
-		if len(e.PartialAggFuncs) == 0 {
-			chk.SetNumVirtualRows(chk.NumRows() + 1)
-		}
-		for i, af := range e.PartialAggFuncs {
-			if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
-				return err
-			}
-		}
-		if chk.IsFull() {
-			e.cursor4GroupKey++
-			return nil
-		}
+// resetSpillMode resets the in-memory results of the last round,
+// and prepares to re-aggregate the data spilled in the last round.
+func (e *HashAggExec) resetSpillMode() {
+	numOfSpilledChks := 0
+	if e.listInDisk != nil {
+		numOfSpilledChks = e.listInDisk.NumChunks()
 	}
-	return nil
+	// No data is spilled again, all data have been processed.
+	e.executed = e.numOfSpilledChks == numOfSpilledChks
+	if e.executed {
+		return
+	}
+	e.cursor4GroupKey, e.groupKeys = 0, e.groupKeys[:0]
+	var setSize int64
+	e.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	e.partialResultMap = make(aggPartialResultMapper)
+	e.bInMap = 0
+	e.prepared = false
+	e.numOfSpilledChks = numOfSpilledChks
+	e.memTracker.ReplaceBytesUsed(defBucketMemoryUsage*(1<<e.bInMap) + setSize + e.childResult.MemoryUsage())
+	atomic.StoreUint32(&e.inSpillMode, 0)
 }
 
 // execute fetches Chunks from src and update each aggregate function for each row in Chunk.
 func (e *HashAggExec) execute(ctx context.Context) (err error) {
+	defer func() {
+		if err == nil && e.tmpChkForSpill != nil && e.tmpChkForSpill.NumRows() > 0 {
+			err = e.listInDisk.Add(e.tmpChkForSpill)
+			e.tmpChkForSpill.Reset()
+		}
+	}()
 	for {
 		mSize := e.childResult.MemoryUsage()
-		err := Next(ctx, e.children[0], e.childResult)
+		err := e.getNextChunk(ctx)
 		failpoint.Inject("ConsumeRandomPanic", nil)
 		e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
 		if err != nil {
@@ -919,9 +1369,17 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 		}
 
 		allMemDelta := int64(0)
+		isSpilling := e.spillAction != nil && atomic.LoadUint32(&e.inSpillMode) == 1
 		for j := 0; j < e.childResult.NumRows(); j++ {
 			groupKey := string(e.groupKeyBuffer[j]) // do memory copy here, because e.groupKeyBuffer may be reused.
 			if !e.groupSet.Exist(groupKey) {
+				// Keep at least one group in memory to make sure the aggregation can make progress.
+				if isSpilling && e.groupSet.Count() > 0 {
+					if err = e.spillRow(e.childResult.GetRow(j)); err != nil {
+						return err
+					}
+					continue
+				}
 				allMemDelta += e.groupSet.Insert(groupKey)
 				e.groupKeys = append(e.groupKeys, groupKey)
 			}
@@ -939,6 +1397,42 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 	}
 }
 
+// getNextChunk fetches the next chunk from the child executor,
+// and from the data spilled in the last round after the child is drained.
+func (e *HashAggExec) getNextChunk(ctx context.Context) (err error) {
+	if !e.isChildDrained {
+		if err = Next(ctx, e.children[0], e.childResult); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() > 0 {
+			return nil
+		}
+		e.isChildDrained = true
+	}
+	e.childResult.Reset()
+	if e.offsetOfSpilledChks < e.numOfSpilledChks {
+		chk, err := e.listInDisk.GetChunk(e.offsetOfSpilledChks)
+		if err != nil {
+			return err
+		}
+		e.childResult.SwapColumns(chk)
+		e.offsetOfSpilledChks++
+	}
+	return nil
+}
+
+func (e *HashAggExec) spillRow(row chunk.Row) error {
+	e.tmpChkForSpill.AppendRow(row)
+	if !e.tmpChkForSpill.IsFull() {
+		return nil
+	}
+	if err := e.listInDisk.Add(e.tmpChkForSpill); err != nil {
+		return err
+	}
+	e.tmpChkForSpill.Reset()
+	return nil
+}
+
 func (e *HashAggExec) getPartialResults(groupKey string) []aggfuncs.PartialResult {
 	partialResults, ok := e.partialResultMap[groupKey]
 	allMemDelta := int64(0)
@@ -1074,6 +1568,42 @@ func (e *HashAggRuntimeStats) Tp() int {
 	return execdetails.TpHashAggRuntimeStat
 }
 
+// maxSpillTimes indicates how many times the HashAggExec can be set to `spill mode` at most.
+const maxSpillTimes = 10
+
+// AggSpillDiskAction implements memory.ActionOnExceed for HashAggExec. If the memory quota of a query is exceeded,
+// AggSpillDiskAction.Action is triggered to set HashAggExec to `spill mode`.
+type AggSpillDiskAction struct {
+	memory.BaseOOMAction
+	e          *HashAggExec
+	spillTimes uint32
+}
+
+// Action sets HashAggExec to `spill mode`, and calls its fallbackAction if HashAggExec is already in `spill mode`.
+func (a *AggSpillDiskAction) Action(t *memory.Tracker) {
+	// Guarantee that processed data is at least 20% of the threshold, to avoid spilling too frequently.
+	if atomic.LoadUint32(&a.e.inSpillMode) == 0 && a.spillTimes < maxSpillTimes && a.e.memTracker.BytesConsumed() >= t.GetBytesLimit()/5 {
+		a.spillTimes++
+		logutil.BgLogger().Info("memory exceeds quota, set aggregate mode to spill-mode",
+			zap.Uint32("spillTimes", a.spillTimes),
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		atomic.StoreUint32(&a.e.inSpillMode, 1)
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (a *AggSpillDiskAction) SetLogHook(hook func(uint64)) {}
+
+// GetPriority get the priority of the Action.
+func (a *AggSpillDiskAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
 // StreamAggExec deals with all the aggregate functions.
 // It assumes all the input data is sorted by group by key.
 // When Next() is called, it will return a result for the same group.
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
@@ -1045,6 +1046,46 @@ func (s *testSuiteAgg) TestAggJSON(c *C) {
 	))
 }
 
+func (s *testSuiteAgg) TestJSONArrayagg(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a int, b int, c varchar(10), d json)")
+	tk.MustQuery("select json_arrayagg(b) from t").Check(testkit.Rows("<nil>"))
+	tk.MustExec(`insert into t values(1, 1, 1, 'a', '{"k": 1}'), (2, 1, null, 'b', '[1, 2]'), (3, 2, 3, 'c', null), (4, 3, 4, null, '"x"'), (5, 3, 5, 'e', 'true')`)
+
+	checkResults := func() {
+		tk.MustQuery("select a, json_arrayagg(b) from t group by a order by a").Check(testkit.Rows("1 [1, null]", "2 [3]", "3 [4, 5]"))
+		tk.MustQuery("select json_arrayagg(c) from t where a = 3").Check(testkit.Rows(`[null, "e"]`))
+		tk.MustQuery("select json_arrayagg(d) from t where a < 3").Check(testkit.Rows(`[{"k": 1}, [1, 2], null]`))
+		tk.MustQuery("select json_arrayagg(b) from t where a > 3").Check(testkit.Rows("<nil>"))
+		tk.MustQuery("select json_arrayagg(null) from t where a = 2").Check(testkit.Rows("[null]"))
+	}
+	hasCopJSONArrayagg := func() bool {
+		for _, row := range tk.MustQuery("explain format = 'brief' select a, json_arrayagg(b) from t group by a").Rows() {
+			if strings.HasPrefix(row[2].(string), "cop") && strings.Contains(row[4].(string), "json_arrayagg(") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// json_arrayagg is only supported by TiDB, so it isn't pushed down to the coprocessor.
+	c.Assert(hasCopJSONArrayagg(), IsFalse)
+	checkResults()
+
+	// the partial and final aggregation are both executed by the parallel hash aggregation.
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 4")
+	tk.MustExec("set @@tidb_hashagg_final_concurrency = 4")
+	checkResults()
+	tk.MustQuery("select /*+ stream_agg() */ a, json_arrayagg(b) from t group by a order by a").Check(testkit.Rows("1 [1, null]", "2 [3]", "3 [4, 5]"))
+
+	// json_arrayagg as a window function.
+	tk.MustQuery("select id, json_arrayagg(b) over (partition by a order by id) from t order by id").Check(testkit.Rows(
+		"1 [1]", "2 [1, null]", "3 [3]", "4 [4]", "5 [4, 5]"))
+	tk.MustQuery("select id, json_arrayagg(c) over (order by id rows between 1 preceding and 1 following) from t order by id").Check(testkit.Rows(
+		`1 ["a", "b"]`, `2 ["a", "b", "c"]`, `3 ["b", "c", null]`, `4 ["c", null, "e"]`, `5 [null, "e"]`))
+}
+
 func (s *testSuiteAgg) TestIssue10099(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("drop table if exists t")
@@ -1461,3 +1502,42 @@ func (s *testSuiteAgg) TestIssue23314(c *C) {
 	res := tk.MustQuery("select col1 from t1 group by col1")
 	res.Check(testkit.Rows("16:40:20.01"))
 }
+
+func (s *testSerialSuite) TestAggInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	var buf strings.Builder
+	buf.WriteString("insert into t values (0, 0)")
+	for i := 1; i < 2000; i++ {
+		buf.WriteString(fmt.Sprintf(",(%v, %v)", i, i%7))
+	}
+	tk.MustExec(buf.String())
+	tk.MustExec("insert into t select a, b + 1 from t")
+	expected := tk.MustQuery("select /*+ HASH_AGG() */ a, count(*), sum(b) from t group by a").Sort().Rows()
+	expectedDistinct := tk.MustQuery("select /*+ HASH_AGG() */ a, count(distinct b) from t group by a").Sort().Rows()
+
+	tk.MustExec("set @@tidb_mem_quota_query = 65536")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	defer tk.MustExec("set @@tidb_mem_quota_query = default")
+	for _, concurrency := range []int{1, 4} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_partial_concurrency = %v", concurrency))
+		tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_final_concurrency = %v", concurrency))
+		tk.MustQuery("select /*+ HASH_AGG() */ a, count(*), sum(b) from t group by a").Sort().Check(expected)
+		rows := tk.MustQuery("explain analyze select /*+ HASH_AGG() */ a, count(*), sum(b) from t group by a").Rows()
+		for _, row := range rows {
+			if strings.Contains(fmt.Sprintf("%v", row[0]), "HashAgg") && row[3] == "root" {
+				disk := fmt.Sprintf("%v", row[len(row)-1])
+				c.Assert(disk, Not(Equals), "N/A", Commentf("concurrency: %v", concurrency))
+				c.Assert(disk, Not(Equals), "0 Bytes", Commentf("concurrency: %v", concurrency))
+			}
+		}
+	}
+	// Aggregation with distinct is always executed unparallelly.
+	tk.MustQuery("select /*+ HASH_AGG() */ a, count(distinct b) from t group by a").Sort().Check(expectedDistinct)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/math"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/tikv/client-go/v2/tikv"
 )
 
@@ -149,6 +150,8 @@ func (e *BatchPointGetExec) Open(context.Context) error {
 	// Avoid network requests for the temporary table.
 	if e.tblInfo.TempTableType == model.TempTableGlobal {
 		snapshot = globalTemporaryTableSnapshot{snapshot}
+	} else if tableutil.KeepsSessionData(e.tblInfo.TempTableType) {
+		snapshot = sessionTemporaryTableSnapshot{snapshot, e.ctx.GetSessionVars()}
 	}
 	var batchGetter kv.BatchGetter = snapshot
 	if txn.Valid() {
@@ -176,6 +179,28 @@ func (s globalTemporaryTableSnapshot) BatchGet(ctx context.Context, keys []kv.Ke
 	return make(map[string][]byte), nil
 }
 
+// sessionTemporaryTableSnapshot inherits kv.Snapshot and override the BatchGet methods to read the data
+// committed in the current session, which is where the rows of the session-scoped temporary tables live.
+type sessionTemporaryTableSnapshot struct {
+	kv.Snapshot
+	sessVars *variable.SessionVars
+}
+
+func (s sessionTemporaryTableSnapshot) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		val, err := s.sessVars.GetTemporaryTableSnapshotValue(ctx, key)
+		if err != nil {
+			if kv.ErrNotExist.Equal(err) {
+				continue
+			}
+			return nil, err
+		}
+		values[string(key)] = val
+	}
+	return values, nil
+}
+
 // Close implements the Executor interface.
 func (e *BatchPointGetExec) Close() error {
 	if e.runtimeStats != nil && e.snapshot != nil {
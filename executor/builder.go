@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
@@ -221,6 +222,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -1403,6 +1406,16 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Execu
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) Executor {
+	return &JSONTableExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		asName:       v.AsName,
+		expr:         v.Expr,
+		path:         v.Path,
+		columns:      v.Columns,
+	}
+}
+
 // `getSnapshotTS` returns the timestamp of the snapshot that a reader should read.
 func (b *executorBuilder) getSnapshotTS() (uint64, error) {
 	// `refreshForUpdateTSForRC` should always be invoked before returning the cached value to
@@ -1569,6 +1582,7 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableTiDBHotRegions),
 			strings.ToLower(infoschema.TableSessionVar),
 			strings.ToLower(infoschema.TableConstraints),
+			strings.ToLower(infoschema.TableCheckConstraints),
 			strings.ToLower(infoschema.TableTiFlashReplica),
 			strings.ToLower(infoschema.TableTiDBServersInfo),
 			strings.ToLower(infoschema.TableTiKVStoreStatus),
@@ -3960,23 +3974,14 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 	for _, item := range v.OrderBy {
 		orderByCols = append(orderByCols, item.Col)
 	}
-	windowFuncs := make([]aggfuncs.AggFunc, 0, len(v.WindowFuncDescs))
-	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
-	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
-	for _, desc := range v.WindowFuncDescs {
-		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args, false)
-		if err != nil {
-			b.err = err
+
+	// The peer groups of GROUPS frames are computed on the whole partition, which is only supported by WindowExec.
+	isGroupsFrame := v.Frame != nil && v.Frame.Type == ast.Groups
+	if b.ctx.GetSessionVars().EnablePipelinedWindowExec && !isGroupsFrame {
+		windowFuncs, partialResults := b.buildWindowFuncs(v, orderByCols)
+		if b.err != nil {
 			return nil
 		}
-		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols)
-		windowFuncs = append(windowFuncs, agg)
-		partialResult, _ := agg.AllocPartialResult()
-		partialResults = append(partialResults, partialResult)
-		resultColIdx++
-	}
-
-	if b.ctx.GetSessionVars().EnablePipelinedWindowExec {
 		exec := &PipelinedWindowExec{
 			baseExecutor:   base,
 			groupChecker:   newVecGroupChecker(b.ctx, groupByItems),
@@ -4007,8 +4012,53 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 				exec.isRangeFrame = true
 			}
 		}
+		// PipelinedWindowExec has to keep the whole partition in memory when the frame is unbounded,
+		// so it hands the rows over to WindowExec, which is able to spill them to disk, once the
+		// memory quota of the query is exceeded. The functions operating on the entire partition are
+		// given a bounded default frame by the planner, and they count the rows which are out of the
+		// frame, so they can't be handed over.
+		canFallBack := true
+		for _, desc := range v.WindowFuncDescs {
+			if v.Frame != nil && !aggregation.NeedFrame(desc.Name) {
+				canFallBack = false
+			}
+		}
+		if canFallBack {
+			exec.fallback = b.buildWindowExec(v, base, groupByItems, orderByCols)
+			if b.err != nil {
+				return nil
+			}
+		}
 		return exec
 	}
+	return b.buildWindowExec(v, base, groupByItems, orderByCols)
+}
+
+func (b *executorBuilder) buildWindowFuncs(v *plannercore.PhysicalWindow, orderByCols []*expression.Column) ([]aggfuncs.AggFunc, []aggfuncs.PartialResult) {
+	windowFuncs := make([]aggfuncs.AggFunc, 0, len(v.WindowFuncDescs))
+	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
+	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
+	for _, desc := range v.WindowFuncDescs {
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args, desc.HasDistinct)
+		if err != nil {
+			b.err = err
+			return nil, nil
+		}
+		aggDesc.OrderByItems = desc.OrderByItems
+		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols, desc.IgnoreNull, desc.FromLast)
+		windowFuncs = append(windowFuncs, agg)
+		partialResult, _ := agg.AllocPartialResult()
+		partialResults = append(partialResults, partialResult)
+		resultColIdx++
+	}
+	return windowFuncs, partialResults
+}
+
+func (b *executorBuilder) buildWindowExec(v *plannercore.PhysicalWindow, base baseExecutor, groupByItems []expression.Expression, orderByCols []*expression.Column) *WindowExec {
+	windowFuncs, partialResults := b.buildWindowFuncs(v, orderByCols)
+	if b.err != nil {
+		return nil
+	}
 	var processor windowProcessor
 	if v.Frame == nil {
 		processor = &aggWindowProcessor{
@@ -4022,6 +4072,19 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) Executor {
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
 		}
+	} else if v.Frame.Type == ast.Groups {
+		cmpFuncs := make([]expression.CompareFunc, 0, len(orderByCols))
+		for _, col := range orderByCols {
+			cmpFuncs = append(cmpFuncs, expression.GetCmpFunction(b.ctx, col, col))
+		}
+		processor = &groupsFrameWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+			start:          v.Frame.Start,
+			end:            v.Frame.End,
+			orderByCols:    orderByCols,
+			cmpFuncs:       cmpFuncs,
+		}
 	} else {
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
@@ -4323,6 +4386,10 @@ func (s *emptySampler) finished() bool {
 	return true
 }
 
+func (s *emptySampler) close() error {
+	return nil
+}
+
 func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *TableSampleExecutor {
 	startTS, err := b.getSnapshotTS()
 	if err != nil {
@@ -4335,12 +4402,33 @@ func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *
 		startTS:      startTS,
 	}
 
+	node := v.TableSampleInfo.AstNode
 	if v.TableInfo.Meta().TempTableType != model.TempTableNone {
 		e.sampler = &emptySampler{}
-	} else if v.TableSampleInfo.AstNode.SampleMethod == ast.SampleMethodTypeTiDBRegion {
+	} else if node.SampleMethod == ast.SampleMethodTypeTiDBRegion {
 		e.sampler = newTableRegionSampler(
 			b.ctx, v.TableInfo, startTS, v.TableSampleInfo.Partitions, v.Schema(),
 			v.TableSampleInfo.FullSchema, e.retFieldTypes, v.Desc)
+	} else {
+		sc := b.ctx.GetSessionVars().StmtCtx
+		// The percentage and the seed are checked to be numeric literals in the preprocessor.
+		percent, err := node.Expr.(*driver.ValueExpr).ToFloat64(sc)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		seed := time.Now().UnixNano()
+		if node.RepeatableSeed != nil {
+			seed, err = node.RepeatableSeed.(*driver.ValueExpr).ToInt64(sc)
+			if err != nil {
+				b.err = err
+				return nil
+			}
+		}
+		regionSampler := newTableRegionSampler(
+			b.ctx, v.TableInfo, startTS, v.TableSampleInfo.Partitions, v.Schema(),
+			v.TableSampleInfo.FullSchema, e.retFieldTypes, v.Desc)
+		e.sampler = newTablePercentSampler(regionSampler, node.SampleMethod, percent, seed)
 	}
 
 	return e
@@ -20,9 +20,11 @@ import (
 	"sort"
 
 	"github.com/pingcap/check"
+	"github.com/pingcap/parser/terror"
 
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 
@@ -448,3 +450,59 @@ func (test *CTETestSuite) TestCTEWithLimit(c *check.C) {
 	rows = tk.MustQuery("with recursive cte1(c1) as (select c1 from t1 union all select c1 + 1 from cte1 limit 4 offset 4) select * from cte1;")
 	rows.Check(testkit.Rows("3", "4", "3", "4"))
 }
+
+func (test *CTETestSuite) TestCTEWithLimitInRecursivePart(c *check.C) {
+	tk := testkit.NewTestKit(c, test.store)
+	tk.MustExec("use test;")
+
+	// Same as MySQL, the LIMIT of the recursive query block limits the whole CTE and stops the iteration.
+	rows := tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + 1 from cte1 limit 5)) select * from cte1")
+	rows.Check(testkit.Rows("1", "2", "3", "4", "5"))
+
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union (select c1 + 1 from cte1 limit 3 offset 2)) select * from cte1")
+	rows.Check(testkit.Rows("3", "4", "5"))
+
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + 1 from cte1 limit 0)) select * from cte1")
+	rows.Check(testkit.Rows())
+
+	// The recursion would never end without the LIMIT.
+	tk.MustExec("set cte_max_recursion_depth=10;")
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + 1 from cte1 limit 10)) select * from cte1 order by c1 desc limit 2")
+	rows.Check(testkit.Rows("10", "9"))
+	tk.MustExec("set cte_max_recursion_depth=1000;")
+
+	err := tk.ExecToErr("with recursive cte1(c1) as (select 1 union all (select c1 + 1 from cte1 limit 5) union all (select c1 + 2 from cte1 limit 3)) select * from cte1")
+	c.Assert(err, check.NotNil)
+	c.Assert(terror.ErrorEqual(err, plannercore.ErrNotSupportedYet.GenWithStackByArgs("more than one LIMIT in recursive Common Table Expression")), check.IsTrue, check.Commentf("err %v", err))
+}
+
+func (test *CTETestSuite) TestCTEWithOrderByAndDistinctInRecursivePart(c *check.C) {
+	tk := testkit.NewTestKit(c, test.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t1, t2;")
+	tk.MustExec("create table t1(a int);")
+	tk.MustExec("insert into t1 values(1), (1), (1);")
+	tk.MustExec("create table t2(a int);")
+	tk.MustExec("insert into t2 values(1), (2), (3);")
+
+	// DISTINCT removes the duplicated rows produced by each iteration.
+	rows := tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select distinct c1 + 1 from cte1, t1 where c1 < 3)) select * from cte1")
+	rows.Check(testkit.Rows("1", "2", "3"))
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + 1 from cte1, t1 where c1 < 3)) select count(*) from cte1")
+	rows.Check(testkit.Rows("13"))
+
+	// ORDER BY sorts the rows produced by each iteration.
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + a from cte1, t2 where c1 < 2 order by a desc)) select * from cte1")
+	rows.Check(testkit.Rows("1", "4", "3", "2"))
+
+	// ORDER BY decides which rows are kept by the LIMIT.
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select c1 + a from cte1, t2 where c1 < 10 order by c1 + a desc limit 3)) select * from cte1")
+	rows.Check(testkit.Rows("1", "4", "3"))
+	rows = tk.MustQuery("with recursive cte1(c1) as (select 1 union all (select distinct c1 + 1 from cte1, t1 where c1 < 10 order by 1 limit 4)) select * from cte1")
+	rows.Check(testkit.Rows("1", "2", "3", "4"))
+
+	// The AST is not changed after building, so the same statement can be executed again.
+	tk.MustExec("prepare stmt from 'with recursive cte1(c1) as (select 1 union all (select c1 + a from cte1, t2 where c1 < 10 order by c1 + a desc limit 3)) select * from cte1'")
+	tk.MustQuery("execute stmt").Check(testkit.Rows("1", "4", "3"))
+	tk.MustQuery("execute stmt").Check(testkit.Rows("1", "4", "3"))
+}
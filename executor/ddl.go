@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -319,6 +320,7 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	sessionVars := e.ctx.GetSessionVars()
 	if err == nil && strings.ToLower(sessionVars.CurrentDB) == dbName.L {
 		sessionVars.CurrentDB = ""
+		sessionVars.StateTracker.TrackSchema()
 		err = variable.SetSessionSystemVar(sessionVars, variable.CharsetDatabase, mysql.DefaultCharset)
 		if err != nil {
 			return err
@@ -647,7 +649,7 @@ func (e *DDLExec) getRecoverTableByTableName(tableName *ast.TableName) (*model.J
 		return nil, nil, errors.Errorf("Can't find dropped/truncated table: %v in DDL history jobs", tableName.Name)
 	}
 	// Dropping local temporary tables won't appear in DDL jobs.
-	if tableInfo.TempTableType == model.TempTableGlobal {
+	if tableutil.IsGlobalTemporaryTable(tableInfo.TempTableType) {
 		return nil, nil, errUnsupportedFlashbackTmpTable
 	}
 	return jobInfo, tableInfo, nil
@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"runtime"
 	"strconv"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
@@ -459,6 +461,37 @@ func (s *pkgTestSuite) TestSlowQueryRuntimeStats(c *C) {
 	c.Assert(stats.String(), Equals, "initialize: 2ms, read_file: 2s, parse_log: {time:200ms, concurrency:15}, total_file: 4, read_file: 4, read_size: 2 GB")
 }
 
+func (s *pkgTestSuite) TestAggSpillRound(c *C) {
+	inSpillMode := uint32(1)
+	round := newAggSpillRound(&inSpillMode, 3)
+	finishCh := make(chan struct{})
+	finished := make(chan bool)
+	go func() {
+		finished <- round.finish(finishCh)
+	}()
+	// The spill mode is kept until all the workers have finished the round.
+	select {
+	case <-finished:
+		c.Fatal("the round is finished before all the workers arrive")
+	case <-time.After(50 * time.Millisecond):
+	}
+	c.Assert(atomic.LoadUint32(&inSpillMode), Equals, uint32(1))
+	round.leave()
+	c.Assert(atomic.LoadUint32(&inSpillMode), Equals, uint32(1))
+	c.Assert(round.finish(finishCh), IsTrue)
+	c.Assert(<-finished, IsTrue)
+	c.Assert(atomic.LoadUint32(&inSpillMode), Equals, uint32(0))
+
+	// The worker stops waiting once the executor is closed.
+	atomic.StoreUint32(&inSpillMode, 1)
+	go func() {
+		finished <- round.finish(finishCh)
+	}()
+	close(finishCh)
+	c.Assert(<-finished, IsFalse)
+	c.Assert(atomic.LoadUint32(&inSpillMode), Equals, uint32(1))
+}
+
 // Test whether the actual buckets in Golang Map is same with the estimated number.
 // The test relies the implement of Golang Map. ref https://github.com/golang/go/blob/go1.13/src/runtime/map.go#L114
 func (s *pkgTestSuite) TestAggPartialResultMapperB(c *C) {
@@ -563,3 +596,22 @@ func (s *pkgTestSuite) TestFilterTemporaryTableKeys(c *C) {
 	res := filterTemporaryTableKeys(vars, []kv.Key{tablecodec.EncodeTablePrefix(tableID), tablecodec.EncodeTablePrefix(42)})
 	c.Assert(res, HasLen, 1)
 }
+
+type mockCloseSelectResult struct {
+	distsql.SelectResult
+	closed bool
+}
+
+func (r *mockCloseSelectResult) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (s *pkgTestSuite) TestTableSampleExecutorClose(c *C) {
+	result := &mockCloseSelectResult{}
+	sampler := &tablePercentSampler{result: result}
+	e := &TableSampleExecutor{sampler: sampler}
+	c.Assert(e.Close(), IsNil)
+	c.Assert(result.closed, IsTrue)
+	c.Assert(sampler.result, IsNil)
+}
@@ -0,0 +1,715 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	storeerr "github.com/pingcap/tidb/store/driver/error"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+// maxForeignKeyCascadeDepth is the max depth of the nested cascading foreign key actions, the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// fkRelation is a foreign key of the child table which refers to the parent table.
+type fkRelation struct {
+	dbName model.CIStr
+	fk     *model.FKInfo
+	child  table.Table
+	// parent is nil if the referenced table does not exist.
+	parent     table.Table
+	childCols  []*table.Column
+	parentCols []*table.Column
+}
+
+// String returns the description of the foreign key used in the error messages.
+func (r *fkRelation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (", r.dbName.O, r.child.Meta().Name.O, r.fk.Name.O)
+	for i, col := range r.fk.Cols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "`%s`", col.O)
+	}
+	fmt.Fprintf(&sb, ") REFERENCES `%s` (", r.fk.RefTable.O)
+	for i, col := range r.fk.RefCols {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "`%s`", col.O)
+	}
+	sb.WriteString(")")
+	if onDelete := ast.ReferOptionType(r.fk.OnDelete); onDelete != ast.ReferOptionNoOption {
+		fmt.Fprintf(&sb, " ON DELETE %s", onDelete)
+	}
+	if onUpdate := ast.ReferOptionType(r.fk.OnUpdate); onUpdate != ast.ReferOptionNoOption {
+		fmt.Fprintf(&sb, " ON UPDATE %s", onUpdate)
+	}
+	return sb.String()
+}
+
+// fkInfoSchema returns the information schema to look up the foreign keys, or nil if `foreign_key_checks` is disabled.
+func fkInfoSchema(sctx sessionctx.Context) infoschema.InfoSchema {
+	if !sctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	is, ok := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	if !ok {
+		return nil
+	}
+	// The local temporary tables can't have foreign keys, and they shouldn't hide the parent tables.
+	if attached, ok := is.(*infoschema.TemporaryTableAttachedInfoSchema); ok {
+		is = attached.InfoSchema
+	}
+	return is
+}
+
+func newFKRelation(is infoschema.InfoSchema, dbName model.CIStr, child table.Table, fk *model.FKInfo) *fkRelation {
+	childCols := make([]*table.Column, 0, len(fk.Cols))
+	for _, name := range fk.Cols {
+		if col := table.FindCol(child.Cols(), name.L); col != nil {
+			childCols = append(childCols, col)
+		}
+	}
+	if len(childCols) != len(fk.Cols) {
+		return nil
+	}
+	rel := &fkRelation{dbName: dbName, fk: fk, child: child, childCols: childCols}
+	if parent, err := is.TableByName(dbName, fk.RefTable); err == nil {
+		parentCols := make([]*table.Column, 0, len(fk.RefCols))
+		for _, name := range fk.RefCols {
+			if col := table.FindCol(parent.Cols(), name.L); col != nil {
+				parentCols = append(parentCols, col)
+			}
+		}
+		if len(parentCols) == len(fk.RefCols) {
+			rel.parent, rel.parentCols = parent, parentCols
+		}
+	}
+	return rel
+}
+
+// childFKRelations returns the foreign keys of the table to be enforced.
+func childFKRelations(sctx sessionctx.Context, t table.Table) []*fkRelation {
+	if len(t.Meta().ForeignKeys) == 0 {
+		return nil
+	}
+	is := fkInfoSchema(sctx)
+	if is == nil {
+		return nil
+	}
+	db, ok := is.SchemaByTable(t.Meta())
+	if !ok {
+		return nil
+	}
+	var rels []*fkRelation
+	for _, fk := range t.Meta().ForeignKeys {
+		// The foreign keys created before the enforcement was enabled are only metadata.
+		if fk.State != model.StatePublic || fk.Version < model.FKVersion1 {
+			continue
+		}
+		if rel := newFKRelation(is, db.Name, t, fk); rel != nil {
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// parentFKRelations returns the foreign keys to be enforced which refer to the table.
+func parentFKRelations(sctx sessionctx.Context, t table.Table) []*fkRelation {
+	is := fkInfoSchema(sctx)
+	if is == nil {
+		return nil
+	}
+	referredFKs := is.ReferredFKs(t.Meta().ID)
+	rels := make([]*fkRelation, 0, len(referredFKs))
+	for _, referredFK := range referredFKs {
+		if rel := newFKRelation(is, referredFK.ChildSchema, referredFK.ChildTable, referredFK.FK); rel != nil && rel.parent != nil {
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// isReferredByFK checks whether the table is referred by the foreign keys to be enforced.
+func isReferredByFK(sctx sessionctx.Context, t table.Table) bool {
+	is := fkInfoSchema(sctx)
+	return is != nil && len(is.ReferredFKs(t.Meta().ID)) > 0
+}
+
+// isForeignKeyError checks whether the error is a foreign key violation, which can be ignored by the IGNORE statements.
+func isForeignKeyError(err error) bool {
+	return table.ErrNoReferencedRow.Equal(err) || table.ErrRowIsReferenced.Equal(err)
+}
+
+// fkValues extracts the values of the columns from the row, hasNull reports whether any of them is NULL.
+func fkValues(row []types.Datum, cols []*table.Column) (vals []types.Datum, hasNull bool) {
+	vals = make([]types.Datum, len(cols))
+	for i, col := range cols {
+		if row[col.Offset].IsNull() {
+			return nil, true
+		}
+		vals[i] = row[col.Offset]
+	}
+	return vals, false
+}
+
+func fkColsModified(cols []*table.Column, modified []bool) bool {
+	for _, col := range cols {
+		if modified[col.Offset] {
+			return true
+		}
+	}
+	return false
+}
+
+// physicalTables returns the physical tables which store the rows of the table.
+func physicalTables(t table.Table) []table.PhysicalTable {
+	pt, ok := t.(table.PartitionedTable)
+	if !ok {
+		return []table.PhysicalTable{t.(table.PhysicalTable)}
+	}
+	defs := t.Meta().GetPartitionInfo().Definitions
+	tbls := make([]table.PhysicalTable, 0, len(defs))
+	for _, def := range defs {
+		tbls = append(tbls, pt.GetPartition(def.ID))
+	}
+	return tbls
+}
+
+// fkLookupRows returns the handles of the rows in the physical table whose cols equal vals,
+// it returns at most limit handles if limit > 0.
+// The index or the clustered primary key on the leading cols is used if possible, otherwise the table is scanned.
+func fkLookupRows(sctx sessionctx.Context, txn kv.Transaction, tbl table.PhysicalTable, cols []*table.Column,
+	vals []types.Datum, limit int) ([]kv.Handle, error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	tblInfo := tbl.Meta()
+	converted := make([]types.Datum, len(vals))
+	for i, col := range cols {
+		v, err := vals[i].ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			// The value can't be stored in the column, so no row matches it.
+			return nil, nil
+		}
+		v.SetCollation(col.Collate)
+		converted[i] = v
+	}
+
+	if len(cols) == 1 && cols[0].IsPKHandleColumn(tblInfo) {
+		h := kv.IntHandle(converted[0].GetInt64())
+		_, err := txn.Get(context.TODO(), tablecodec.EncodeRecordKey(tbl.RecordPrefix(), h))
+		if kv.IsErrNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []kv.Handle{h}, nil
+	}
+
+	for _, idx := range tbl.Indices() {
+		idxInfo := idx.Meta()
+		if idxInfo.State != model.StatePublic || idxInfo.Global || !fkIndexMatched(idxInfo, cols) {
+			continue
+		}
+		if idxInfo.Primary && tblInfo.IsCommonHandle {
+			// The clustered primary key is encoded in the record key.
+			prefix, err := codec.EncodeKey(sc, tbl.RecordPrefix().Clone(), converted...)
+			if err != nil {
+				return nil, err
+			}
+			return fkIterHandles(txn, prefix, limit, func(key kv.Key, _ []byte) (kv.Handle, error) {
+				return tablecodec.DecodeRowKey(key)
+			})
+		}
+		prefix, _, err := idx.GenIndexKey(sc, converted, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return fkIterHandles(txn, prefix, limit, func(key kv.Key, value []byte) (kv.Handle, error) {
+			return tablecodec.DecodeIndexHandle(key, value, len(idxInfo.Columns))
+		})
+	}
+
+	var handles []kv.Handle
+	err := iterTableRecords(sctx, txn, tbl, func(h kv.Handle, row []types.Datum) (bool, error) {
+		for i, col := range cols {
+			cmp, err := converted[i].CompareDatum(sc, &row[col.Offset])
+			if err != nil || cmp != 0 {
+				return true, err
+			}
+		}
+		handles = append(handles, h)
+		return limit <= 0 || len(handles) < limit, nil
+	})
+	return handles, err
+}
+
+// fkIndexMatched checks whether the leading columns of the index are exactly the foreign key columns.
+func fkIndexMatched(idxInfo *model.IndexInfo, cols []*table.Column) bool {
+	if len(idxInfo.Columns) < len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		idxCol := idxInfo.Columns[i]
+		if idxCol.Offset != col.Offset || idxCol.Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+func fkIterHandles(txn kv.Transaction, prefix kv.Key, limit int, decode func(key kv.Key, value []byte) (kv.Handle, error)) ([]kv.Handle, error) {
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var handles []kv.Handle
+	for it.Valid() && (limit <= 0 || len(handles) < limit) {
+		h, err := decode(it.Key(), it.Value())
+		if err != nil {
+			return nil, err
+		}
+		handles = append(handles, h)
+		if err = it.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return handles, nil
+}
+
+// iterTableRecords iterates the rows of the physical table visible to the transaction.
+func iterTableRecords(sctx sessionctx.Context, txn kv.Transaction, tbl table.PhysicalTable,
+	fn func(h kv.Handle, row []types.Datum) (more bool, err error)) error {
+	prefix := tbl.RecordPrefix()
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Valid() {
+		h, err := tablecodec.DecodeRowKey(it.Key())
+		if err != nil {
+			return err
+		}
+		row, _, err := tables.DecodeRawRowData(sctx, tbl.Meta(), h, tbl.Cols(), it.Value())
+		if err != nil {
+			return err
+		}
+		more, err := fn(h, row)
+		if err != nil || !more {
+			return err
+		}
+		if err = it.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fkShareLockSlots is the number of the lock keys which emulate the shared lock of a parent row.
+const fkShareLockSlots = 8
+
+// fkShareLockHashFactor is used to spread the start ts to the lock slots by the Fibonacci hashing,
+// the top 3 bits of the product are used as fkShareLockSlots is 8.
+const fkShareLockHashFactor uint64 = 11400714819323198485
+
+// fkShareLockKeyFlag is appended to the record key of the parent row to build its lock keys.
+const fkShareLockKeyFlag byte = 0xfe
+
+// fkShareLockKey returns the slot-th lock key of the parent row. The keys are only locked and never written,
+// they sort right after the record key and can't be decoded as a record key.
+func fkShareLockKey(physicalID int64, h kv.Handle, slot int) kv.Key {
+	key := tablecodec.EncodeRowKeyWithHandle(physicalID, h)
+	return append(key, fkShareLockKeyFlag, byte(slot))
+}
+
+// lockFKParentRowShared locks the parent row in share mode, so that it can't be deleted or updated by other
+// transactions before the current transaction commits, while the other child rows referring to it can still
+// be written concurrently. TiKV only provides exclusive locks, so the shared lock is emulated by locking any
+// one of the lock keys of the row, and the exclusive lock is taken by locking all of them, see lockFKParentRowExclusive.
+// The pessimistic transaction tries the lock keys without waiting, it only waits for the last one if all the others
+// are locked. For optimistic transactions, the lock is checked for write conflicts on commit.
+func lockFKParentRowShared(ctx context.Context, sctx sessionctx.Context, physicalID int64, h kv.Handle) error {
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	keys := make([]kv.Key, 0, fkShareLockSlots)
+	for slot := 0; slot < fkShareLockSlots; slot++ {
+		key := fkShareLockKey(physicalID, h, slot)
+		// The lock keys are never written, so they are in the memory buffer only if they are locked by the transaction.
+		if _, err := txn.GetMemBuffer().GetFlags(key); err == nil {
+			return nil
+		}
+		keys = append(keys, key)
+	}
+	// Start from the slot chosen by the start ts, so the transactions are likely to lock different keys.
+	first := int(txn.StartTS() * fkShareLockHashFactor >> 61)
+	vars := sctx.GetSessionVars()
+	if !vars.TxnCtx.IsPessimistic {
+		return doLockKeys(ctx, sctx, newLockCtx(vars, vars.LockWaitTimeout), keys[first])
+	}
+	for i := 0; i < fkShareLockSlots-1; i++ {
+		err = doLockKeys(ctx, sctx, newLockCtx(vars, tikv.LockNoWait), keys[(first+i)%fkShareLockSlots])
+		if !storeerr.ErrLockAcquireFailAndNoWaitSet.Equal(err) {
+			return err
+		}
+	}
+	return doLockKeys(ctx, sctx, newLockCtx(vars, vars.LockWaitTimeout), keys[(first+fkShareLockSlots-1)%fkShareLockSlots])
+}
+
+// lockFKParentRowExclusive locks all the lock keys of the parent row before it's deleted or its referred columns
+// are updated, so that it waits for the transactions which write the child rows referring to it.
+func lockFKParentRowExclusive(ctx context.Context, sctx sessionctx.Context, physicalID int64, h kv.Handle) error {
+	keys := make([]kv.Key, 0, fkShareLockSlots)
+	for slot := 0; slot < fkShareLockSlots; slot++ {
+		keys = append(keys, fkShareLockKey(physicalID, h, slot))
+	}
+	vars := sctx.GetSessionVars()
+	return doLockKeys(ctx, sctx, newLockCtx(vars, vars.LockWaitTimeout), keys...)
+}
+
+// fkPhysicalID returns the ID of the physical table which stores the row.
+func fkPhysicalID(sctx sessionctx.Context, t table.Table, row []types.Datum) (int64, error) {
+	pt, ok := t.(table.PartitionedTable)
+	if !ok {
+		return t.Meta().ID, nil
+	}
+	p, err := pt.GetPartitionByRow(sctx, row)
+	if err != nil {
+		return 0, err
+	}
+	return p.GetPhysicalID(), nil
+}
+
+// checkFKParentsExist checks the parent rows referred by the foreign keys of the row to be written exist,
+// only the foreign keys on the modified columns are checked if modified is not nil.
+func checkFKParentsExist(ctx context.Context, sctx sessionctx.Context, t table.Table, row []types.Datum, modified []bool) error {
+	rels := childFKRelations(sctx, t)
+	if len(rels) == 0 {
+		return nil
+	}
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	for _, rel := range rels {
+		if modified != nil && !fkColsModified(rel.childCols, modified) {
+			continue
+		}
+		vals, hasNull := fkValues(row, rel.childCols)
+		if hasNull {
+			continue
+		}
+		if rel.parent == nil {
+			return table.ErrNoReferencedRow.GenWithStackByArgs(rel.String())
+		}
+		if rel.parent.Meta().ID == t.Meta().ID {
+			// The row which refers to itself satisfies the foreign key.
+			selfVals, _ := fkValues(row, rel.parentCols)
+			if equal, err := fkValuesEqual(sc, selfVals, vals, rel.parentCols); err != nil {
+				return err
+			} else if equal {
+				continue
+			}
+		}
+		found := false
+		for _, pt := range physicalTables(rel.parent) {
+			handles, err := fkLookupRows(sctx, txn, pt, rel.parentCols, vals, 1)
+			if err != nil {
+				return err
+			}
+			if len(handles) > 0 {
+				if err = lockFKParentRowShared(ctx, sctx, pt.GetPhysicalID(), handles[0]); err != nil {
+					return err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return table.ErrNoReferencedRow.GenWithStackByArgs(rel.String())
+		}
+	}
+	return nil
+}
+
+func fkValuesEqual(sc *stmtctx.StatementContext, a, b []types.Datum, cols []*table.Column) (bool, error) {
+	if a == nil || b == nil {
+		return false, nil
+	}
+	for i, col := range cols {
+		x, y := a[i], b[i]
+		x.SetCollation(col.Collate)
+		y.SetCollation(col.Collate)
+		cmp, err := x.CompareDatum(sc, &y)
+		if err != nil || cmp != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// buildGeneratedExprs builds the expressions of the generated columns of the table in the order of WritableCols.
+func buildGeneratedExprs(sctx sessionctx.Context, t table.Table) ([]expression.Expression, error) {
+	var exprs []expression.Expression
+	for _, col := range t.WritableCols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(sctx, col.GeneratedExprString, t.Meta())
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// removeRecordWithFK removes the record and applies the ON DELETE actions of the foreign keys which refer to it.
+// For `DELETE IGNORE`, the row still referred by the child rows is left with a warning and removed is false.
+func removeRecordWithFK(ctx context.Context, sctx sessionctx.Context, t table.Table, h kv.Handle, row []types.Datum) (removed bool, err error) {
+	if !isReferredByFK(sctx, t) {
+		return true, t.RemoveRecord(sctx, h, row)
+	}
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return false, err
+	}
+	memBuffer := txn.GetMemBuffer()
+	sh := memBuffer.Staging()
+	defer memBuffer.Cleanup(sh)
+
+	if err = t.RemoveRecord(sctx, h, row); err != nil {
+		return false, err
+	}
+	physicalID, err := fkPhysicalID(sctx, t, row)
+	if err != nil {
+		return false, err
+	}
+	if err = onFKParentRowRemoved(ctx, sctx, t, physicalID, h, row, 0); err != nil {
+		sc := sctx.GetSessionVars().StmtCtx
+		if sc.DupKeyAsWarning && isForeignKeyError(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+	memBuffer.Release(sh)
+	return true, nil
+}
+
+// onFKParentRowRemoved applies the ON DELETE actions of the foreign keys which refer to the removed row,
+// which is stored in the physical table physicalID with the handle h.
+func onFKParentRowRemoved(ctx context.Context, sctx sessionctx.Context, t table.Table, physicalID int64, h kv.Handle,
+	row []types.Datum, depth int) error {
+	locked := false
+	for _, rel := range parentFKRelations(sctx, t) {
+		vals, hasNull := fkValues(row, rel.parentCols)
+		if hasNull {
+			continue
+		}
+		if !locked {
+			if err := lockFKParentRowExclusive(ctx, sctx, physicalID, h); err != nil {
+				return err
+			}
+			locked = true
+		}
+		if err := applyFKAction(ctx, sctx, rel, ast.ReferOptionType(rel.fk.OnDelete), vals, nil, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onFKParentRowUpdated applies the ON UPDATE actions of the foreign keys which refer to the modified columns of the updated row,
+// the old row is stored in the physical table physicalID with the handle h.
+func onFKParentRowUpdated(ctx context.Context, sctx sessionctx.Context, t table.Table, physicalID int64, h kv.Handle,
+	oldRow, newRow []types.Datum, modified []bool, depth int) error {
+	sc := sctx.GetSessionVars().StmtCtx
+	locked := false
+	for _, rel := range parentFKRelations(sctx, t) {
+		if !fkColsModified(rel.parentCols, modified) {
+			continue
+		}
+		oldVals, hasNull := fkValues(oldRow, rel.parentCols)
+		if hasNull {
+			continue
+		}
+		newVals := make([]types.Datum, len(rel.parentCols))
+		for i, col := range rel.parentCols {
+			newVals[i] = newRow[col.Offset]
+		}
+		// The child rows still match the parent row if the values are only changed in a way that the collation ignores.
+		if equal, err := fkValuesEqual(sc, oldVals, newVals, rel.parentCols); err != nil {
+			return err
+		} else if equal {
+			continue
+		}
+		if !locked {
+			if err := lockFKParentRowExclusive(ctx, sctx, physicalID, h); err != nil {
+				return err
+			}
+			locked = true
+		}
+		if err := applyFKAction(ctx, sctx, rel, ast.ReferOptionType(rel.fk.OnUpdate), oldVals, newVals, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyFKAction applies the referential action to the child rows which refer to the parent values vals.
+// newVals are the new values of the parent row for ON UPDATE, and nil for ON DELETE.
+func applyFKAction(ctx context.Context, sctx sessionctx.Context, rel *fkRelation, action ast.ReferOptionType,
+	vals, newVals []types.Datum, depth int) error {
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	if action != ast.ReferOptionCascade && action != ast.ReferOptionSetNull {
+		// RESTRICT, NO ACTION and SET DEFAULT reject the change of the parent row which is still referred.
+		for _, pt := range physicalTables(rel.child) {
+			handles, err := fkLookupRows(sctx, txn, pt, rel.childCols, vals, 1)
+			if err != nil {
+				return err
+			}
+			if len(handles) > 0 {
+				return table.ErrRowIsReferenced.GenWithStackByArgs(rel.String())
+			}
+		}
+		return nil
+	}
+
+	var genExprs []expression.Expression
+	for _, pt := range physicalTables(rel.child) {
+		handles, err := fkLookupRows(sctx, txn, pt, rel.childCols, vals, 0)
+		if err != nil {
+			return err
+		}
+		if len(handles) == 0 {
+			continue
+		}
+		if depth >= maxForeignKeyCascadeDepth {
+			return table.ErrForeignKeyCascadeDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+		}
+		if genExprs == nil {
+			if genExprs, err = buildGeneratedExprs(sctx, rel.child); err != nil {
+				return err
+			}
+		}
+		for _, h := range handles {
+			row, err := getOldRow(ctx, sctx, txn, pt, h, genExprs)
+			if err != nil {
+				return err
+			}
+			if action == ast.ReferOptionCascade && newVals == nil {
+				if err = rel.child.RemoveRecord(sctx, h, row); err != nil {
+					return err
+				}
+				if err = onFKParentRowRemoved(ctx, sctx, rel.child, pt.GetPhysicalID(), h, row, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			newRow := make([]types.Datum, len(row))
+			copy(newRow, row)
+			for i, col := range rel.childCols {
+				if action == ast.ReferOptionSetNull {
+					newRow[col.Offset].SetNull()
+					continue
+				}
+				if newRow[col.Offset], err = table.CastValue(sctx, newVals[i], col.ToInfo(), false, false); err != nil {
+					return err
+				}
+			}
+			if err = updateFKChildRow(ctx, sctx, rel.child, pt.GetPhysicalID(), h, row, newRow, genExprs, depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateFKChildRow writes the child row changed by the cascading action, and applies the actions of the foreign keys
+// which refer to it in turn. Unlike the rows changed by the statement, it's not counted in the affected rows.
+func updateFKChildRow(ctx context.Context, sctx sessionctx.Context, t table.Table, physicalID int64, h kv.Handle,
+	oldRow, newRow []types.Datum, genExprs []expression.Expression, depth int) error {
+	sc := sctx.GetSessionVars().StmtCtx
+	modified := make([]bool, len(newRow))
+	changed, handleChanged := false, false
+	gIdx := 0
+	for _, col := range t.WritableCols() {
+		if col.IsGenerated() {
+			val, err := genExprs[gIdx].Eval(chunk.MutRowFromDatums(newRow).ToRow())
+			if err != nil {
+				return err
+			}
+			if newRow[col.Offset], err = table.CastValue(sctx, val, col.ToInfo(), false, false); err != nil {
+				return err
+			}
+			gIdx++
+		}
+		if err := col.HandleBadNull(&newRow[col.Offset], sc); err != nil {
+			return err
+		}
+		cmp, err := newRow[col.Offset].CompareDatum(sc, &oldRow[col.Offset])
+		if err != nil {
+			return err
+		}
+		if cmp != 0 {
+			changed, modified[col.Offset] = true, true
+			if col.IsPKHandleColumn(t.Meta()) || col.IsCommonHandleColumn(t.Meta()) {
+				handleChanged = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := table.CheckRowConstraint(sctx, t, newRow); err != nil {
+		return err
+	}
+	if handleChanged {
+		if err := t.RemoveRecord(sctx, h, oldRow); err != nil {
+			return err
+		}
+		if _, err := t.AddRecord(sctx, newRow, table.IsUpdate, table.WithCtx(ctx)); err != nil {
+			return err
+		}
+	} else if err := t.UpdateRecord(ctx, sctx, h, oldRow, newRow, modified); err != nil {
+		return err
+	}
+	return onFKParentRowUpdated(ctx, sctx, t, physicalID, h, oldRow, newRow, modified, depth+1)
+}
@@ -0,0 +1,312 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite8) TestForeignKeyCheckParentExists(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key, name varchar(10))")
+	tk.MustExec("create table fk_child (id int primary key, pid int, index(pid), constraint fk_pid foreign key (pid) references fk_parent(id))")
+	tk.MustExec("insert into fk_parent values (1, 'a'), (2, 'b')")
+
+	// foreign_key_checks is disabled by default.
+	tk.MustExec("insert into fk_child values (100, 100)")
+	tk.MustExec("delete from fk_child")
+
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("insert into fk_child values (1, 1), (2, null)")
+	err := tk.ExecToErr("insert into fk_child values (3, 3)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:1452]Cannot add or update a child row: a foreign key constraint fails "+
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`))")
+	tk.MustExec("insert ignore into fk_child values (3, 3), (4, 2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`))"))
+	tk.MustQuery("select * from fk_child order by id").Check(testkit.Rows("1 1", "2 <nil>", "4 2"))
+
+	tk.MustGetErrCode("update fk_child set pid = 3 where id = 1", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("replace into fk_child values (1, 3)", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("insert into fk_child values (1, 1) on duplicate key update pid = 3", errno.ErrNoReferencedRow2)
+	tk.MustExec("update ignore fk_child set pid = 3 where id = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`))"))
+	tk.MustExec("update fk_child set pid = 2 where id = 1")
+	// Updating the other columns doesn't check the foreign key.
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("insert into fk_child values (5, 5)")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("update fk_child set id = 6 where id = 5")
+	tk.MustQuery("select * from fk_child order by id").Check(testkit.Rows("1 2", "2 <nil>", "4 2", "6 5"))
+
+	// The parent row inserted in the same transaction is visible.
+	tk.MustExec("begin")
+	tk.MustExec("insert into fk_parent values (3, 'c')")
+	tk.MustExec("insert into fk_child values (7, 3)")
+	tk.MustExec("commit")
+
+	// The referenced columns without an index and the child rows referring to the row itself.
+	tk.MustExec("drop table if exists fk_self")
+	tk.MustExec("create table fk_self (id int, pid int, foreign key (pid) references fk_self(id))")
+	tk.MustExec("insert into fk_self values (1, 1), (2, 1)")
+	tk.MustGetErrCode("insert into fk_self values (3, 4)", errno.ErrNoReferencedRow2)
+	tk.MustExec("insert into fk_self values (3, 2), (4, 3)")
+	tk.MustQuery("select * from fk_self order by id").Check(testkit.Rows("1 1", "2 1", "3 2", "4 3"))
+
+	// The multiple columns foreign key referring to the clustered primary key.
+	tk.MustExec("drop table if exists fk_child2, fk_parent2")
+	tk.MustExec("create table fk_parent2 (a varchar(10), b int, c int, primary key(a, b) clustered)")
+	tk.MustExec("create table fk_child2 (id int, a varchar(10), b int, foreign key fk_ab (a, b) references fk_parent2(a, b))")
+	tk.MustExec("insert into fk_parent2 values ('x', 1, 1), ('y', 2, 2)")
+	tk.MustExec("insert into fk_child2 values (1, 'x', 1), (2, 'y', null), (3, null, 3)")
+	tk.MustGetErrCode("insert into fk_child2 values (4, 'x', 2)", errno.ErrNoReferencedRow2)
+}
+
+func (s *testSuite8) TestForeignKeyRestrict(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int, name varchar(10), unique index(id))")
+	tk.MustExec("create table fk_child (id int primary key, pid int, constraint fk_pid foreign key (pid) references fk_parent(id) on delete restrict)")
+	tk.MustExec("insert into fk_parent values (1, 'a'), (2, 'b'), (3, 'c')")
+	tk.MustExec("insert into fk_child values (1, 1), (2, 2)")
+
+	tk.MustExec("set @@foreign_key_checks = 1")
+	err := tk.ExecToErr("delete from fk_parent where id = 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:1451]Cannot delete or update a parent row: a foreign key constraint fails "+
+		"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`) ON DELETE RESTRICT)")
+	tk.MustGetErrCode("update fk_parent set id = 10 where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("replace into fk_parent values (1, 'x')", errno.ErrRowIsReferenced2)
+	// The update which doesn't change the referenced columns is allowed.
+	tk.MustExec("update fk_parent set name = 'x' where id = 1")
+	tk.MustExec("update fk_parent set id = 30 where id = 3")
+
+	tk.MustExec("delete ignore from fk_parent")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from fk_parent order by id").Check(testkit.Rows("1 x", "2 b"))
+	tk.MustExec("update ignore fk_parent set id = id + 10")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1451 Cannot delete or update a parent row: a foreign key constraint fails "+
+			"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`) ON DELETE RESTRICT)",
+		"Warning 1451 Cannot delete or update a parent row: a foreign key constraint fails "+
+			"(`test`.`fk_child`, CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`) ON DELETE RESTRICT)"))
+	tk.MustQuery("select * from fk_parent order by id").Check(testkit.Rows("1 x", "2 b"))
+
+	tk.MustExec("delete from fk_child where id = 1")
+	tk.MustExec("delete from fk_parent where id = 1")
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("delete from fk_parent")
+	tk.MustQuery("select * from fk_child").Check(testkit.Rows("2 2"))
+}
+
+func (s *testSuite8) TestForeignKeyCascade(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("drop table if exists fk_grandchild, fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key, name varchar(10))")
+	tk.MustExec("create table fk_child (id int primary key, pid int, index(pid), foreign key (pid) references fk_parent(id) on delete cascade on update cascade)")
+	tk.MustExec("create table fk_grandchild (id int primary key, cid int, foreign key (cid) references fk_child(id) on delete set null on update cascade)")
+	tk.MustExec("insert into fk_parent values (1, 'a'), (2, 'b')")
+	tk.MustExec("insert into fk_child values (10, 1), (11, 1), (20, 2)")
+	tk.MustExec("insert into fk_grandchild values (100, 10), (110, 11), (200, 20)")
+
+	tk.MustExec("update fk_parent set id = 3 where id = 1")
+	tk.MustQuery("select * from fk_child order by id").Check(testkit.Rows("10 3", "11 3", "20 2"))
+	tk.MustExec("update fk_child set id = 21 where id = 20")
+	tk.MustQuery("select * from fk_grandchild order by id").Check(testkit.Rows("100 10", "110 11", "200 21"))
+
+	tk.MustExec("delete from fk_parent where id = 3")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from fk_parent").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select * from fk_child").Check(testkit.Rows("21 2"))
+	tk.MustQuery("select * from fk_grandchild order by id").Check(testkit.Rows("100 <nil>", "110 <nil>", "200 21"))
+	tk.MustQuery("select count(*) from fk_child use index(pid) where pid = 3").Check(testkit.Rows("0"))
+
+	// The actions are rolled back with the failed statement.
+	tk.MustExec("create table fk_restrict (id int, pid int, foreign key (pid) references fk_child(id))")
+	tk.MustExec("insert into fk_restrict values (1, 21)")
+	tk.MustGetErrCode("delete from fk_parent", errno.ErrRowIsReferenced2)
+	tk.MustQuery("select * from fk_child").Check(testkit.Rows("21 2"))
+	tk.MustQuery("select * from fk_grandchild order by id").Check(testkit.Rows("100 <nil>", "110 <nil>", "200 21"))
+	tk.MustExec("drop table fk_restrict")
+
+	// SET NULL on the column which can't be null.
+	tk.MustExec("drop table if exists fk_notnull")
+	tk.MustExec("create table fk_notnull (id int, pid int not null, foreign key (pid) references fk_parent(id) on delete set null)")
+	tk.MustExec("insert into fk_notnull values (1, 2)")
+	tk.MustGetErrCode("delete from fk_parent", errno.ErrBadNull)
+	tk.MustExec("drop table fk_notnull")
+
+	// The cascading actions on the self referencing table.
+	tk.MustExec("drop table if exists fk_tree")
+	tk.MustExec("create table fk_tree (id int primary key, pid int, foreign key (pid) references fk_tree(id) on delete cascade)")
+	tk.MustExec("insert into fk_tree values (1, null), (2, 1), (3, 1), (4, 2), (5, 4), (6, null)")
+	tk.MustExec("delete from fk_tree where id = 2")
+	tk.MustQuery("select * from fk_tree order by id").Check(testkit.Rows("1 <nil>", "3 1", "6 <nil>"))
+	tk.MustExec("delete from fk_tree where id = 1")
+	tk.MustQuery("select * from fk_tree order by id").Check(testkit.Rows("6 <nil>"))
+
+	// The depth of the nested cascading actions is limited.
+	tk.MustExec("delete from fk_tree")
+	tk.MustExec("insert into fk_tree values (0, null)")
+	for i := 1; i <= 16; i++ {
+		tk.MustExec(fmt.Sprintf("insert into fk_tree values (%d, %d)", i, i-1))
+	}
+	err := tk.ExecToErr("delete from fk_tree where id = 0")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[table:3008]Foreign key cascade delete/update exceeds max depth of 15.")
+	tk.MustExec("delete from fk_tree where id = 1")
+	tk.MustQuery("select * from fk_tree").Check(testkit.Rows("0 <nil>"))
+}
+
+func (s *testSuite8) TestForeignKeyLock(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key)")
+	tk.MustExec("create table fk_child (id int primary key, pid int, foreign key (pid) references fk_parent(id))")
+	tk.MustExec("insert into fk_parent values (1), (2)")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+
+	// The parent row referred by the pessimistic transaction is locked.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into fk_child values (1, 1)")
+	tk2.MustExec("set @@innodb_lock_wait_timeout = 1")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustGetErrCode("delete from fk_parent where id = 1", errno.ErrLockWaitTimeout)
+	tk2.MustExec("rollback")
+	tk.MustExec("commit")
+	tk2.MustGetErrCode("delete from fk_parent where id = 1", errno.ErrRowIsReferenced2)
+
+	// The parent row is locked in share mode, so the transactions referring to the same parent row don't block each other.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into fk_child values (3, 1)")
+	tk2.MustExec("begin pessimistic")
+	tk2.MustExec("insert into fk_child values (4, 1)")
+	tk2.MustExec("commit")
+	tk.MustExec("commit")
+	tk.MustExec("delete from fk_child where id in (3, 4)")
+
+}
+
+func (s *testSuite8) TestForeignKeyLockOptimistic(c *C) {
+	// The committed locks are checked for write conflicts by TiKV, which isn't supported by unistore.
+	store, err := mockstore.NewMockStore(mockstore.WithStoreType(mockstore.MockTiKV))
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(store.Close(), IsNil)
+	}()
+	session.SetSchemaLease(0)
+	session.DisableStats4Test()
+	dom, err := session.BootstrapSession(store)
+	c.Assert(err, IsNil)
+	defer dom.Close()
+
+	tk := testkit.NewTestKit(c, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("create table fk_parent (id int primary key)")
+	tk.MustExec("create table fk_child (id int primary key, pid int, foreign key (pid) references fk_parent(id))")
+	tk.MustExec("insert into fk_parent values (1), (2)")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk2 := testkit.NewTestKit(c, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+
+	tk.MustExec("insert into fk_child values (1, 1)")
+
+	// The optimistic transaction fails to commit if the referred parent row is changed.
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into fk_child values (3, 2)")
+	tk2.MustExec("delete from fk_parent where id = 2")
+	_, err = tk.Exec("commit")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select * from fk_child").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select * from fk_parent").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite8) TestForeignKeyChildIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("drop table if exists fk_child, fk_child2, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key, a int, b int, index(a, b))")
+
+	// The index is created for the foreign key columns if there isn't one.
+	tk.MustExec("create table fk_child (id int primary key, pid int, constraint fk_pid foreign key (pid) references fk_parent(id))")
+	tk.MustQuery("show create table fk_child").Check(testkit.Rows("fk_child CREATE TABLE `fk_child` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `pid` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `fk_pid` (`pid`),\n" +
+		"  CONSTRAINT `fk_pid` FOREIGN KEY (`pid`) REFERENCES `fk_parent` (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("alter table fk_child add column a int, add column b int")
+	tk.MustExec("alter table fk_child add constraint pid foreign key (a, b) references fk_parent(a, b)")
+	tk.MustQuery("show index from fk_child where Key_name != 'PRIMARY'").CheckAt([]int{2, 3, 4}, testkit.Rows(
+		"fk_pid 1 pid", "pid 1 a", "pid 2 b"))
+
+	// The existing index whose leading columns are the foreign key columns is used.
+	tk.MustExec("create table fk_child2 (id int primary key, a int, b int, foreign key (a) references fk_parent(a), index idx(a, b))")
+	tk.MustQuery("show index from fk_child2 where Key_name != 'PRIMARY'").CheckAt([]int{2, 3, 4}, testkit.Rows(
+		"idx 1 a", "idx 2 b"))
+	tk.MustExec("alter table fk_child2 add foreign key (id) references fk_parent(id)")
+	tk.MustQuery("show index from fk_child2 where Key_name != 'PRIMARY'").CheckAt([]int{2, 3, 4}, testkit.Rows(
+		"idx 1 a", "idx 2 b"))
+}
+
+func (s *testSuite8) TestForeignKeyMetadataOnly(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key)")
+	tk.MustExec("insert into fk_parent values (1)")
+
+	// The foreign keys created when tidb_enable_foreign_key is off are only metadata, like the ones
+	// created by the older versions, and no index is created for them.
+	tk.MustExec("create table fk_child (id int primary key, pid int, foreign key (pid) references fk_parent(id) on delete cascade)")
+	tk.MustQuery("show index from fk_child where Key_name != 'PRIMARY'").Check(testkit.Rows())
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("insert into fk_child values (1, 1), (2, 2)")
+	tk.MustExec("delete from fk_parent")
+	tk.MustQuery("select * from fk_child order by id").Check(testkit.Rows("1 1", "2 2"))
+
+	// The foreign keys added after it's turned on are enforced.
+	tk.MustExec("set @@tidb_enable_foreign_key = 1")
+	tk.MustExec("delete from fk_child")
+	tk.MustExec("alter table fk_child add column pid2 int")
+	tk.MustExec("alter table fk_child add constraint fk_pid2 foreign key (pid2) references fk_parent(id)")
+	tk.MustQuery("show index from fk_child where Key_name != 'PRIMARY'").CheckAt([]int{2, 3, 4}, testkit.Rows("fk_pid2 1 pid2"))
+	tk.MustExec("insert into fk_child values (3, 3, null)")
+	tk.MustGetErrCode("insert into fk_child values (4, null, 4)", errno.ErrNoReferencedRow2)
+}
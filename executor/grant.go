@@ -132,7 +132,7 @@ func (e *GrantExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			// It is required for compatibility with 5.7 but removed from 8.0
 			// since it results in a massive security issue:
 			// spelling errors will create users with no passwords.
-			pwd, ok := user.EncodedPassword()
+			pwd, ok := encodedPassword(user)
 			if !ok {
 				return errors.Trace(ErrPasswordFormat)
 			}
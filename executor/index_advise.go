@@ -15,14 +15,30 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // IndexAdviseExec represents a index advise executor.
@@ -122,13 +138,754 @@ func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	advisor := newIndexAdvisor(e)
+	indexes, err := advisor.advise(ctx, e.StmtNodes)
+	if err != nil {
+		return err
+	}
+	e.Result = newIndexAdvice(indexes)
 	return nil
 }
 
-// IndexAdvice represents the index advice.
+const (
+	// maxAdviseIndexColumns is the maximum number of columns of a candidate index.
+	maxAdviseIndexColumns = 3
+	// minAdviseIndexBenefit is the minimum workload cost reduction of a recommended index.
+	minAdviseIndexBenefit = 1e-6
+)
+
+// adviseQuery is a query of the workload whose cost is estimated by the optimizer.
+type adviseQuery struct {
+	stmt ast.StmtNode
+	is   infoschema.InfoSchema
+	// tables are the IDs of the tables referenced by the query.
+	tables map[int64]struct{}
+	// cost is the estimated cost with the indexes recommended so far.
+	cost float64
+}
+
+// adviseIndex is a hypothetical index which may reduce the cost of the workload.
+type adviseIndex struct {
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+	info    *model.IndexInfo
+	// benefit is the workload cost reduction when the index is added to the indexes recommended before it.
+	benefit float64
+}
+
+func (idx *adviseIndex) columnNames() string {
+	names := make([]string, 0, len(idx.info.Columns))
+	for _, col := range idx.info.Columns {
+		names = append(names, col.Name.O)
+	}
+	return strings.Join(names, ",")
+}
+
+func (idx *adviseIndex) createStmt() string {
+	cols := make([]string, 0, len(idx.info.Columns))
+	for _, col := range idx.info.Columns {
+		cols = append(cols, quoteIdentifier(col.Name.O))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s(%s)", quoteIdentifier(idx.info.Name.O),
+		quoteIdentifier(idx.dbName.O), quoteIdentifier(idx.tblInfo.Name.O), strings.Join(cols, ", "))
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// indexAdvisor recommends indexes for a workload. It enumerates candidate indexes from the
+// predicates, join keys and ORDER BY / GROUP BY columns of the workload, then greedily picks
+// the candidate which reduces the estimated workload cost most. The cost is estimated by the
+// optimizer with the candidates registered as hypothetical indexes, see SessionVars.HypoIndexes.
+type indexAdvisor struct {
+	sctx     sessionctx.Context
+	deadline time.Time
+	perTable uint64
+	perDB    uint64
+
+	queries    []*adviseQuery
+	candidates []*adviseIndex
+	// candidateKeys deduplicates the candidates by table ID and column names.
+	candidateKeys map[string]struct{}
+	// hypoIndexNames records the index names used by the candidates of every table.
+	hypoIndexNames map[int64]map[string]struct{}
+	// warnings are appended to the statement context of INDEX ADVISE.
+	warnings *stmtctx.StatementContext
+}
+
+func newIndexAdvisor(info *IndexAdviseInfo) *indexAdvisor {
+	a := &indexAdvisor{
+		sctx:           info.Ctx,
+		perTable:       ast.UnspecifiedSize,
+		perDB:          ast.UnspecifiedSize,
+		candidateKeys:  make(map[string]struct{}),
+		hypoIndexNames: make(map[int64]map[string]struct{}),
+		warnings:       info.Ctx.GetSessionVars().StmtCtx,
+	}
+	if info.MaxMinutes != ast.UnspecifiedSize && info.MaxMinutes < uint64(math.MaxInt64/int64(time.Minute)) {
+		a.deadline = time.Now().Add(time.Duration(info.MaxMinutes) * time.Minute)
+	}
+	if info.MaxIndexNum != nil {
+		a.perTable, a.perDB = info.MaxIndexNum.PerTable, info.MaxIndexNum.PerDB
+	}
+	return a
+}
+
+func (a *indexAdvisor) advise(ctx context.Context, stmtNodes [][]ast.StmtNode) ([]*adviseIndex, error) {
+	sessVars := a.sctx.GetSessionVars()
+	defer func() {
+		sessVars.StmtCtx = a.warnings
+		sessVars.HypoIndexes = nil
+	}()
+	for _, stmts := range stmtNodes {
+		for _, stmt := range stmts {
+			a.addQuery(ctx, stmt)
+		}
+	}
+	return a.selectIndexes(ctx)
+}
+
+// adviseSelect converts the statement to the SELECT statement which reads the same rows.
+// It returns nil if the statement can't benefit from indexes.
+func adviseSelect(stmt ast.StmtNode) ast.StmtNode {
+	wildcard := &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}}
+	switch x := stmt.(type) {
+	case *ast.SelectStmt:
+		if x.SelectIntoOpt != nil || x.From == nil {
+			return nil
+		}
+		return x
+	case *ast.SetOprStmt:
+		return x
+	case *ast.UpdateStmt:
+		return &ast.SelectStmt{Fields: wildcard, From: x.TableRefs, Where: x.Where, OrderBy: x.Order, Limit: x.Limit}
+	case *ast.DeleteStmt:
+		return &ast.SelectStmt{Fields: wildcard, From: x.TableRefs, Where: x.Where, OrderBy: x.Order, Limit: x.Limit}
+	}
+	return nil
+}
+
+// addQuery estimates the cost of the statement and collects the candidate indexes from it.
+// Statements which can't be planned are skipped with a warning.
+func (a *indexAdvisor) addQuery(ctx context.Context, stmt ast.StmtNode) {
+	node := adviseSelect(stmt)
+	if node == nil {
+		return
+	}
+	a.resetStmtCtx()
+	ret := &plannercore.PreprocessorReturn{}
+	if err := plannercore.Preprocess(a.sctx, node, plannercore.WithPreprocessorReturn(ret)); err != nil {
+		a.warnings.AppendWarning(errors.Errorf("Index Advise: skip statement '%s': %v", stmt.Text(), err))
+		return
+	}
+	q := &adviseQuery{stmt: node, is: ret.InfoSchema, tables: make(map[int64]struct{})}
+	cost, err := a.queryCost(ctx, q)
+	if err != nil {
+		a.warnings.AppendWarning(errors.Errorf("Index Advise: skip statement '%s': %v", stmt.Text(), err))
+		return
+	}
+	q.cost = cost
+
+	collector := &adviseColumnCollector{}
+	node.Accept(collector)
+	for _, usage := range collector.usages {
+		q.tables[usage.tbl.tblInfo.ID] = struct{}{}
+		a.addCandidates(usage)
+	}
+	if len(q.tables) > 0 {
+		a.queries = append(a.queries, q)
+	}
+}
+
+func (a *indexAdvisor) addCandidates(usage *adviseTableUsage) {
+	eqCols := usage.eqCols
+	if len(eqCols) > maxAdviseIndexColumns {
+		eqCols = eqCols[:maxAdviseIndexColumns]
+	}
+	for _, cols := range [][]*model.ColumnInfo{usage.eqCols, usage.rangeCols, usage.orderCols} {
+		for _, col := range cols {
+			a.addCandidate(usage.tbl, []*model.ColumnInfo{col})
+		}
+	}
+	for i := 2; i <= len(eqCols); i++ {
+		a.addCandidate(usage.tbl, eqCols[:i])
+	}
+	prefix := eqCols
+	if len(prefix) == maxAdviseIndexColumns {
+		prefix = prefix[:maxAdviseIndexColumns-1]
+	}
+	for _, col := range usage.rangeCols {
+		cols := make([]*model.ColumnInfo, 0, len(prefix)+1)
+		a.addCandidate(usage.tbl, append(append(cols, prefix...), col))
+	}
+	if len(usage.orderCols) > 0 {
+		cols := make([]*model.ColumnInfo, 0, len(eqCols)+len(usage.orderCols))
+		a.addCandidate(usage.tbl, append(append(cols, eqCols...), usage.orderCols...))
+		a.addCandidate(usage.tbl, usage.orderCols)
+	}
+}
+
+func (a *indexAdvisor) addCandidate(tbl *adviseTable, cols []*model.ColumnInfo) {
+	tblInfo := tbl.tblInfo
+	idxCols := make([]*model.IndexColumn, 0, maxAdviseIndexColumns)
+	names := make([]string, 0, maxAdviseIndexColumns)
+	for _, col := range cols {
+		if len(idxCols) == maxAdviseIndexColumns {
+			break
+		}
+		// Columns of these types can only be indexed with a prefix length, skip them.
+		if types.IsTypeBlob(col.Tp) || col.Tp == mysql.TypeJSON {
+			return
+		}
+		if findIndexColumnByName(idxCols, col.Name.L) != nil {
+			continue
+		}
+		idxCols = append(idxCols, &model.IndexColumn{Name: col.Name, Offset: col.Offset, Length: types.UnspecifiedLength})
+		names = append(names, col.Name.L)
+	}
+	if len(idxCols) == 1 && tblInfo.PKIsHandle && mysql.HasPriKeyFlag(cols[0].Flag) {
+		return
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State == model.StatePublic && isIndexColumnsPrefix(idxCols, idx.Columns) {
+			return
+		}
+	}
+	key := fmt.Sprintf("%d:%s", tblInfo.ID, strings.Join(names, ","))
+	if _, ok := a.candidateKeys[key]; ok {
+		return
+	}
+	a.candidateKeys[key] = struct{}{}
+
+	usedNames, ok := a.hypoIndexNames[tblInfo.ID]
+	if !ok {
+		usedNames = make(map[string]struct{})
+		a.hypoIndexNames[tblInfo.ID] = usedNames
+	}
+	baseName := "idx_" + strings.Join(names, "_")
+	if len(baseName) > mysql.MaxIndexIdentifierLen-4 {
+		baseName = baseName[:mysql.MaxIndexIdentifierLen-4]
+	}
+	name := baseName
+	for i := 1; ; i++ {
+		_, used := usedNames[name]
+		if !used && tblInfo.FindIndexByName(name) == nil {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", baseName, i)
+	}
+	usedNames[name] = struct{}{}
+
+	a.candidates = append(a.candidates, &adviseIndex{
+		dbName:  tbl.dbName,
+		tblInfo: tblInfo,
+		info: &model.IndexInfo{
+			// Hypothetical indexes take the IDs after the existing indexes, so the optimizer
+			// won't find statistics for them and estimates their row count by the table statistics.
+			ID:      tblInfo.MaxIndexID + int64(len(usedNames)),
+			Name:    model.NewCIStr(name),
+			Table:   tblInfo.Name,
+			Columns: idxCols,
+			State:   model.StatePublic,
+			Tp:      model.IndexTypeBtree,
+		},
+	})
+}
+
+func findIndexColumnByName(cols []*model.IndexColumn, nameL string) *model.IndexColumn {
+	for _, col := range cols {
+		if col.Name.L == nameL {
+			return col
+		}
+	}
+	return nil
+}
+
+// isIndexColumnsPrefix checks whether cols is the prefix of the index columns idxCols.
+func isIndexColumnsPrefix(cols, idxCols []*model.IndexColumn) bool {
+	if len(cols) > len(idxCols) {
+		return false
+	}
+	for i, col := range cols {
+		if idxCols[i].Name.L != col.Name.L || idxCols[i].Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+// selectIndexes picks the candidates greedily. In each round, the candidate which reduces the
+// workload cost most is added to the result, until no candidate reduces the cost, the limits of
+// index number are reached or the time runs out.
+func (a *indexAdvisor) selectIndexes(ctx context.Context) ([]*adviseIndex, error) {
+	sessVars := a.sctx.GetSessionVars()
+	remained := make([]*adviseIndex, len(a.candidates))
+	copy(remained, a.candidates)
+	var selected []*adviseIndex
+	tableIndexNum := make(map[int64]uint64)
+	dbIndexNum := make(map[string]uint64)
+	for len(remained) > 0 {
+		if !a.deadline.IsZero() && time.Now().After(a.deadline) {
+			a.warnings.AppendWarning(errors.New("Index Advise: the maximum execution time is reached, the result may be incomplete"))
+			break
+		}
+		bestIdx, bestBenefit := -1, float64(minAdviseIndexBenefit)
+		var bestCosts map[*adviseQuery]float64
+		for i, cand := range remained {
+			if atomic.LoadUint32(&sessVars.Killed) == 1 {
+				return nil, ErrQueryInterrupted
+			}
+			if tableIndexNum[cand.tblInfo.ID] >= a.perTable || dbIndexNum[cand.dbName.L] >= a.perDB {
+				continue
+			}
+			benefit, costs, err := a.evaluate(ctx, selected, cand)
+			if err != nil {
+				return nil, err
+			}
+			if benefit > bestBenefit {
+				bestIdx, bestBenefit, bestCosts = i, benefit, costs
+			}
+		}
+		if bestIdx < 0 {
+			break
+		}
+		best := remained[bestIdx]
+		best.benefit = bestBenefit
+		for q, cost := range bestCosts {
+			q.cost = cost
+		}
+		selected = append(selected, best)
+		tableIndexNum[best.tblInfo.ID]++
+		dbIndexNum[best.dbName.L]++
+		remained = append(remained[:bestIdx], remained[bestIdx+1:]...)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].benefit > selected[j].benefit
+	})
+	return selected, nil
+}
+
+// evaluate estimates the workload cost reduction if cand is added to the selected indexes.
+// It also returns the new costs of the queries referencing the table of cand.
+func (a *indexAdvisor) evaluate(ctx context.Context, selected []*adviseIndex, cand *adviseIndex) (float64, map[*adviseQuery]float64, error) {
+	hypoIndexes := make(map[int64][]*model.IndexInfo)
+	for _, idx := range selected {
+		hypoIndexes[idx.tblInfo.ID] = append(hypoIndexes[idx.tblInfo.ID], idx.info)
+	}
+	hypoIndexes[cand.tblInfo.ID] = append(hypoIndexes[cand.tblInfo.ID], cand.info)
+	a.sctx.GetSessionVars().HypoIndexes = hypoIndexes
+
+	var benefit float64
+	costs := make(map[*adviseQuery]float64)
+	for _, q := range a.queries {
+		if _, ok := q.tables[cand.tblInfo.ID]; !ok {
+			continue
+		}
+		cost, err := a.queryCost(ctx, q)
+		if err != nil {
+			return 0, nil, err
+		}
+		costs[q] = cost
+		benefit += q.cost - cost
+	}
+	return benefit, costs, nil
+}
+
+func (a *indexAdvisor) queryCost(ctx context.Context, q *adviseQuery) (float64, error) {
+	a.resetStmtCtx()
+	_, cost, err := planner.OptimizeWithCost(ctx, a.sctx, q.stmt, q.is)
+	return cost, err
+}
+
+// resetStmtCtx sets a new statement context for planning a query of the workload, the
+// flags are the same as the ones of a SELECT statement, see ResetContextOfStmt.
+func (a *indexAdvisor) resetStmtCtx() {
+	sessVars := a.sctx.GetSessionVars()
+	sc := &stmtctx.StatementContext{
+		TimeZone:          sessVars.Location(),
+		InSelectStmt:      true,
+		OverflowAsWarning: true,
+		TruncateAsWarning: true,
+		IgnoreZeroInDate:  true,
+		AllowInvalidDate:  sessVars.SQLMode.HasAllowInvalidDatesMode(),
+	}
+	// The cost model reads the memory quota, the tracker is not attached to the global tracker
+	// because the plans are never executed.
+	sc.InitMemTracker(memory.LabelForSQLText, sessVars.MemQuotaQuery)
+	sc.InitDiskTracker(memory.LabelForSQLText, -1)
+	sessVars.StmtCtx = sc
+}
+
+// adviseTable is a table in the FROM clause of a query block.
+type adviseTable struct {
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+}
+
+// adviseTableUsage records how a table of a query block is accessed.
+type adviseTableUsage struct {
+	tbl *adviseTable
+	// eqCols are the columns compared by equal conditions with constants or columns of other tables.
+	eqCols []*model.ColumnInfo
+	// rangeCols are the columns filtered by range conditions.
+	rangeCols []*model.ColumnInfo
+	// orderCols are the ORDER BY or GROUP BY columns.
+	orderCols []*model.ColumnInfo
+}
+
+func appendColumnIfAbsent(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	for _, c := range cols {
+		if c == col {
+			return cols
+		}
+	}
+	return append(cols, col)
+}
+
+// adviseScope resolves the column names in a query block to the tables in its FROM clause.
+type adviseScope struct {
+	// usages are keyed by the lower case table alias, the usage is nil for derived tables and
+	// tables which can't be indexed.
+	usages map[string]*adviseTableUsage
+	names  []string
+}
+
+func (s *adviseScope) add(name string, usage *adviseTableUsage) {
+	if _, ok := s.usages[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.usages[name] = usage
+}
+
+// resolve returns the usage and the column info of the column name. The returned bool is false if
+// the name can't be found in the scope, so the outer scopes should be searched.
+func (s *adviseScope) resolve(name *ast.ColumnName) (*adviseTableUsage, *model.ColumnInfo, bool) {
+	if name.Table.L != "" {
+		usage, ok := s.usages[name.Table.L]
+		if !ok {
+			return nil, nil, false
+		}
+		if usage == nil || (name.Schema.L != "" && name.Schema.L != usage.tbl.dbName.L) {
+			return nil, nil, true
+		}
+		col := model.FindColumnInfo(usage.tbl.tblInfo.Cols(), name.Name.L)
+		if col == nil {
+			return nil, nil, true
+		}
+		return usage, col, true
+	}
+	var (
+		resUsage *adviseTableUsage
+		resCol   *model.ColumnInfo
+	)
+	for _, n := range s.names {
+		usage := s.usages[n]
+		if usage == nil {
+			continue
+		}
+		if col := model.FindColumnInfo(usage.tbl.tblInfo.Cols(), name.Name.L); col != nil {
+			if resCol != nil {
+				// The column name is ambiguous.
+				return nil, nil, true
+			}
+			resUsage, resCol = usage, col
+		}
+	}
+	return resUsage, resCol, resCol != nil
+}
+
+// adviseColumnCollector collects the table usages of all the query blocks of a statement.
+type adviseColumnCollector struct {
+	scopes []*adviseScope
+	usages []*adviseTableUsage
+}
+
+// Enter implements ast.Visitor interface.
+func (c *adviseColumnCollector) Enter(in ast.Node) (ast.Node, bool) {
+	sel, ok := in.(*ast.SelectStmt)
+	if !ok {
+		return in, false
+	}
+	scope := &adviseScope{usages: make(map[string]*adviseTableUsage)}
+	c.scopes = append(c.scopes, scope)
+	if sel.From == nil {
+		return in, false
+	}
+	var conds []ast.ExprNode
+	var usingCols []*ast.ColumnName
+	c.addTableRefs(scope, sel.From.TableRefs, &conds, &usingCols)
+	for _, name := range usingCols {
+		for _, n := range scope.names {
+			usage := scope.usages[n]
+			if usage == nil {
+				continue
+			}
+			if col := model.FindColumnInfo(usage.tbl.tblInfo.Cols(), name.Name.L); col != nil {
+				usage.eqCols = appendColumnIfAbsent(usage.eqCols, col)
+			}
+		}
+	}
+	if sel.Where != nil {
+		conds = append(conds, sel.Where)
+	}
+	for _, cond := range conds {
+		c.collectCond(cond)
+	}
+	if sel.OrderBy != nil {
+		c.collectOrder(sel.OrderBy.Items)
+	} else if sel.GroupBy != nil {
+		c.collectOrder(sel.GroupBy.Items)
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *adviseColumnCollector) Leave(in ast.Node) (ast.Node, bool) {
+	if _, ok := in.(*ast.SelectStmt); ok {
+		c.scopes = c.scopes[:len(c.scopes)-1]
+	}
+	return in, true
+}
+
+func (c *adviseColumnCollector) addTableRefs(scope *adviseScope, node ast.ResultSetNode, conds *[]ast.ExprNode, usingCols *[]*ast.ColumnName) {
+	switch x := node.(type) {
+	case *ast.Join:
+		c.addTableRefs(scope, x.Left, conds, usingCols)
+		if x.Right != nil {
+			c.addTableRefs(scope, x.Right, conds, usingCols)
+		}
+		if x.On != nil {
+			*conds = append(*conds, x.On.Expr)
+		}
+		*usingCols = append(*usingCols, x.Using...)
+	case *ast.TableSource:
+		tn, ok := x.Source.(*ast.TableName)
+		name := x.AsName.L
+		if name == "" && ok {
+			name = tn.Name.L
+		}
+		if name == "" {
+			return
+		}
+		if !ok || tn.TableInfo == nil || tn.TableInfo.IsView() || tn.TableInfo.IsSequence() || util.IsMemOrSysDB(tn.Schema.L) {
+			scope.add(name, nil)
+			return
+		}
+		usage := &adviseTableUsage{tbl: &adviseTable{dbName: tn.Schema, tblInfo: tn.TableInfo}}
+		scope.add(name, usage)
+		c.usages = append(c.usages, usage)
+	}
+}
+
+func (c *adviseColumnCollector) resolveColumn(expr ast.ExprNode) (*adviseTableUsage, *model.ColumnInfo) {
+	for {
+		p, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	colExpr, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if usage, col, found := c.scopes[i].resolve(colExpr.Name); found {
+			return usage, col
+		}
+	}
+	return nil, nil
+}
+
+func (c *adviseColumnCollector) collectCond(expr ast.ExprNode) {
+	switch x := expr.(type) {
+	case *ast.ParenthesesExpr:
+		c.collectCond(x.Expr)
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.LogicAnd:
+			c.collectCond(x.L)
+			c.collectCond(x.R)
+		case opcode.EQ, opcode.NullEQ:
+			lUsage, lCol := c.resolveColumn(x.L)
+			rUsage, rCol := c.resolveColumn(x.R)
+			if lCol != nil && rCol != nil {
+				if lUsage != rUsage {
+					lUsage.eqCols = appendColumnIfAbsent(lUsage.eqCols, lCol)
+					rUsage.eqCols = appendColumnIfAbsent(rUsage.eqCols, rCol)
+				}
+			} else if lCol != nil && !hasColumnRef(x.R) {
+				lUsage.eqCols = appendColumnIfAbsent(lUsage.eqCols, lCol)
+			} else if rCol != nil && !hasColumnRef(x.L) {
+				rUsage.eqCols = appendColumnIfAbsent(rUsage.eqCols, rCol)
+			}
+		case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+			if usage, col := c.resolveColumn(x.L); col != nil && !hasColumnRef(x.R) {
+				usage.rangeCols = appendColumnIfAbsent(usage.rangeCols, col)
+			} else if usage, col := c.resolveColumn(x.R); col != nil && !hasColumnRef(x.L) {
+				usage.rangeCols = appendColumnIfAbsent(usage.rangeCols, col)
+			}
+		}
+	case *ast.PatternInExpr:
+		if x.Not || (x.Sel == nil && hasColumnRef(x.List...)) {
+			return
+		}
+		if usage, col := c.resolveColumn(x.Expr); col != nil {
+			usage.eqCols = appendColumnIfAbsent(usage.eqCols, col)
+		}
+	case *ast.IsNullExpr:
+		if x.Not {
+			return
+		}
+		if usage, col := c.resolveColumn(x.Expr); col != nil {
+			usage.eqCols = appendColumnIfAbsent(usage.eqCols, col)
+		}
+	case *ast.BetweenExpr:
+		if x.Not || hasColumnRef(x.Left, x.Right) {
+			return
+		}
+		if usage, col := c.resolveColumn(x.Expr); col != nil {
+			usage.rangeCols = appendColumnIfAbsent(usage.rangeCols, col)
+		}
+	case *ast.PatternLikeExpr:
+		// Only the pattern with a constant prefix can be converted to a range.
+		v, ok := x.Pattern.(ast.ValueExpr)
+		if x.Not || !ok {
+			return
+		}
+		pattern, ok := v.GetValue().(string)
+		if !ok || len(pattern) == 0 || pattern[0] == '%' || pattern[0] == '_' || pattern[0] == x.Escape {
+			return
+		}
+		if usage, col := c.resolveColumn(x.Expr); col != nil {
+			usage.rangeCols = appendColumnIfAbsent(usage.rangeCols, col)
+		}
+	}
+}
+
+// collectOrder collects the ORDER BY or GROUP BY items, they are collected only if all the items
+// are columns of the same table.
+func (c *adviseColumnCollector) collectOrder(items []*ast.ByItem) {
+	var (
+		usage *adviseTableUsage
+		cols  []*model.ColumnInfo
+	)
+	for _, item := range items {
+		u, col := c.resolveColumn(item.Expr)
+		if col == nil || (usage != nil && u != usage) {
+			return
+		}
+		usage = u
+		cols = appendColumnIfAbsent(cols, col)
+	}
+	if usage != nil && len(usage.orderCols) == 0 {
+		usage.orderCols = cols
+	}
+}
+
+// columnRefChecker checks whether an expression references any column. Subqueries are
+// treated as constants.
+type columnRefChecker struct {
+	hasColumnRef bool
+}
+
+// Enter implements ast.Visitor interface.
+func (c *columnRefChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.ColumnNameExpr:
+		c.hasColumnRef = true
+		return in, true
+	case *ast.SubqueryExpr:
+		return in, true
+	}
+	return in, c.hasColumnRef
+}
+
+// Leave implements ast.Visitor interface.
+func (c *columnRefChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func hasColumnRef(exprs ...ast.ExprNode) bool {
+	checker := &columnRefChecker{}
+	for _, expr := range exprs {
+		expr.Accept(checker)
+		if checker.hasColumnRef {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexAdvice represents the index advice. It implements the sqlexec.RecordSet interface,
+// every row is a recommended index, ordered by the estimated benefit descending.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	fields     []*ast.ResultField
+	fieldTypes []*types.FieldType
+	rows       *chunk.Chunk
+	cursor     int
+}
+
+func newIndexAdvice(indexes []*adviseIndex) *IndexAdvice {
+	adv := &IndexAdvice{}
+	addField := func(name string, tp byte, flen int) {
+		ft := types.NewFieldType(tp)
+		ft.Flen = flen
+		if tp == mysql.TypeVarchar {
+			ft.Charset, ft.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+		} else {
+			ft.Charset, ft.Collate = charset.CharsetBin, charset.CollationBin
+		}
+		adv.fieldTypes = append(adv.fieldTypes, ft)
+		adv.fields = append(adv.fields, &ast.ResultField{
+			Column:       &model.ColumnInfo{Name: model.NewCIStr(name), FieldType: *ft},
+			ColumnAsName: model.NewCIStr(name),
+			Table:        &model.TableInfo{},
+		})
+	}
+	addField("Database", mysql.TypeVarchar, mysql.MaxDatabaseNameLength)
+	addField("Table", mysql.TypeVarchar, mysql.MaxTableNameLength)
+	addField("Index_name", mysql.TypeVarchar, mysql.MaxIndexIdentifierLen)
+	addField("Index_columns", mysql.TypeVarchar, 256)
+	addField("Est_benefit", mysql.TypeDouble, mysql.MaxRealWidth)
+	addField("Create_statement", mysql.TypeVarchar, 512)
+
+	adv.rows = chunk.NewChunkWithCapacity(adv.fieldTypes, len(indexes))
+	for _, idx := range indexes {
+		adv.rows.AppendString(0, idx.dbName.O)
+		adv.rows.AppendString(1, idx.tblInfo.Name.O)
+		adv.rows.AppendString(2, idx.info.Name.O)
+		adv.rows.AppendString(3, idx.columnNames())
+		adv.rows.AppendFloat64(4, idx.benefit)
+		adv.rows.AppendString(5, idx.createStmt())
+	}
+	return adv
+}
+
+// Fields implements the sqlexec.RecordSet Fields interface.
+func (adv *IndexAdvice) Fields() []*ast.ResultField {
+	return adv.fields
+}
+
+// Next implements the sqlexec.RecordSet Next interface.
+func (adv *IndexAdvice) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() && adv.cursor < adv.rows.NumRows() {
+		req.AppendRow(adv.rows.GetRow(adv.cursor))
+		adv.cursor++
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet NewChunk interface.
+func (adv *IndexAdvice) NewChunk() *chunk.Chunk {
+	return chunk.New(adv.fieldTypes, variable.DefInitChunkSize, variable.DefMaxChunkSize)
+}
+
+// Close implements the sqlexec.RecordSet Close interface.
+func (adv *IndexAdvice) Close() error {
+	return nil
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
@@ -14,7 +14,10 @@
 package executor_test
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/executor"
@@ -63,5 +66,75 @@ func (s *testSuite1) TestIndexAdvise(c *C) {
 	c.Assert(ia.MaxMinutes, Equals, uint64(3))
 	c.Assert(ia.MaxIndexNum.PerTable, Equals, uint64(4))
 	c.Assert(ia.MaxIndexNum.PerDB, Equals, uint64(5))
+}
+
+func (s *testSuite1) TestIndexAdviseResult(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1, t2")
+	tk.MustExec("create table t(a int, b int, c int, d text, key idx_c(c))")
+	tk.MustExec("create table t1(a int primary key, b int)")
+	tk.MustExec("create table t2(a int, b int, c int)")
+	var tVals, t1Vals, t2Vals []string
+	for i := 0; i < 1000; i++ {
+		tVals = append(tVals, fmt.Sprintf("(%d, %d, %d, 'x')", i%2, i, i))
+		t2Vals = append(t2Vals, fmt.Sprintf("(%d, %d, %d)", i%10, i, i))
+		if i < 100 {
+			t1Vals = append(t1Vals, fmt.Sprintf("(%d, %d)", i, i))
+		}
+	}
+	tk.MustExec("insert into t values " + strings.Join(tVals, ","))
+	tk.MustExec("insert into t1 values " + strings.Join(t1Vals, ","))
+	tk.MustExec("insert into t2 values " + strings.Join(t2Vals, ","))
+	tk.MustExec("analyze table t, t1, t2")
+
+	getAdvice := func(workload string, maxIdxNum string) [][]interface{} {
+		tk.MustExec("index advise local infile '/tmp/index_advise.sql' max_minutes 3" + maxIdxNum)
+		ctx := tk.Se.(sessionctx.Context)
+		ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+		c.Assert(ok, IsTrue)
+		ctx.SetValue(executor.IndexAdviseVarKey, nil)
+		c.Assert(ia.GetIndexAdvice(context.Background(), []byte(workload)), IsNil)
+		rows := tk.ResultSetToResult(ia.Result, Commentf("workload %s", workload)).Rows()
+		res := make([][]interface{}, 0, len(rows))
+		for _, row := range rows {
+			// Skip the estimated benefit, it depends on the cost model.
+			res = append(res, []interface{}{row[0], row[1], row[2], row[3], row[5]})
+		}
+		return res
+	}
+
+	workload := "select * from t where a = 1 and b > 990;\n" +
+		"select * from t where a = 0 and b < 5;\n" +
+		"select * from t1 join t2 on t1.b = t2.c where t1.a > 95;\n" +
+		"select * from t where c = 1;\n" +
+		"update t set c = 1 where a = 1 and b = 3;\n" +
+		"select * from t where d = 'x';\n" +
+		"select * from not_exist where a = 1;\n" +
+		"create table t3(a int);\n"
+	res := getAdvice(workload, "")
+	c.Assert(res, DeepEquals, [][]interface{}{
+		{"test", "t", "idx_a_b", "a,b", "CREATE INDEX `idx_a_b` ON `test`.`t`(`a`, `b`)"},
+		{"test", "t2", "idx_c", "c", "CREATE INDEX `idx_c` ON `test`.`t2`(`c`)"},
+	})
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 Index Advise: skip statement 'select * from not_exist where a = 1;': [schema:1146]Table 'test.not_exist' doesn't exist"))
+
+	// The number of indexes is limited by max_idxnum.
+	res = getAdvice(workload, " max_idxnum per_table 1 per_db 1")
+	c.Assert(res, DeepEquals, [][]interface{}{
+		{"test", "t", "idx_a_b", "a,b", "CREATE INDEX `idx_a_b` ON `test`.`t`(`a`, `b`)"},
+	})
+
+	// The candidates covered by the existing indexes are ignored.
+	tk.MustExec("alter table t add index idx_a_b(a, b)")
+	res = getAdvice(workload, "")
+	c.Assert(res, DeepEquals, [][]interface{}{
+		{"test", "t2", "idx_c", "c", "CREATE INDEX `idx_c` ON `test`.`t2`(`c`)"},
+	})
 
+	// The ORDER BY columns are also considered.
+	res = getAdvice("select * from t2 where a = 1 order by b limit 10;\n", "")
+	c.Assert(res, DeepEquals, [][]interface{}{
+		{"test", "t2", "idx_a_b", "a,b", "CREATE INDEX `idx_a_b` ON `test`.`t2`(`a`, `b`)"},
+	})
 }
@@ -1108,6 +1108,22 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
+	if err = table.CheckRowConstraint(e.ctx, e.Table, row); err != nil {
+		// For `INSERT IGNORE`, the row violating the check constraints is skipped with a warning.
+		if vars.StmtCtx.DupKeyAsWarning && table.ErrCheckConstraintViolated.Equal(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
+	if err = checkFKParentsExist(ctx, e.ctx, e.Table, row, nil); err != nil {
+		// For `INSERT IGNORE`, the row referring to a missing parent row is skipped with a warning.
+		if vars.StmtCtx.DupKeyAsWarning && isForeignKeyError(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
@@ -0,0 +1,178 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var _ Executor = &JSONTableExec{}
+
+// JSONTableExec is the executor of JSON_TABLE. The JSON document is evaluated when the executor is opened,
+// because it may refer to the outer row of an Apply, which reopens the executor for every outer row.
+type JSONTableExec struct {
+	baseExecutor
+
+	asName  model.CIStr
+	expr    expression.Expression
+	path    json.PathExpression
+	columns []*plannercore.JSONTableColumn
+
+	// sc is used to convert the JSON values to the column types. Unlike the statement context, it
+	// returns an error for a truncated value, which is handled by the ON ERROR clause.
+	sc     *stmtctx.StatementContext
+	rows   [][]types.Datum
+	cursor int
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.sc = &stmtctx.StatementContext{TimeZone: e.ctx.GetSessionVars().Location()}
+	e.rows, e.cursor = e.rows[:0], 0
+	doc, isNull, err := e.expr.EvalJSON(e.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return err
+	}
+	row := make([]types.Datum, e.schema.Len())
+	e.rows, err = e.appendRows(e.rows, row, e.columns, doc.ExtractAll(e.path))
+	return err
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	for ; !req.IsFull() && e.cursor < len(e.rows); e.cursor++ {
+		for i := range e.rows[e.cursor] {
+			req.AppendDatum(i, &e.rows[e.cursor][i])
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	e.rows = nil
+	return e.baseExecutor.Close()
+}
+
+// appendRows appends a row for each of the values matched by the path of columns. A value which
+// matches the nested paths produces a row for each of the nested rows, the rows of sibling nested
+// paths are appended one after another, with the columns of the other nested paths set to NULL.
+func (e *JSONTableExec) appendRows(rows [][]types.Datum, row []types.Datum, columns []*plannercore.JSONTableColumn, values []json.BinaryJSON) ([][]types.Datum, error) {
+	var err error
+	for i, value := range values {
+		for _, col := range columns {
+			switch col.Tp {
+			case ast.JSONTableColumnOrdinality:
+				row[col.Offset].SetUint64(uint64(i + 1))
+			case ast.JSONTableColumnExistsPath:
+				_, found := value.Extract([]json.PathExpression{col.Path})
+				exists := types.NewIntDatum(0)
+				if found {
+					exists.SetInt64(1)
+				}
+				if row[col.Offset], err = exists.ConvertTo(e.sc, e.retFieldTypes[col.Offset]); err != nil {
+					return nil, ErrJTValueOutOfRange.GenWithStackByArgs(col.Name.O)
+				}
+			case ast.JSONTableColumnPath:
+				if row[col.Offset], err = e.evalPathColumn(col, value); err != nil {
+					return nil, err
+				}
+			}
+		}
+		numRows := len(rows)
+		for _, col := range columns {
+			if col.Tp != ast.JSONTableColumnNested {
+				continue
+			}
+			if rows, err = e.appendRows(rows, row, col.NestedColumns, value.ExtractAll(col.Path)); err != nil {
+				return nil, err
+			}
+			resetNestedColumns(row, col.NestedColumns)
+		}
+		if len(rows) == numRows {
+			rows = append(rows, append([]types.Datum(nil), row...))
+		}
+	}
+	return rows, nil
+}
+
+func resetNestedColumns(row []types.Datum, columns []*plannercore.JSONTableColumn) {
+	for _, col := range columns {
+		if col.Tp == ast.JSONTableColumnNested {
+			resetNestedColumns(row, col.NestedColumns)
+		} else {
+			row[col.Offset].SetNull()
+		}
+	}
+}
+
+func (e *JSONTableExec) evalPathColumn(col *plannercore.JSONTableColumn, value json.BinaryJSON) (types.Datum, error) {
+	matched, found := value.Extract([]json.PathExpression{col.Path})
+	if !found {
+		return e.onResponse(col, col.OnEmpty, ErrMissingJSONTableValue.GenWithStackByArgs(col.Name.O))
+	}
+	d, err := e.convertValue(col, matched)
+	if err != nil {
+		return e.onResponse(col, col.OnError, err)
+	}
+	return d, nil
+}
+
+func (e *JSONTableExec) onResponse(col *plannercore.JSONTableColumn, resp *plannercore.JSONTableOnResponse, err error) (types.Datum, error) {
+	switch resp.Tp {
+	case ast.JSONTableOnResponseError:
+		return types.Datum{}, err
+	case ast.JSONTableOnResponseDefault:
+		return e.convertValue(col, resp.Default)
+	}
+	return types.Datum{}, nil
+}
+
+// convertValue converts a JSON value to the type of col. A JSON string is unquoted unless the column is JSON,
+// an object or an array can only be stored into a JSON column.
+func (e *JSONTableExec) convertValue(col *plannercore.JSONTableColumn, value json.BinaryJSON) (types.Datum, error) {
+	ft := e.retFieldTypes[col.Offset]
+	var d types.Datum
+	switch {
+	case ft.Tp == mysql.TypeJSON:
+		d.SetMysqlJSON(value)
+	case value.TypeCode == json.TypeCodeObject || value.TypeCode == json.TypeCodeArray:
+		return types.Datum{}, ErrWrongJSONTableValue.GenWithStackByArgs(col.Name.O, e.asName.O)
+	case value.TypeCode == json.TypeCodeLiteral && value.Value[0] == json.LiteralNil:
+		return types.Datum{}, nil
+	case value.TypeCode == json.TypeCodeString:
+		d.SetString(string(value.GetString()), ft.Collate)
+	default:
+		d.SetMysqlJSON(value)
+	}
+	result, err := d.ConvertTo(e.sc, ft)
+	if err != nil {
+		return types.Datum{}, ErrJTValueOutOfRange.GenWithStackByArgs(col.Name.O)
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite1) TestJSONTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": "x"}, {"a": "2", "b": [1]}, {"c": 3}]', '$[*]' columns (
+		id for ordinality,
+		a int path '$.a',
+		b varchar(10) path '$.b' default '"y"' on empty null on error,
+		c json path '$.c',
+		d int exists path '$.c')) as jt`).Check(testkit.Rows(
+		"1 1 x <nil> 0",
+		"2 2 <nil> <nil> 0",
+		"3 <nil> y 3 1",
+	))
+	tk.MustQuery(`select jt.a + 1 from json_table('[1, 2, null, 4]', '$[*]' columns (a int path '$')) jt where jt.a > 1 order by jt.a desc`).Check(testkit.Rows("5", "3"))
+	tk.MustQuery(`select count(*) from json_table('{}', '$[*]' columns (a int path '$')) jt`).Check(testkit.Rows("0"))
+	tk.MustQuery(`select * from json_table(null, '$[*]' columns (a int path '$')) jt`).Check(testkit.Rows())
+
+	// nested paths
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": [10, 20], "c": [30]}, {"a": 2}]', '$[*]' columns (
+		a int path '$.a',
+		nested path '$.b[*]' columns (bid for ordinality, b int path '$'),
+		nested path '$.c[*]' columns (c int path '$'))) jt`).Check(testkit.Rows(
+		"1 1 10 <nil>",
+		"1 2 20 <nil>",
+		"1 <nil> <nil> 30",
+		"2 <nil> <nil> <nil>",
+	))
+
+	// errors
+	tk.MustGetErrCode(`select * from json_table('[{}]', '$[*]' columns (a int path '$.a' error on empty)) jt`, 3665)
+	tk.MustGetErrCode(`select * from json_table('[{"a": [1]}]', '$[*]' columns (a int path '$.a' error on error)) jt`, 3666)
+	tk.MustGetErrCode(`select * from json_table('[{"a": "x"}]', '$[*]' columns (a int path '$.a' error on error)) jt`, 3669)
+	tk.MustQuery(`select * from json_table('[{"a": "x"}]', '$[*]' columns (a int path '$.a' default '7' on error)) jt`).Check(testkit.Rows("7"))
+	tk.MustGetErrCode(`select * from json_table('[]', '$[' columns (a int path '$')) jt`, 3143)
+	tk.MustGetErrCode(`select * from json_table('[]', '$[*]' columns (a int path '$', a int path '$')) jt`, 1060)
+	tk.MustGetErrCode(`select * from json_table('[]', '$[*]' columns (a int path '$' default 'x' on empty)) jt`, 3140)
+	tk.MustGetErrCode(`select * from json_table('[', '$[*]' columns (a int path '$')) jt`, 3140)
+
+	// lateral references to the tables on the left side
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, j json)")
+	tk.MustExec(`insert into t values (1, '[1, 2]'), (2, '[]'), (3, null), (4, '[3]')`)
+	tk.MustQuery(`select t.id, jt.a from t, json_table(t.j, '$[*]' columns (a int path '$')) as jt order by t.id, jt.a`).Check(testkit.Rows(
+		"1 1", "1 2", "4 3",
+	))
+	tk.MustQuery(`select t.id, jt.a from t join json_table(t.j, '$[*]' columns (a int path '$')) as jt on jt.a > t.id order by t.id, jt.a`).Check(testkit.Rows(
+		"1 2",
+	))
+	tk.MustQuery(`select t.id, jt.a from t left join json_table(t.j, '$[*]' columns (a int path '$')) as jt on true order by t.id, jt.a`).Check(testkit.Rows(
+		"1 1", "1 2", "2 <nil>", "3 <nil>", "4 3",
+	))
+	tk.MustQuery(`select t.id, (select sum(a) from json_table(t.j, '$[*]' columns (a int path '$')) as jt) from t order by t.id`).Check(testkit.Rows(
+		"1 3", "2 <nil>", "3 <nil>", "4 3",
+	))
+	tk.MustQuery(`explain format = 'brief' select t.id, jt.a from t left join json_table(t.j, '$[*]' columns (a int path '$')) as jt on jt.a = t.id`).Check(testkit.Rows(
+		"Projection 10000.00 root  test.t.id, Column#4",
+		"└─Apply 10000.00 root  left outer join, equal:[eq(test.t.id, Column#4)]",
+		"  ├─TableReader(Build) 10000.00 root  data:TableFullScan",
+		"  │ └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+		"  └─Selection(Probe) 8.00 root  not(isnull(Column#4))",
+		"    └─JSONTable 10.00 root  expr:test.t.j, path:$[*]",
+	))
+	tk.MustQuery(`select t.id, jt.a from t left join json_table(t.j, '$[*]' columns (a int path '$')) as jt on jt.a = t.id order by t.id`).Check(testkit.Rows(
+		"1 1", "2 <nil>", "3 <nil>", "4 <nil>",
+	))
+	tk.MustGetErrCode(`select * from t right join json_table(t.j, '$[*]' columns (a int path '$')) as jt on true`, 3668)
+	tk.MustGetErrCode(`select * from json_table(t.j, '$[*]' columns (a int path '$')) as jt, t`, 1054)
+}
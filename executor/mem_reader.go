@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/tableutil"
 )
 
 type memIndexReader struct {
@@ -90,7 +91,7 @@ func (m *memIndexReader) getMemRows() ([][]types.Datum, error) {
 	}
 
 	mutableRow := chunk.MutRowFromTypes(m.retFieldTypes)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.table, m.kvRanges, func(key, value []byte) error {
 		data, err := m.decodeIndexKeyValue(key, value, tps)
 		if err != nil {
 			return err
@@ -199,7 +200,7 @@ func buildMemTableReader(us *UnionScanExec, tblReader *TableReaderExecutor) *mem
 // TODO: Try to make memXXXReader lazy, There is no need to decode many rows when parent operator only need 1 row.
 func (m *memTableReader) getMemRows() ([][]types.Datum, error) {
 	mutableRow := chunk.MutRowFromTypes(m.retFieldTypes)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.table, m.kvRanges, func(key, value []byte) error {
 		row, err := m.decodeRecordKeyValue(key, value)
 		if err != nil {
 			return err
@@ -317,13 +318,32 @@ func hasColVal(data [][]byte, colIDs map[int64]int, id int64) bool {
 
 type processKVFunc func(key, value []byte) error
 
-func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn processKVFunc) error {
+func iterTxnMemBuffer(ctx sessionctx.Context, tblInfo *model.TableInfo, kvRanges []kv.KeyRange, fn processKVFunc) error {
 	txn, err := ctx.Txn(true)
 	if err != nil {
 		return err
 	}
+	// The committed rows of some temporary tables are kept in the session instead of TiKV,
+	// so they should be read together with the txn mem buffer.
+	var sessionData kv.MemBuffer
+	var diskData *tableutil.TempTableDiskData
+	if tableutil.KeepsSessionData(tblInfo.TempTableType) {
+		sessionData = ctx.GetSessionVars().TemporaryTableData
+		diskData = ctx.GetSessionVars().TemporaryTableDiskData[tblInfo.ID]
+	}
 	for _, rg := range kvRanges {
-		iter := txn.GetMemBuffer().SnapshotIter(rg.StartKey, rg.EndKey)
+		var iter kv.Iterator = txn.GetMemBuffer().SnapshotIter(rg.StartKey, rg.EndKey)
+		if sessionData != nil {
+			committed, err := iterSessionData(sessionData, diskData, rg)
+			if err != nil {
+				iter.Close()
+				return err
+			}
+			iter, err = tableutil.NewUnionIter(iter, committed)
+			if err != nil {
+				return err
+			}
+		}
 		for ; iter.Valid(); err = iter.Next() {
 			if err != nil {
 				return err
@@ -341,6 +361,25 @@ func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn process
 	return nil
 }
 
+// iterSessionData iterates the data committed in the session.
+// The data spilled to disk is overwritten by the data in the session memory.
+func iterSessionData(sessionData kv.MemBuffer, diskData *tableutil.TempTableDiskData, rg kv.KeyRange) (kv.Iterator, error) {
+	committed, err := sessionData.Iter(rg.StartKey, rg.EndKey)
+	if err != nil || diskData == nil {
+		return committed, err
+	}
+	spilled, err := diskData.Iter(rg.StartKey, rg.EndKey)
+	if err != nil {
+		committed.Close()
+		return nil, err
+	}
+	iter, err := tableutil.NewUnionIter(committed, spilled)
+	if err != nil {
+		return nil, err
+	}
+	return iter, nil
+}
+
 func reverseDatumSlice(rows [][]types.Datum) {
 	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
 		rows[i], rows[j] = rows[j], rows[i]
@@ -349,7 +388,7 @@ func reverseDatumSlice(rows [][]types.Datum) {
 
 func (m *memIndexReader) getMemRowsHandle() ([]kv.Handle, error) {
 	handles := make([]kv.Handle, 0, m.addedRowsLen)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.table, m.kvRanges, func(key, value []byte) error {
 		handle, err := tablecodec.DecodeIndexHandle(key, value, len(m.index.Columns))
 		if err != nil {
 			return err
@@ -15,21 +15,29 @@ package executor
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
 )
 
 type dataInfo struct {
 	chk         *chunk.Chunk
+	numRows     uint64
 	remaining   uint64
 	accumulated uint64
+	// memUsage is the memory usage of the child chunk which chk refers to.
+	memUsage int64
 }
 
 // PipelinedWindowExec is the executor for window functions.
@@ -73,10 +81,32 @@ type PipelinedWindowExec struct {
 	isRangeFrame             bool
 	emptyFrame               bool
 	initializedSlidingWindow bool
+
+	memTracker  *memory.Tracker
+	spillAction *pipelinedWindowSpillAction
+	// fallback is the spillable WindowExec which takes over the remaining rows once spillTriggered is set.
+	// It is nil if the executor is not allowed to fall back.
+	fallback       *WindowExec
+	spillTriggered uint32
+	// spilled indicates the remaining rows are handed over to fallback.
+	spilled bool
+	// skipRows is the number of leading results of fallback which have been produced by the executor.
+	skipRows    uint64
+	fallbackChk *chunk.Chunk
 }
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
+	e.data, e.rows, e.childResult, e.fallbackChk = nil, nil, nil, nil
+	if e.spilled {
+		e.spilled = false
+		if err := e.fallback.Close(); err != nil {
+			return err
+		}
+	}
+	if e.memTracker != nil {
+		e.memTracker.ReplaceBytesUsed(0)
+	}
 	return errors.Trace(e.baseExecutor.Close())
 }
 
@@ -95,6 +125,11 @@ func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
 		}
 	}
 	e.rows = make([]chunk.Row, 0)
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	// The spill action is kept across reopens, only its state is reset.
+	atomic.StoreUint32(&e.spillTriggered, 0)
+	e.spilled, e.skipRows = false, 0
 	return e.baseExecutor.Open(ctx)
 }
 
@@ -106,8 +141,11 @@ func (e *PipelinedWindowExec) firstResultChunkNotReady() bool {
 // Next implements the Executor Next interface.
 func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err error) {
 	chk.Reset()
+	if e.spilled && len(e.data) == 0 {
+		return e.nextFromFallback(ctx, chk)
+	}
 
-	for !e.done || e.firstResultChunkNotReady() {
+	for !e.spilled && (!e.done || e.firstResultChunkNotReady()) {
 		// we firstly gathering enough rows and consume them, until we are able to produce.
 		// for unbounded frame, it needs consume the whole partition before being able to produce, in this case
 		// e.p.enoughToProduce will be false until so.
@@ -122,6 +160,9 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 				if err != nil {
 					return err
 				}
+				if e.spilled {
+					break
+				}
 			}
 			if e.done || e.newPartition {
 				e.finish()
@@ -158,8 +199,11 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 	}
 	if len(e.data) > 0 {
 		chk.SwapColumns(e.data[0].chk)
+		e.memTracker.Consume(-e.data[0].memUsage)
 		e.data = e.data[1:]
 		e.dataIdx--
+	} else if e.spilled {
+		return e.nextFromFallback(ctx, chk)
 	}
 	return nil
 }
@@ -172,6 +216,10 @@ func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error
 	}
 
 	if e.groupChecker.isExhausted() {
+		// All rows of the child chunks have been consumed, it's the safe point to fall back.
+		if atomic.LoadUint32(&e.spillTriggered) == 1 {
+			return e.spillToFallback(ctx)
+		}
 		var drained, samePartition bool
 		drained, err = e.fetchChild(ctx)
 		if err != nil {
@@ -219,7 +267,16 @@ func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (EOF bool, err err
 		return false, err
 	}
 	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
+	memUsage := childResult.MemoryUsage()
+	e.memTracker.Consume(memUsage)
+	e.data = append(e.data, dataInfo{chk: resultChk, numRows: uint64(numRows), remaining: uint64(numRows), accumulated: e.accumulated, memUsage: memUsage})
+	// The spill action is registered lazily, so the executor won't fall back
+	// before holding any rows when the child executor exceeds the memory quota.
+	// It is registered only once, since the executor may be reopened many times, e.g. as the inner side of Apply.
+	if e.spillAction == nil && e.fallback != nil && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.spillAction = &pipelinedWindowSpillAction{e: e}
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+	}
 
 	e.childResult = childResult
 	return false, nil
@@ -455,3 +512,131 @@ func (e *PipelinedWindowExec) reset() {
 		windowFunc.ResetPartialResult(e.partialResults[i])
 	}
 }
+
+// spillToFallback hands over the rows which are not produced yet to the spillable WindowExec. The results
+// which have been produced are still returned by the executor, and the fallback returns the others.
+func (e *PipelinedWindowExec) spillToFallback(ctx context.Context) error {
+	// The chunk being produced is truncated to the produced rows, and the chunks after it contain no produced rows.
+	var truncated *chunk.Chunk
+	for i, info := range e.data[e.dataIdx:] {
+		if produced := int(info.numRows - info.remaining); i == 0 && produced > 0 {
+			truncated = chunk.New(e.retFieldTypes, produced, produced)
+			truncated.Append(info.chk, 0, produced)
+			e.memTracker.Consume(truncated.MemoryUsage())
+		}
+		e.memTracker.Consume(-info.memUsage)
+	}
+	data := e.data[:e.dataIdx]
+	if truncated != nil {
+		data = append(data, dataInfo{chk: truncated, numRows: uint64(truncated.NumRows()), memUsage: truncated.MemoryUsage()})
+	}
+
+	// The rows before rowStart are out of the frames of the rows which are not produced yet, so
+	// the fallback only needs to start the partition from rowStart and skip the produced rows.
+	// The rows are copied because the returned chunks refer to the same columns.
+	rows := chunk.New(retTypes(e.children[0]), len(e.rows), len(e.rows))
+	for _, row := range e.rows {
+		rows.AppendRow(row)
+	}
+	e.memTracker.Consume(rows.MemoryUsage())
+	logutil.Logger(ctx).Info("memory exceeds quota, pipelined window executor falls back to the spillable one",
+		zap.Int("bufferedRows", len(e.rows)),
+		zap.Uint64("producedRows", e.curRowIdx-e.rowStart))
+
+	e.skipRows = e.curRowIdx - e.rowStart
+	e.data, e.dataIdx = data, len(data)
+	e.rows, e.childResult = nil, nil
+	e.spilled = true
+	e.fallback.children = []Executor{&windowReplayExec{
+		baseExecutor: newBaseExecutor(e.ctx, e.children[0].Schema(), 0, e.children[0]),
+		rows:         rows,
+		memTracker:   e.memTracker,
+	}}
+	e.fallbackChk = newFirstChunk(e)
+	return e.fallback.Open(ctx)
+}
+
+func (e *PipelinedWindowExec) nextFromFallback(ctx context.Context, chk *chunk.Chunk) error {
+	for e.skipRows > 0 {
+		if err := Next(ctx, e.fallback, e.fallbackChk); err != nil {
+			return err
+		}
+		numRows := uint64(e.fallbackChk.NumRows())
+		if numRows == 0 {
+			return nil
+		}
+		if numRows > e.skipRows {
+			chk.Append(e.fallbackChk, int(e.skipRows), int(numRows))
+			e.skipRows = 0
+			return nil
+		}
+		e.skipRows -= numRows
+	}
+	return Next(ctx, e.fallback, chk)
+}
+
+// pipelinedWindowSpillAction implements memory.ActionOnExceed for PipelinedWindowExec. If the memory quota of a
+// query is exceeded, pipelinedWindowSpillAction.Action is triggered to make PipelinedWindowExec fall back to
+// WindowExec, which is able to spill the rows to disk.
+type pipelinedWindowSpillAction struct {
+	memory.BaseOOMAction
+	e *PipelinedWindowExec
+}
+
+// Action sets PipelinedWindowExec to fall back, and calls its fallbackAction if it is already set.
+func (a *pipelinedWindowSpillAction) Action(t *memory.Tracker) {
+	if atomic.CompareAndSwapUint32(&a.e.spillTriggered, 0, 1) {
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (a *pipelinedWindowSpillAction) SetLogHook(hook func(uint64)) {}
+
+// GetPriority get the priority of the Action.
+func (a *pipelinedWindowSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// windowReplayExec returns the rows buffered by PipelinedWindowExec before the rows of its child, it is the
+// child of the WindowExec which PipelinedWindowExec falls back to. The child is opened and closed by
+// PipelinedWindowExec.
+type windowReplayExec struct {
+	baseExecutor
+
+	rows       *chunk.Chunk
+	cursor     int
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *windowReplayExec) Open(ctx context.Context) error {
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *windowReplayExec) Close() error {
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *windowReplayExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.rows == nil {
+		return Next(ctx, e.children[0], req)
+	}
+	req.Reset()
+	end := mathutil.Min(e.cursor+req.RequiredRows(), e.rows.NumRows())
+	req.Append(e.rows, e.cursor, end)
+	e.cursor = end
+	if e.cursor == e.rows.NumRows() {
+		e.memTracker.Consume(-e.rows.MemoryUsage())
+		e.rows = nil
+	}
+	if req.NumRows() == 0 {
+		return Next(ctx, e.children[0], req)
+	}
+	return nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/tikv/client-go/v2/tikv"
 )
 
@@ -402,8 +403,9 @@ func (e *PointGetExecutor) get(ctx context.Context, key kv.Key) ([]byte, error)
 		return nil, nil
 	}
 
-	// Local temporary table always get snapshot value from session
-	if e.tblInfo.TempTableType == model.TempTableLocal {
+	// Local temporary table and global temporary table with "on commit preserve rows"
+	// always get snapshot value from session.
+	if tableutil.KeepsSessionData(e.tblInfo.TempTableType) {
 		return e.ctx.GetSessionVars().GetTemporaryTableSnapshotValue(ctx, key)
 	}
 
@@ -83,7 +83,7 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 		return true, nil
 	}
 
-	err = r.t.RemoveRecord(e.ctx, handle, oldRow)
+	_, err = removeRecordWithFK(ctx, e.ctx, r.t, handle, oldRow)
 	if err != nil {
 		return false, err
 	}
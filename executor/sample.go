@@ -15,20 +15,28 @@ package executor
 
 import (
 	"context"
+	"math/rand"
 	"sort"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tipb/go-tipb"
 	"github.com/tikv/client-go/v2/tikv"
 )
 
@@ -69,12 +77,13 @@ func (e *TableSampleExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 
 // Close implements the Executor Close interface.
 func (e *TableSampleExecutor) Close() error {
-	return nil
+	return e.sampler.close()
 }
 
 type rowSampler interface {
 	writeChunk(req *chunk.Chunk) error
 	finished() bool
+	close() error
 }
 
 type tableRegionSampler struct {
@@ -153,31 +162,43 @@ func (s *tableRegionSampler) pickRanges(count int) ([]kv.KeyRange, error) {
 }
 
 func (s *tableRegionSampler) writeChunkFromRanges(ranges []kv.KeyRange, req *chunk.Chunk) error {
-	decLoc, sysLoc := s.ctx.GetSessionVars().Location(), time.UTC
-	cols, decColMap, err := s.buildSampleColAndDecodeColMap()
+	rowDecoder, decColMap, err := s.buildRowDecoder()
 	if err != nil {
 		return err
 	}
-	rowDecoder := decoder.NewRowDecoder(s.table, cols, decColMap)
 	err = s.scanFirstKVForEachRange(ranges, func(handle kv.Handle, value []byte) error {
-		_, err := rowDecoder.DecodeAndEvalRowWithMap(s.ctx, handle, value, decLoc, sysLoc, s.rowMap)
-		if err != nil {
-			return err
-		}
-		currentRow := rowDecoder.CurrentRowWithDefaultVal()
-		mutRow := chunk.MutRowFromTypes(s.retTypes)
-		for i, col := range s.schema.Columns {
-			offset := decColMap[col.ID].Col.Offset
-			target := currentRow.GetDatum(offset, s.retTypes[i])
-			mutRow.SetDatum(i, target)
-		}
-		req.AppendRow(mutRow.ToRow())
-		s.resetRowMap()
-		return nil
+		return s.appendRow(rowDecoder, decColMap, handle, value, req)
 	})
 	return err
 }
 
+func (s *tableRegionSampler) buildRowDecoder() (*decoder.RowDecoder, map[int64]decoder.Column, error) {
+	cols, decColMap, err := s.buildSampleColAndDecodeColMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	return decoder.NewRowDecoder(s.table, cols, decColMap), decColMap, nil
+}
+
+func (s *tableRegionSampler) appendRow(rowDecoder *decoder.RowDecoder, decColMap map[int64]decoder.Column,
+	handle kv.Handle, value []byte, req *chunk.Chunk) error {
+	decLoc, sysLoc := s.ctx.GetSessionVars().Location(), time.UTC
+	_, err := rowDecoder.DecodeAndEvalRowWithMap(s.ctx, handle, value, decLoc, sysLoc, s.rowMap)
+	if err != nil {
+		return err
+	}
+	currentRow := rowDecoder.CurrentRowWithDefaultVal()
+	mutRow := chunk.MutRowFromTypes(s.retTypes)
+	for i, col := range s.schema.Columns {
+		offset := decColMap[col.ID].Col.Offset
+		target := currentRow.GetDatum(offset, s.retTypes[i])
+		mutRow.SetDatum(i, target)
+	}
+	req.AppendRow(mutRow.ToRow())
+	s.resetRowMap()
+	return nil
+}
+
 func (s *tableRegionSampler) splitTableRanges() ([]kv.KeyRange, error) {
 	if len(s.partTables) != 0 {
 		var ranges []kv.KeyRange
@@ -323,6 +344,197 @@ func (s *tableRegionSampler) finished() bool {
 	return s.isFinished
 }
 
+func (s *tableRegionSampler) close() error {
+	return nil
+}
+
+// tablePercentSampler implements the SYSTEM and BERNOULLI sampling methods.
+// SYSTEM picks each region with the given probability and returns all the rows in it,
+// while BERNOULLI scans all the regions and picks each row with the given probability.
+// The regions are read one by one through coprocessor requests.
+type tablePercentSampler struct {
+	*tableRegionSampler
+
+	method  ast.SampleMethodType
+	percent float64
+	rng     *rand.Rand
+
+	// colInfos and fullTypes describe the row layout of fullSchema, which is
+	// the layout returned by the table scan.
+	colInfos        []*model.ColumnInfo
+	fullTypes       []*types.FieldType
+	evalSchema      *expression.Schema
+	virtualColIdx   []int
+	virtualRetTypes []*types.FieldType
+	// outputOffsets maps the columns of schema to their offsets in fullSchema.
+	outputOffsets []int
+
+	result distsql.SelectResult
+	chk    *chunk.Chunk
+	rowIdx int
+}
+
+func newTablePercentSampler(regionSampler *tableRegionSampler, method ast.SampleMethodType,
+	percent float64, seed int64) *tablePercentSampler {
+	return &tablePercentSampler{
+		tableRegionSampler: regionSampler,
+		method:             method,
+		percent:            percent,
+		rng:                rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *tablePercentSampler) init() error {
+	if s.chk != nil {
+		return nil
+	}
+	err := s.initRanges()
+	if err != nil {
+		return err
+	}
+	tblInfo := s.table.Meta()
+	fullCols := s.fullSchema.Columns
+	s.colInfos = make([]*model.ColumnInfo, 0, len(fullCols))
+	s.fullTypes = make([]*types.FieldType, 0, len(fullCols))
+	for _, col := range fullCols {
+		if col.ID == model.ExtraHandleID {
+			s.colInfos = append(s.colInfos, model.NewExtraHandleColInfo())
+		} else {
+			s.colInfos = append(s.colInfos, plannercore.FindColumnInfoByID(tblInfo.Columns, col.ID))
+		}
+		s.fullTypes = append(s.fullTypes, col.RetType)
+	}
+	// The virtual generated columns are not stored, so they are evaluated in TiDB
+	// against the full row returned by the table scan.
+	s.evalSchema = s.fullSchema.Clone()
+	s.outputOffsets = make([]int, 0, s.schema.Len())
+	for _, col := range s.schema.Columns {
+		offset := s.fullSchema.ColumnIndex(col)
+		if offset < 0 {
+			return errors.Errorf("column %s not found in the sampled table", col.OrigName)
+		}
+		if col.VirtualExpr != nil {
+			s.evalSchema.Columns[offset].VirtualExpr, err = col.VirtualExpr.ResolveIndices(s.fullSchema)
+			if err != nil {
+				return err
+			}
+		}
+		s.outputOffsets = append(s.outputOffsets, offset)
+	}
+	s.virtualColIdx = buildVirtualColumnIndex(s.evalSchema, s.colInfos)
+	for _, idx := range s.virtualColIdx {
+		s.virtualRetTypes = append(s.virtualRetTypes, s.fullTypes[idx])
+	}
+	s.chk = chunk.NewChunkWithCapacity(s.fullTypes, s.ctx.GetSessionVars().MaxChunkSize)
+	return nil
+}
+
+func (s *tablePercentSampler) close() error {
+	if s.result != nil {
+		err := s.result.Close()
+		s.result = nil
+		return err
+	}
+	return nil
+}
+
+func (s *tablePercentSampler) pick() bool {
+	return s.rng.Float64()*100 < s.percent
+}
+
+func (s *tablePercentSampler) writeChunk(req *chunk.Chunk) error {
+	err := s.init()
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	for !req.IsFull() {
+		if s.result == nil {
+			if len(s.restKVRanges) == 0 {
+				s.isFinished = true
+				return nil
+			}
+			var r kv.KeyRange
+			r, s.restKVRanges = s.restKVRanges[0], s.restKVRanges[1:]
+			if s.method == ast.SampleMethodTypeSystem && !s.pick() {
+				continue
+			}
+			s.result, err = s.buildRegionScan(ctx, r)
+			if err != nil {
+				return err
+			}
+			s.chk.Reset()
+			s.rowIdx = 0
+		}
+		if s.rowIdx >= s.chk.NumRows() {
+			err = s.result.Next(ctx, s.chk)
+			if err != nil {
+				return err
+			}
+			if s.chk.NumRows() == 0 {
+				if err = s.close(); err != nil {
+					return err
+				}
+				continue
+			}
+			err = FillVirtualColumnValue(s.virtualRetTypes, s.virtualColIdx, s.evalSchema, s.colInfos, s.ctx, s.chk)
+			if err != nil {
+				return err
+			}
+			s.rowIdx = 0
+		}
+		row := s.chk.GetRow(s.rowIdx)
+		s.rowIdx++
+		if s.method == ast.SampleMethodTypeBernoulli && !s.pick() {
+			continue
+		}
+		req.AppendRowByColIdxs(row, s.outputOffsets)
+	}
+	return nil
+}
+
+// buildRegionScan sends a coprocessor table scan request for the key range of one region.
+func (s *tablePercentSampler) buildRegionScan(ctx context.Context, r kv.KeyRange) (distsql.SelectResult, error) {
+	sessVars := s.ctx.GetSessionVars()
+	dagReq := &tipb.DAGRequest{}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(sessVars.Location())
+	dagReq.Flags = sessVars.StmtCtx.PushDownFlags()
+	for i := range s.colInfos {
+		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(i))
+	}
+	tblScan := tables.BuildTableScanFromInfos(s.table.Meta(), s.colInfos)
+	// The range may belong to a partition, so the physical table ID is taken from the key.
+	tblScan.TableId = tablecodec.DecodeTableID(r.StartKey)
+	// A range covering the whole record prefix is taken as a point range by the coprocessor.
+	// No row key equals the prefix, so starting right after it skips nothing.
+	if r.StartKey.Cmp(tablecodec.GenTableRecordPrefix(tblScan.TableId)) == 0 {
+		r.StartKey = r.StartKey.Next()
+	}
+	tblScan.Desc = s.isDesc
+	err := plannercore.SetPBColumnsDefaultValue(s.ctx, tblScan.Columns, s.colInfos)
+	if err != nil {
+		return nil, err
+	}
+	dagReq.Executors = append(dagReq.Executors, &tipb.Executor{Tp: tipb.ExecType_TypeTableScan, TblScan: tblScan})
+	distsql.SetEncodeType(s.ctx, dagReq)
+
+	var builder distsql.RequestBuilder
+	builder.KeyRanges = []kv.KeyRange{r}
+	kvReq, err := builder.
+		SetDAGRequest(dagReq).
+		SetStartTS(s.startTS).
+		SetDesc(s.isDesc).
+		SetKeepOrder(true).
+		SetFromSessionVars(sessVars).
+		SetFromInfoSchema(s.ctx.GetInfoSchema()).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	kvReq.Concurrency = 1
+	return distsql.Select(ctx, s.ctx, kvReq, s.fullTypes, statistics.NewQueryFeedback(0, nil, 0, false))
+}
+
 type sampleKV struct {
 	handle kv.Handle
 	value  []byte
@@ -185,7 +185,8 @@ func (s *testTableSampleSuite) TestTableSampleInvalid(c *C) {
 	tk.MustGetErrCode("select * from information_schema.tables tablesample regions();", errno.ErrInvalidTableSample)
 
 	tk.MustGetErrCode("select a from t tablesample system();", errno.ErrInvalidTableSample)
-	tk.MustGetErrCode("select a from t tablesample bernoulli(10 percent);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli(10 rows);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli(200 percent);", errno.ErrInvalidTableSample)
 	tk.MustGetErrCode("select a from t as t1 tablesample regions(), t as t2 tablesample system();", errno.ErrInvalidTableSample)
 	tk.MustGetErrCode("select a from t tablesample ();", errno.ErrInvalidTableSample)
 }
@@ -287,3 +288,43 @@ func (s *testTableSampleSuite) TestMaxChunkSize(c *C) {
 	rows := tk.MustQuery("select * from t tablesample regions();").Rows()
 	c.Assert(len(rows), Equals, 4)
 }
+
+func (s *testTableSampleSuite) TestTableSampleSystemAndBernoulli(c *C) {
+	tk := s.initSampleTest(c)
+	tk.MustExec("create table t (a int primary key, b int);")
+	tk.MustQuery("split table t between (0) and (40000) regions 4;").Check(testkit.Rows("3 1"))
+	for i := 0; i < 100; i++ {
+		tk.MustExec("insert into t values (?, ?);", i*400, i)
+	}
+	for _, method := range []string{"system", "bernoulli"} {
+		tk.MustQuery(fmt.Sprintf("select count(*) from t tablesample %s(100 percent);", method)).Check(testkit.Rows("100"))
+		tk.MustQuery(fmt.Sprintf("select count(*) from t tablesample %s(0);", method)).Check(testkit.Rows("0"))
+		tk.MustQuery(fmt.Sprintf("select a from t tablesample %s(100) order by a desc limit 3;", method)).Check(testkit.Rows("39600", "39200", "38800"))
+		c.Assert(tk.HasPlan(fmt.Sprintf("select * from t tablesample %s(10);", method), "TableSample"), IsTrue)
+
+		// The same seed returns the same rows.
+		sql := fmt.Sprintf("select a, b from t tablesample %s(50 percent) repeatable(7);", method)
+		rows := tk.MustQuery(sql).Rows()
+		tk.MustQuery(sql).Check(rows)
+		c.Assert(len(rows), Less, 100)
+	}
+
+	// SYSTEM returns all the rows in the picked regions.
+	rows := tk.MustQuery("select a from t tablesample system(50) repeatable(1);").Rows()
+	c.Assert(len(rows)%25, Equals, 0)
+
+	tk.Se.GetSessionVars().MaxChunkSize = 1
+	tk.MustQuery("select count(*) from t tablesample bernoulli(100);").Check(testkit.Rows("100"))
+	tk.Se.GetSessionVars().MaxChunkSize = variable.DefMaxChunkSize
+
+	// The virtual generated columns are evaluated in TiDB.
+	tk.MustExec("drop table t;")
+	tk.MustExec("create table t (a int, b int as (a + 1), c int as (b + 1)) partition by hash(a) partitions 2;")
+	tk.MustExec("insert into t(a) values (1), (2), (3);")
+	for _, method := range []string{"system", "bernoulli"} {
+		tk.MustQuery(fmt.Sprintf("select c, a from t tablesample %s(100) order by a;", method)).Check(
+			testkit.Rows("3 1", "4 2", "5 3"))
+		tk.MustQuery(fmt.Sprintf("select _tidb_rowid, b from t tablesample %s(100) order by b;", method)).Check(
+			testkit.Rows("1 2", "2 3", "3 4"))
+	}
+}
@@ -777,7 +777,7 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	sqlMode := ctx.GetSessionVars().SQLMode
 	tableName := stringutil.Escape(tableInfo.Name.O, sqlMode)
 	switch tableInfo.TempTableType {
-	case model.TempTableGlobal:
+	case model.TempTableGlobal, model.TempTableGlobalPreserveRows:
 		fmt.Fprintf(buf, "CREATE GLOBAL TEMPORARY TABLE %s (\n", tableName)
 	default:
 		fmt.Fprintf(buf, "CREATE TABLE %s (\n", tableName)
@@ -959,6 +959,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constraint := range tableInfo.Constraints {
+		if constraint.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constraint.Name.O, sqlMode), constraint.ExprString))
+		if !constraint.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	switch tableInfo.TempTableType {
@@ -1026,8 +1036,11 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		fmt.Fprintf(buf, " COMMENT='%s'", format.OutputFormat(tableInfo.Comment))
 	}
 
-	if tableInfo.TempTableType == model.TempTableGlobal {
+	switch tableInfo.TempTableType {
+	case model.TempTableGlobal:
 		fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
+	case model.TempTableGlobalPreserveRows:
+		fmt.Fprintf(buf, " ON COMMIT PRESERVE ROWS")
 	}
 
 	// add partition info here.
@@ -1170,6 +1183,15 @@ func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer)
 		fmt.Fprintf(buf, "\nPARTITIONS %d", partitionInfo.Num)
 		return
 	}
+	if partitionInfo.Type == model.PartitionTypeKey {
+		colsName := make([]string, 0, len(partitionInfo.Columns))
+		for _, col := range partitionInfo.Columns {
+			colsName = append(colsName, col.L)
+		}
+		fmt.Fprintf(buf, "\nPARTITION BY KEY( %s )", strings.Join(colsName, ","))
+		fmt.Fprintf(buf, "\nPARTITIONS %d", partitionInfo.Num)
+		return
+	}
 	// this if statement takes care of range columns case
 	if partitionInfo.Columns != nil && partitionInfo.Type == model.PartitionTypeRange {
 		buf.WriteString("\nPARTITION BY RANGE COLUMNS(")
@@ -1334,7 +1356,7 @@ func (e *ShowExec) fetchShowCreateUser() error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT plugin, password_expired, password_lifetime FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1353,6 +1375,16 @@ func (e *ShowExec) fetchShowCreateUser() error {
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
+	passwordExpire := "PASSWORD EXPIRE DEFAULT"
+	if rows[0].GetEnum(1).String() == "Y" {
+		passwordExpire = "PASSWORD EXPIRE"
+	} else if !rows[0].IsNull(2) {
+		if lifetime := rows[0].GetUint64(2); lifetime == 0 {
+			passwordExpire = "PASSWORD EXPIRE NEVER"
+		} else {
+			passwordExpire = fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", lifetime)
+		}
+	}
 
 	stmt, err = exec.ParseWithParams(context.TODO(), `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1374,8 +1406,8 @@ func (e *ShowExec) fetchShowCreateUser() error {
 		require = privValue.RequireStr()
 	}
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s %s ACCOUNT UNLOCK",
+		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require, passwordExpire)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
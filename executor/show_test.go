@@ -452,6 +452,16 @@ func (s *testSuite5) TestShowCreateUser(c *C) {
 	rows = tk.MustQuery("SHOW CREATE USER 'sha_test'@'%'")
 	c.Assert(rows.Rows()[0][0].(string)[:78], check.Equals, "CREATE USER 'sha_test'@'%' IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$")
 
+	// The password expiration options of the user.
+	tk.MustExec("CREATE USER 'expire_test'@'%' PASSWORD EXPIRE INTERVAL 10 DAY")
+	tk.MustQuery("SHOW CREATE USER 'expire_test'@'%'").
+		Check(testkit.Rows("CREATE USER 'expire_test'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE INTERVAL 10 DAY ACCOUNT UNLOCK"))
+	tk.MustExec("ALTER USER 'expire_test'@'%' PASSWORD EXPIRE NEVER")
+	tk.MustQuery("SHOW CREATE USER 'expire_test'@'%'").
+		Check(testkit.Rows("CREATE USER 'expire_test'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE NEVER ACCOUNT UNLOCK"))
+	tk.MustExec("ALTER USER 'expire_test'@'%' PASSWORD EXPIRE")
+	tk.MustQuery("SHOW CREATE USER 'expire_test'@'%'").
+		Check(testkit.Rows("CREATE USER 'expire_test'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE ACCOUNT UNLOCK"))
 }
 
 func (s *testSuite5) TestUnprivilegedShow(c *C) {
@@ -834,7 +844,7 @@ func (s *testSuite5) TestShowCreateTable(c *C) {
 			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
 	))
 
-	// TiDB defaults (and only supports) foreign_key_checks=0
+	// TiDB defaults to foreign_key_checks=0
 	// This means that the child table can be created before the parent table.
 	// This behavior is required for mysqldump restores.
 	tk.MustExec(`DROP TABLE IF EXISTS parent, child`)
@@ -1130,7 +1140,7 @@ func (s *testSuite5) TestShowBuiltin(c *C) {
 	res := tk.MustQuery("show builtins;")
 	c.Assert(res, NotNil)
 	rows := res.Rows()
-	const builtinFuncNum = 271
+	const builtinFuncNum = 276
 	c.Assert(builtinFuncNum, Equals, len(rows))
 	c.Assert("abs", Equals, rows[0][0].(string))
 	c.Assert("yearweek", Equals, rows[builtinFuncNum-1][0].(string))
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -39,6 +40,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
@@ -557,6 +559,7 @@ func (e *SimpleExec) executeUse(s *ast.UseStmt) error {
 	e.ctx.GetSessionVars().CurrentDBChanged = dbname.O != e.ctx.GetSessionVars().CurrentDB
 	e.ctx.GetSessionVars().CurrentDB = dbname.O
 	sessionVars := e.ctx.GetSessionVars()
+	sessionVars.StateTracker.TrackSchema()
 	dbCollate := dbinfo.Collate
 	if dbCollate == "" {
 		dbCollate = getDefaultCollate(dbinfo.Charset)
@@ -748,14 +751,21 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		return err
 	}
 
+	passwordExpired, passwordLifetime, _ := passwordExpireOptions(s.PasswordOrLockOptions)
+	expired := "N"
+	if passwordExpired {
+		expired = "Y"
+	}
+
 	sql := new(strings.Builder)
 	if s.IsCreateRole {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, password_expired, password_lifetime, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, password_expired, password_lifetime) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
+	passwords := make([]string, 0, len(s.Specs))
 	for _, spec := range s.Specs {
 		if len(users) > 0 {
 			sqlexec.MustFormatSQL(sql, ",")
@@ -776,7 +786,7 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
+		pwd, ok := encodedPassword(spec)
 
 		if !ok {
 			return errors.Trace(ErrPasswordFormat)
@@ -786,11 +796,12 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			authPlugin = spec.AuthOpt.AuthPlugin
 		}
 		if s.IsCreateRole {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, "Y")
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, expired, passwordLifetime, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, expired, passwordLifetime)
 		}
 		users = append(users, spec.User)
+		passwords = append(passwords, pwd)
 	}
 	if len(users) == 0 {
 		return nil
@@ -833,6 +844,18 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
+	for i, user := range users {
+		if passwords[i] == "" {
+			continue
+		}
+		h, err := e.loadPasswordHistory(user.Username, user.Hostname)
+		if err != nil {
+			return err
+		}
+		if err = e.recordPasswordHistory(user.Username, user.Hostname, passwords[i], h); err != nil {
+			return err
+		}
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
@@ -868,6 +891,8 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 	hasSystemUserPriv := checker.RequestDynamicVerification(activeRoles, "SYSTEM_USER", false)
 	hasRestrictedUserPriv := checker.RequestDynamicVerification(activeRoles, "RESTRICTED_USER_ADMIN", false)
 	hasSystemSchemaPriv := checker.RequestVerification(activeRoles, mysql.SystemDB, mysql.UserTable, "", mysql.UpdatePriv)
+	passwordExpired, passwordLifetime, lifetimeSet := passwordExpireOptions(s.PasswordOrLockOptions)
+	accountUnlock := hasAccountUnlock(s.PasswordOrLockOptions)
 
 	for _, spec := range s.Specs {
 		user := e.ctx.GetSessionVars().User
@@ -922,17 +947,63 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 		}
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 		if spec.AuthOpt != nil {
-			pwd, ok := spec.EncodedPassword()
+			pwd, ok := encodedPassword(spec)
 			if !ok {
 				return errors.Trace(ErrPasswordFormat)
 			}
-			stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
+			h, err := e.loadPasswordHistory(spec.User.Username, spec.User.Hostname)
+			if err != nil {
+				return err
+			}
+			if spec.AuthOpt.ByAuthString {
+				err = h.check(spec.User.Username, spec.User.Hostname, spec.AuthOpt.AuthString, true)
+			} else {
+				err = h.check(spec.User.Username, spec.User.Hostname, pwd, false)
+			}
+			if err != nil {
+				return err
+			}
+			stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%?, password_expired='N', password_last_changed=CURRENT_TIMESTAMP() WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
 			if err != nil {
 				return err
 			}
 			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
 			if err != nil {
 				failedUsers = append(failedUsers, spec.User.String())
+			} else {
+				if err = e.recordPasswordHistory(spec.User.Username, spec.User.Hostname, pwd, h); err != nil {
+					return err
+				}
+				e.passwordChanged(spec.User.Username, spec.User.Hostname)
+			}
+		}
+
+		if passwordExpired || lifetimeSet {
+			sql := new(strings.Builder)
+			sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET `, mysql.SystemDB, mysql.UserTable)
+			if passwordExpired {
+				sqlexec.MustFormatSQL(sql, `password_expired='Y'`)
+			}
+			if lifetimeSet {
+				if passwordExpired {
+					sqlexec.MustFormatSQL(sql, `, `)
+				}
+				sqlexec.MustFormatSQL(sql, `password_lifetime=%?`, passwordLifetime)
+			}
+			sqlexec.MustFormatSQL(sql, ` WHERE Host=%? and User=%?;`, spec.User.Hostname, spec.User.Username)
+			stmt, err := exec.ParseWithParams(context.TODO(), sql.String())
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if accountUnlock {
+			if err = e.resetLoginFailures(spec.User.Username, spec.User.Hostname); err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
 			}
 		}
 
@@ -1118,6 +1189,17 @@ func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 			break
 		}
 
+		// rename the password history and the failed logins
+		if err = renameUserHostInSystemTable(sqlExecutor, "password_history", "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " mysql.password_history error"
+			break
+		}
+
+		if err = renameUserHostInSystemTable(sqlExecutor, "login_failures", "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " mysql.login_failures error"
+			break
+		}
+
 		//TODO: need update columns_priv once we implement columns_priv functionality.
 		// When that is added, please refactor both executeRenameUser and executeDropUser to use an array of tables
 		// to loop over, so it is easier to maintain.
@@ -1280,6 +1362,21 @@ func (e *SimpleExec) executeDropUser(s *ast.DropUserStmt) error {
 			break
 		}
 
+		// delete the password history and the failed logins
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, "password_history", user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, "login_failures", user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
 		//TODO: need delete columns_priv once we implement columns_priv functionality.
 	}
 
@@ -1334,6 +1431,187 @@ func userExistsInternal(sqlExecutor sqlexec.SQLExecutor, name string, host strin
 	return rows > 0, err
 }
 
+// encodedPassword returns the password of the user spec which is encoded by its authentication plugin,
+// sha256_password is not handled by the parser and shares the format of caching_sha2_password.
+func encodedPassword(spec *ast.UserSpec) (string, bool) {
+	opt := spec.AuthOpt
+	if opt == nil || opt.AuthPlugin != privileges.AuthSha256Password {
+		return spec.EncodedPassword()
+	}
+	if opt.ByAuthString {
+		return auth.NewSha2Password(opt.AuthString), true
+	}
+	if opt.HashString != "" && len(opt.HashString) != mysql.SHAPWDHashLen {
+		return "", false
+	}
+	return opt.HashString, true
+}
+
+// passwordExpireOptions returns the password_expired and password_lifetime set by the PASSWORD EXPIRE options,
+// lifetimeSet is false if no option changes the password lifetime.
+func passwordExpireOptions(options []*ast.PasswordOrLockOption) (expired bool, lifetime interface{}, lifetimeSet bool) {
+	for _, option := range options {
+		switch option.Type {
+		case ast.PasswordExpire:
+			expired = true
+		case ast.PasswordExpireDefault:
+			lifetime, lifetimeSet = nil, true
+		case ast.PasswordExpireNever:
+			lifetime, lifetimeSet = 0, true
+		case ast.PasswordExpireInterval:
+			lifetime, lifetimeSet = option.Count, true
+		}
+	}
+	return
+}
+
+// hasAccountUnlock returns whether the options contain ACCOUNT UNLOCK.
+func hasAccountUnlock(options []*ast.PasswordOrLockOption) bool {
+	for _, option := range options {
+		if option.Type == ast.Unlock {
+			return true
+		}
+	}
+	return false
+}
+
+// passwordHistory is the password reuse policy and the previous passwords of an account.
+type passwordHistory struct {
+	// history is the number of the most recent passwords which can't be reused.
+	history int64
+	// interval is the number of days in which the passwords can't be reused.
+	interval int64
+	// records are the previous passwords, the most recent one comes first.
+	records []passwordHistoryRecord
+}
+
+type passwordHistoryRecord struct {
+	password  string
+	timestamp string
+	// inInterval is true if the password was set within the password reuse interval.
+	inInterval bool
+}
+
+// loadPasswordHistory loads the password history of the account, the global variables password_history and
+// password_reuse_interval are used if the policy is not set for the account.
+func (e *SimpleExec) loadPasswordHistory(user, host string) (*passwordHistory, error) {
+	h := &passwordHistory{history: -1, interval: -1}
+	if checker := privilege.GetPrivilegeManager(e.ctx); checker != nil {
+		if policy, ok := checker.GetPasswordPolicy(user, host); ok {
+			h.history, h.interval = policy.ReuseHistory, policy.ReuseInterval
+		}
+	}
+	var err error
+	if h.history < 0 {
+		if h.history, err = e.getGlobalUintVar(variable.PasswordHistory); err != nil {
+			return nil, err
+		}
+	}
+	if h.interval < 0 {
+		if h.interval, err = e.getGlobalUintVar(variable.PasswordReuseInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT Password, CAST(Password_timestamp AS CHAR), Password_timestamp >= DATE_SUB(NOW(6), INTERVAL %? DAY)
+		FROM %n.%n WHERE User=%? AND Host=%? ORDER BY Password_timestamp DESC`, h.interval, mysql.SystemDB, "password_history", user, host)
+	if err != nil {
+		return nil, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return nil, err
+	}
+	h.records = make([]passwordHistoryRecord, 0, len(rows))
+	for _, row := range rows {
+		h.records = append(h.records, passwordHistoryRecord{
+			password:   row.GetString(0),
+			timestamp:  row.GetString(1),
+			inInterval: h.interval > 0 && row.GetInt64(2) == 1,
+		})
+	}
+	return h, nil
+}
+
+func (e *SimpleExec) getGlobalUintVar(name string) (int64, error) {
+	val, err := variable.GetGlobalSystemVar(e.ctx.GetSessionVars(), name)
+	if err != nil || val == "" {
+		return 0, err
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+// check returns an error if the new password is one of the previous passwords which can't be reused. The password
+// is the cleartext password if cleartext is true, otherwise it's the encoded password.
+func (h *passwordHistory) check(user, host, password string, cleartext bool) error {
+	for i, record := range h.records {
+		if int64(i) >= h.history && !record.inInterval {
+			break
+		}
+		if passwordMatches(record.password, password, cleartext) {
+			return ErrCredentialsContradictToHistory.GenWithStackByArgs(user, host)
+		}
+	}
+	return nil
+}
+
+// passwordMatches returns whether the new password is the same as the stored password.
+func passwordMatches(authString, password string, cleartext bool) bool {
+	if !cleartext {
+		return authString == password
+	}
+	if len(authString) == mysql.SHAPWDHashLen {
+		ok, err := auth.CheckShaPassword([]byte(authString), password)
+		return err == nil && ok
+	}
+	return authString == auth.EncodePassword(password)
+}
+
+// recordPasswordHistory records the new encoded password of the account. The previous passwords are kept only
+// if they are still within the password reuse policy.
+func (e *SimpleExec) recordPasswordHistory(user, host, pwd string, h *passwordHistory) error {
+	kept := 0
+	if h.history > 0 || h.interval > 0 {
+		// The new password is the most recent one.
+		for kept < len(h.records) && (int64(kept) < h.history-1 || h.records[kept].inInterval) {
+			kept++
+		}
+	}
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	if kept < len(h.records) {
+		stmt, err := exec.ParseWithParams(context.TODO(), `DELETE FROM %n.%n WHERE User=%? AND Host=%? AND Password_timestamp <= %?`,
+			mysql.SystemDB, "password_history", user, host, h.records[kept].timestamp)
+		if err != nil {
+			return err
+		}
+		if _, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt); err != nil {
+			return err
+		}
+	}
+	if h.history <= 0 && h.interval <= 0 {
+		return nil
+	}
+	stmt, err := exec.ParseWithParams(context.TODO(), `INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?)`,
+		mysql.SystemDB, "password_history", host, user, pwd)
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	return err
+}
+
+// resetLoginFailures unlocks the account which is locked by too many consecutive failed logins.
+func (e *SimpleExec) resetLoginFailures(user, host string) error {
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `DELETE FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, "login_failures", user, host)
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	return err
+}
+
 func (e *SimpleExec) userAuthPlugin(name string, host string) (string, error) {
 	pm := privilege.GetPrivilegeManager(e.ctx)
 	authplugin, err := pm.GetAuthPlugin(name, host)
@@ -1373,23 +1651,43 @@ func (e *SimpleExec) executeSetPwd(s *ast.SetPwdStmt) error {
 		return err
 	}
 	var pwd string
-	if authplugin == mysql.AuthCachingSha2Password {
+	if authplugin == mysql.AuthCachingSha2Password || authplugin == privileges.AuthSha256Password {
 		pwd = auth.NewSha2Password(s.Password)
 	} else {
 		pwd = auth.EncodePassword(s.Password)
 	}
 
+	history, err := e.loadPasswordHistory(u, h)
+	if err != nil {
+		return err
+	}
+	if err = history.check(u, h, s.Password, true); err != nil {
+		return err
+	}
+
 	// update mysql.user
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
+	stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%?, password_expired='N', password_last_changed=CURRENT_TIMESTAMP() WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
 	if err != nil {
 		return err
 	}
 	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err == nil {
+		err = e.recordPasswordHistory(u, h, pwd, history)
+		e.passwordChanged(u, h)
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
 
+// passwordChanged leaves the sandbox mode if the password of the current user is reset.
+func (e *SimpleExec) passwordChanged(user, host string) {
+	sessVars := e.ctx.GetSessionVars()
+	if sessVars.User != nil && sessVars.User.AuthUsername == user && sessVars.User.AuthHostname == host {
+		sessVars.PasswordExpired = false
+	}
+}
+
 func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error {
 	if !config.GetGlobalConfig().Experimental.EnableGlobalKill {
 		conf := config.GetGlobalConfig()
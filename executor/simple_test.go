@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
@@ -888,3 +889,35 @@ func (s *testSuite3) TestIssue23649(c *C) {
 	_, err = tk.Exec("GRANT bogusrole to nonexisting;")
 	c.Assert(err.Error(), Equals, "[executor:3523]Unknown authorization ID `bogusrole`@`%`")
 }
+
+func (s *testSuite7) TestPasswordExpireAndHistory(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'expire_user'@'%' IDENTIFIED BY 'pass1' PASSWORD EXPIRE NEVER")
+	tk.MustQuery("SELECT password_expired, password_lifetime FROM mysql.user WHERE User = 'expire_user'").Check(testkit.Rows("N 0"))
+	tk.MustExec("ALTER USER 'expire_user'@'%' PASSWORD EXPIRE INTERVAL 10 DAY")
+	tk.MustQuery("SELECT password_expired, password_lifetime FROM mysql.user WHERE User = 'expire_user'").Check(testkit.Rows("N 10"))
+	tk.MustExec("ALTER USER 'expire_user'@'%' PASSWORD EXPIRE DEFAULT")
+	tk.MustQuery("SELECT password_expired, password_lifetime FROM mysql.user WHERE User = 'expire_user'").Check(testkit.Rows("N <nil>"))
+	tk.MustExec("ALTER USER 'expire_user'@'%' PASSWORD EXPIRE")
+	tk.MustQuery("SELECT password_expired FROM mysql.user WHERE User = 'expire_user'").Check(testkit.Rows("Y"))
+	tk.MustExec("ALTER USER 'expire_user'@'%' IDENTIFIED BY 'pass2'")
+	tk.MustQuery("SELECT password_expired FROM mysql.user WHERE User = 'expire_user'").Check(testkit.Rows("N"))
+
+	// The password history isn't recorded by default.
+	tk.MustQuery("SELECT count(*) FROM mysql.password_history WHERE User = 'expire_user'").Check(testkit.Rows("0"))
+	tk.MustExec("ALTER USER 'expire_user'@'%' IDENTIFIED BY 'pass2'")
+
+	tk.MustExec("SET GLOBAL password_history = 2")
+	defer tk.MustExec("SET GLOBAL password_history = DEFAULT")
+	tk.MustExec("CREATE USER 'history_user'@'%' IDENTIFIED BY 'pass1'")
+	tk.MustExec("ALTER USER 'history_user'@'%' IDENTIFIED BY 'pass2'")
+	tk.MustGetErrCode("ALTER USER 'history_user'@'%' IDENTIFIED BY 'pass1'", errno.ErrCredentialsContradictToHistory)
+	tk.MustGetErrCode("SET PASSWORD FOR 'history_user'@'%' = 'pass2'", errno.ErrCredentialsContradictToHistory)
+	tk.MustExec("ALTER USER 'history_user'@'%' IDENTIFIED BY 'pass3'")
+	tk.MustQuery("SELECT count(*) FROM mysql.password_history WHERE User = 'history_user'").Check(testkit.Rows("2"))
+	// pass1 is out of the history now.
+	tk.MustExec("SET PASSWORD FOR 'history_user'@'%' = 'pass1'")
+
+	tk.MustExec("DROP USER 'expire_user'@'%', 'history_user'@'%'")
+	tk.MustQuery("SELECT count(*) FROM mysql.password_history WHERE User IN ('expire_user', 'history_user')").Check(testkit.Rows("0"))
+}
@@ -16,6 +16,7 @@ package executor
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -316,6 +317,10 @@ func (e *slowQueryRetriever) getBatchLog(ctx context.Context, reader *bufio.Read
 			}
 			line = string(hack.String(lineByte))
 			log = append(log, line)
+			if strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+				// A slow log entry in JSON format is always in a single line.
+				break
+			}
 			if strings.HasSuffix(line, variable.SlowLogSQLSuffixStr) {
 				if strings.HasPrefix(line, "use") || strings.HasPrefix(line, variable.SlowLogRowPrefixStr) {
 					continue
@@ -367,6 +372,13 @@ func (e *slowQueryRetriever) getBatchLogForReversedScan(ctx context.Context, rea
 			return nil, err
 		}
 		line = string(hack.String(lineByte))
+		if !hasStartFlag && strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+			logs = append(logs, []string{line})
+			if scanPreviousFile {
+				break
+			}
+			continue
+		}
 		if !hasStartFlag && strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
 			hasStartFlag = true
 		}
@@ -513,6 +525,17 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 			return nil, ctx.Err()
 		}
 		fileLine := getLineIndex(offset, index)
+		if !startFlag && strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+			st, err := e.parseJSONLog(sctx, tz, line, fileLine)
+			if err != nil {
+				sctx.GetSessionVars().StmtCtx.AppendWarning(err)
+				continue
+			}
+			if st != nil && e.checker.hasPrivilege(st.user) {
+				data = append(data, st.convertToDatumRow())
+			}
+			continue
+		}
 		if !startFlag && strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
 			st = &slowQueryTuple{}
 			valid, err := st.setFieldValue(tz, variable.SlowLogTimeStr, line[len(variable.SlowLogStartPrefixStr):], fileLine, e.checker)
@@ -530,6 +553,10 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 				line = line[len(variable.SlowLogRowPrefixStr):]
 				if strings.HasPrefix(line, variable.SlowLogPrevStmtPrefix) {
 					st.prevStmt = line[len(variable.SlowLogPrevStmtPrefix):]
+				} else if strings.HasPrefix(line, variable.SlowLogQueryAttributesPrefix) {
+					// The query attributes are not a column of the slow query table, skip the line as a whole
+					// since the values may contain spaces which can't be split as the other fields.
+					continue
 				} else if strings.HasPrefix(line, variable.SlowLogUserAndHostStr+variable.SlowLogSpaceMarkStr) {
 					value := line[len(variable.SlowLogUserAndHostStr+variable.SlowLogSpaceMarkStr):]
 					valid, err := st.setFieldValue(tz, variable.SlowLogUserAndHostStr, value, fileLine, e.checker)
@@ -588,6 +615,48 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 	return data, nil
 }
 
+// parseJSONLog parses a slow log entry in JSON format, whose keys are the same as the field names in the text
+// format. It returns nil if the entry is filtered out by the checker.
+func (e *slowQueryRetriever) parseJSONLog(sctx sessionctx.Context, tz *time.Location, line string, lineNum int) (*slowQueryTuple, error) {
+	st := &slowQueryTuple{}
+	dec := json.NewDecoder(strings.NewReader(line))
+	if _, err := dec.Token(); err != nil {
+		return nil, errors.Errorf("Parse slow log at line %v failed, error: %v", lineNum, err)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, errors.Errorf("Parse slow log at line %v failed, error: %v", lineNum, err)
+		}
+		field, _ := key.(string)
+		var value string
+		if err = dec.Decode(&value); err != nil {
+			return nil, errors.Errorf("Parse slow log at line %v failed. Field: `%v`, error: %v", lineNum, field, err)
+		}
+		if field == variable.SlowLogPrevStmt {
+			st.prevStmt = value
+			continue
+		}
+		if strings.HasPrefix(field, variable.SlowLogCopBackoffPrefix) {
+			// Keep the backoff detail the same as the one parsed from the text format.
+			value = field + variable.SlowLogSpaceMarkStr + value
+			field = variable.SlowLogBackoffDetail
+		}
+		valid, err := st.setFieldValue(tz, field, value, lineNum, e.checker)
+		if err != nil {
+			if field == variable.SlowLogTimeStr {
+				return nil, err
+			}
+			sctx.GetSessionVars().StmtCtx.AppendWarning(err)
+			continue
+		}
+		if !valid {
+			return nil, nil
+		}
+	}
+	return st, nil
+}
+
 type slowQueryTuple struct {
 	time                      types.Time
 	txnStartTs                uint64
@@ -1091,9 +1160,8 @@ func (e *slowQueryRetriever) getFileStartTime(ctx context.Context, file *os.File
 		if err != nil {
 			return t, err
 		}
-		line := string(lineByte)
-		if strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
-			return ParseTime(line[len(variable.SlowLogStartPrefixStr):])
+		if timeStr, ok := getSlowLogStartTime(string(lineByte)); ok {
+			return ParseTime(timeStr)
 		}
 		maxNum -= 1
 		if maxNum <= 0 {
@@ -1153,6 +1221,21 @@ func (s *slowQueryRuntimeStats) Tp() int {
 	return execdetails.TpSlowQueryRuntimeStat
 }
 
+// getSlowLogStartTime returns the time of a slow log entry in either format if the line is the start of it.
+func getSlowLogStartTime(line string) (string, bool) {
+	if strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
+		return line[len(variable.SlowLogStartPrefixStr):], true
+	}
+	if strings.HasPrefix(line, variable.SlowLogJSONStartPrefixStr) {
+		// The time never contains characters which need escaping in JSON.
+		value := line[len(variable.SlowLogJSONStartPrefixStr):]
+		if end := strings.IndexByte(value, '"'); end >= 0 {
+			return value[:end], true
+		}
+	}
+	return "", false
+}
+
 func (e *slowQueryRetriever) getFileEndTime(ctx context.Context, file *os.File) (time.Time, error) {
 	var t time.Time
 	var tried int
@@ -1173,8 +1256,8 @@ func (e *slowQueryRetriever) getFileEndTime(ctx context.Context, file *os.File)
 		}
 		endCursor -= int64(readBytes)
 		for i := len(lines) - 1; i >= 0; i-- {
-			if strings.HasPrefix(lines[i], variable.SlowLogStartPrefixStr) {
-				return ParseTime(lines[i][len(variable.SlowLogStartPrefixStr):])
+			if timeStr, ok := getSlowLogStartTime(lines[i]); ok {
+				return ParseTime(timeStr)
 			}
 		}
 		tried += len(lines)
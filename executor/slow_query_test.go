@@ -17,6 +17,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -305,6 +306,19 @@ select * from t;
 	c.Assert(warnings, HasLen, 1)
 	c.Assert(warnings[0].Err.Error(), Equals, "Parse slow log at line 2 failed. Field: `Txn_start_ts`, error: strconv.ParseUint: parsing \"405888132465033227#\": invalid syntax")
 
+	// The values of query attributes are not parsed as fields.
+	slowLog = bytes.NewBufferString(
+		`# Time: 2019-05-12-11:23:29.614327491 +0800
+# Txn_start_ts: 405888132465033227
+# Query_attributes: {"trace_id":"a Txn_start_ts: b"}
+select * from t;
+`)
+	ctx.GetSessionVars().StmtCtx.SetWarnings(nil)
+	scanner = bufio.NewReader(slowLog)
+	rows, err := parseSlowLog(ctx, scanner, 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 1)
+	c.Assert(ctx.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
 }
 
 func (s *testExecSuite) TestSlowQueryRetriever(c *C) {
@@ -581,6 +595,156 @@ select 9;`
 	}
 }
 
+func (s *testExecSuite) TestParseSlowLogJSON(c *C) {
+	slowLogStr := `{"Time":"2019-04-28T15:24:04.309074+08:00","Txn_start_ts":"405888132465033227","User@Host":"root[root] @ localhost [127.0.0.1]",` +
+		`"Exec_retry_time":"0.12","Exec_retry_count":"57","Query_time":"0.216905",` +
+		`"Cop_time":"0.38","Process_time":"0.021","Request_count":"1","Total_keys":"637","Processed_keys":"436",` +
+		`"Rocksdb_delete_skipped_count":"10","Rocksdb_key_skipped_count":"10","Rocksdb_block_cache_hit_count":"10","Rocksdb_block_read_count":"10","Rocksdb_block_read_byte":"100",` +
+		`"DB":"test","Is_internal":"true","Digest":"42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772","Stats":"t1:1,t2:2",` +
+		`"Cop_proc_avg":"0.1","Cop_proc_p90":"0.2","Cop_proc_max":"0.03","Cop_proc_addr":"127.0.0.1:20160",` +
+		`"Cop_wait_avg":"0.05","Cop_wait_p90":"0.6","Cop_wait_max":"0.8","Cop_wait_addr":"0.0.0.0:20160",` +
+		`"Cop_backoff_regionMiss_total_times":"200","Cop_backoff_regionMiss_total_time":"0.2","Cop_backoff_regionMiss_max_time":"0.2",` +
+		`"Cop_backoff_regionMiss_max_addr":"127.0.0.1","Cop_backoff_regionMiss_avg_time":"0.2","Cop_backoff_regionMiss_p90_time":"0.2",` +
+		`"Mem_max":"70724","Disk_max":"65536","Plan_from_cache":"true","Plan_from_binding":"true","Succ":"false",` +
+		`"Plan_digest":"60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4","Prev_stmt":"update t set i = 1;",` +
+		`"Query":"select *\nfrom t where a < \"x\";"}`
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().TimeZone = loc
+	reader := bufio.NewReader(bytes.NewBufferString(slowLogStr))
+	rows, err := parseSlowLog(ctx, reader, 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 1)
+	recordString := ""
+	for i, value := range rows[0] {
+		str, err := value.ToString()
+		c.Assert(err, IsNil)
+		if i > 0 {
+			recordString += ","
+		}
+		recordString += str
+	}
+	expectRecordString := `2019-04-28 15:24:04.309074,` +
+		`405888132465033227,root,localhost,0,57,0.12,0.216905,` +
+		`0,0,0,0,0,0,0,0,0,0,0,0,,0,0,0,0,0,0,0.38,0.021,0,0,0,1,637,0,10,10,10,10,100,test,,1,42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772,t1:1,t2:2,` +
+		`0.1,0.2,0.03,127.0.0.1:20160,0.05,0.6,0.8,0.0.0.0:20160,70724,65536,0,0,0,0,` +
+		`Cop_backoff_regionMiss_total_times: 200 Cop_backoff_regionMiss_total_time: 0.2 Cop_backoff_regionMiss_max_time: 0.2 Cop_backoff_regionMiss_max_addr: 127.0.0.1 Cop_backoff_regionMiss_avg_time: 0.2 Cop_backoff_regionMiss_p90_time: 0.2,` +
+		`0,0,1,1,,60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4,` +
+		"update t set i = 1;,select *\nfrom t where a < \"x\";"
+	c.Assert(recordString, Equals, expectRecordString)
+
+	// Both formats can be parsed from the same file.
+	slowLog := bytes.NewBufferString(
+		`# Time: 2019-04-28T15:24:04.309074+08:00
+# Txn_start_ts: 405888132465033227
+select 1;
+{"Time":"2019-04-28T15:24:05.309074+08:00","Txn_start_ts":"405888132465033228","Query":"select 2;"}
+{"Time":"2019-04-28T15:24:06.309074+08:00","Txn_start_ts":"405888132465033229#","Query":"select 3;"}
+{"Time":"2019-04-28T15:24:07.309074+08:00","Txn_start_ts":"405888132465033230","Query":
+# Time: 2019-04-28T15:24:08.309074+08:00
+# Txn_start_ts: 405888132465033231
+select 4;
+`)
+	ctx = mock.NewContext()
+	ctx.GetSessionVars().TimeZone = loc
+	reader = bufio.NewReader(slowLog)
+	rows, err = parseSlowLog(ctx, reader, 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 4)
+	for i, row := range rows {
+		c.Assert(row[len(row)-1].GetString(), Equals, fmt.Sprintf("select %d;", i+1))
+	}
+	c.Assert(rows[1][1].GetUint64(), Equals, uint64(405888132465033228))
+	warnings := ctx.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 2)
+	c.Assert(warnings[0].Err.Error(), Equals, "Parse slow log at line 5 failed. Field: `Txn_start_ts`, error: strconv.ParseUint: parsing \"405888132465033229#\": invalid syntax")
+	c.Assert(warnings[1].Err.Error(), Equals, "Parse slow log at line 6 failed. Field: `Query`, error: unexpected EOF")
+}
+
+func (s *testExecSuite) TestSlowQueryRetrieverJSON(c *C) {
+	logData0 := `{"Time":"2020-02-15T18:00:01.000000+08:00","Query":"select 1;"}
+{"Time":"2020-02-15T19:00:05.000000+08:00","Query":"select 2;"}
+`
+	logData1 := `{"Time":"2020-02-16T18:00:01.000000+08:00","Query":"select 3;"}
+# Time: 2020-02-16T18:00:05.000000+08:00
+select 4;
+{"Time":"2020-02-16T19:00:05.000000+08:00","Query":"select 5;"}
+`
+	logData := []string{logData0, logData1}
+	fileName0 := "tidb-slow-2020-02-15T19-04-05.01.log"
+	fileName1 := "tidb-slow.log"
+	fileNames := []string{fileName0, fileName1}
+	prepareLogs(c, logData, fileNames)
+	defer func() {
+		removeFiles(fileNames)
+	}()
+
+	cases := []struct {
+		startTime string
+		endTime   string
+		desc      bool
+		files     []string
+		querys    []string
+	}{
+		{
+			startTime: "2020-02-15T18:00:00.000000+08:00",
+			endTime:   "2020-02-15T19:00:00.000000+08:00",
+			files:     []string{fileName0},
+			querys:    []string{"select 1;"},
+		},
+		{
+			startTime: "2020-02-16T18:00:00.000000+08:00",
+			endTime:   "2020-02-16T20:00:00.000000+08:00",
+			files:     []string{fileName1},
+			querys:    []string{"select 3;", "select 4;", "select 5;"},
+		},
+		{
+			startTime: "2020-02-16T18:00:00.000000+08:00",
+			endTime:   "2020-02-16T20:00:00.000000+08:00",
+			desc:      true,
+			files:     []string{fileName1},
+			querys:    []string{"select 5;", "select 4;", "select 3;"},
+		},
+		{
+			startTime: "2020-02-17T18:00:00.000000+08:00",
+			endTime:   "2020-02-17T20:00:00.000000+08:00",
+		},
+	}
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	sctx := mock.NewContext()
+	sctx.GetSessionVars().TimeZone = loc
+	sctx.GetSessionVars().SlowQueryFile = fileName1
+	for i, cas := range cases {
+		startTime, err := ParseTime(cas.startTime)
+		c.Assert(err, IsNil)
+		endTime, err := ParseTime(cas.endTime)
+		c.Assert(err, IsNil)
+		extractor := &plannercore.SlowQueryExtractor{Enable: true, Desc: cas.desc}
+		extractor.TimeRanges = []*plannercore.TimeRange{{StartTime: startTime, EndTime: endTime}}
+		retriever := &slowQueryRetriever{extractor: extractor}
+		err = retriever.initialize(context.Background(), sctx)
+		c.Assert(err, IsNil)
+		comment := Commentf("case id: %v", i)
+		c.Assert(retriever.files, HasLen, len(cas.files), comment)
+		for j, file := range retriever.files {
+			c.Assert(file.file.Name(), Equals, cas.files[j], comment)
+		}
+		if len(retriever.files) > 0 {
+			reader := bufio.NewReader(retriever.files[0].file)
+			rows, err := parseLog(retriever, sctx, reader, 64)
+			c.Assert(err, IsNil)
+			c.Assert(rows, HasLen, len(cas.querys), comment)
+			for j, row := range rows {
+				c.Assert(row[len(row)-1].GetString(), Equals, cas.querys[j], comment)
+			}
+		}
+		c.Assert(retriever.close(), IsNil)
+	}
+}
+
 func prepareLogs(c *C, logData []string, fileNames []string) {
 	writeFile := func(file string, data string) {
 		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
@@ -15,18 +15,25 @@ package executor
 
 import (
 	"context"
+	"sort"
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
+// The rows of a partition are buffered in a RowContainer, which is spilled to disk
+// when the memory quota of the query is exceeded, so a big partition won't cause OOM.
 type WindowExec struct {
 	baseExecutor
 
@@ -35,162 +42,312 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// rowContainer stores the rows of current partition which don't belong to childResult.
+	rowContainer *chunk.RowContainer
+	// partition provides the rows of current partition, it is nil if current partition is fully output.
+	partition *windowPartition
+	// numOutputRows indicates how many rows of current partition have been output.
+	numOutputRows uint64
+	// childColIdxs indicates the child columns which are output by the executor.
+	childColIdxs []int
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+	spillAction *chunk.SpillDiskAction
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.executed = false
+	e.partition, e.numOutputRows = nil, 0
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
+	}
+
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+
+	e.childResult = newFirstChunk(e.children[0])
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+	e.rowContainer = chunk.NewRowContainer(retTypes(e.children[0]), e.maxChunkSize)
+	e.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	e.rowContainer.GetMemTracker().SetLabel(memory.LabelForRowContainer)
+	e.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	e.rowContainer.GetDiskTracker().SetLabel(memory.LabelForRowContainer)
+	e.spillAction = nil
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	e.partition = nil
+	e.childResult = nil
+	if e.rowContainer != nil {
+		if err := e.rowContainer.Close(); err != nil {
+			return err
+		}
+		e.rowContainer = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.ReplaceBytesUsed(0)
+	}
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
+	for !chk.IsFull() {
+		if e.partition == nil {
+			if e.executed {
+				return nil
+			}
+			if err := e.fetchPartition(ctx); err != nil {
+				e.executed = true
+				return err
+			}
+			if e.partition == nil {
+				return nil
+			}
+		}
+		if err := e.appendPartitionResult(chk); err != nil {
 			e.executed = true
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
-	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
-}
-
-func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
+// fetchPartition gathers the rows of the next partition and consumes them by the window processor.
+func (e *WindowExec) fetchPartition(ctx context.Context) error {
 	if e.groupChecker.isExhausted() {
 		eof, err := e.fetchChild(ctx)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
+		if err != nil || eof {
+			return err
 		}
-		_, err = e.groupChecker.splitIntoGroups(e.childResult)
-		if err != nil {
+		if _, err = e.groupChecker.splitIntoGroups(e.childResult); err != nil {
 			return errors.Trace(err)
 		}
 	}
 	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
-	}
-
-	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
-		meetLastGroup = false
+	for end == e.childResult.NumRows() {
+		// The partition may continue in the next child chunk,
+		// so the rows are handed over to the RowContainer.
+		if err := e.moveRowsToContainer(begin, end); err != nil {
+			return err
+		}
+		begin, end = 0, 0
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
-			return errors.Trace(err)
+			return err
 		}
 		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			break
 		}
-
 		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
 		if err != nil {
 			return errors.Trace(err)
 		}
-
-		if isFirstGroupSameAsPrev {
-			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
-			}
-			meetLastGroup = end == e.childResult.NumRows()
+		if !isFirstGroupSameAsPrev {
+			break
 		}
+		begin, end = e.groupChecker.getNextGroup()
 	}
-	return e.consumeGroupRows(groupRows)
-}
-
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
+	partition := newWindowPartition(e.rowContainer, e.childResult, begin, end)
+	if partition.numRows == 0 {
 		return nil
 	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
+	if err := e.processor.consumeGroupRows(e.ctx, partition); err != nil {
+		return errors.Trace(err)
+	}
+	e.partition, e.numOutputRows = partition, 0
+	return nil
+}
 
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
-		if err != nil {
-			return errors.Trace(err)
+// moveRowsToContainer moves the rows [begin, end) of childResult to the RowContainer,
+// and allocates a new chunk for the child executor.
+func (e *WindowExec) moveRowsToContainer(begin, end int) error {
+	if begin < end {
+		sel := make([]int, 0, end-begin)
+		for i := begin; i < end; i++ {
+			sel = append(sel, i)
+		}
+		e.childResult.SetSel(sel)
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+		// The spill action is registered lazily, so the RowContainer won't be spilled
+		// before holding any rows when the child executor exceeds the memory quota.
+		if e.spillAction == nil && config.GetGlobalConfig().OOMUseTmpStorage {
+			e.spillAction = e.rowContainer.ActionSpill()
+			failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+				if val.(bool) {
+					e.spillAction = e.rowContainer.ActionSpillForTest()
+				}
+			})
+			e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
 		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
+		if err := e.rowContainer.Add(e.childResult); err != nil {
+			return err
 		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				e.spillAction.WaitForTest()
+			}
+		})
+	} else {
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+	}
+	e.childResult = e.rowContainer.AllocChunk()
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+	return nil
+}
+
+// appendPartitionResult appends the rows of current partition and their window function results to chk.
+func (e *WindowExec) appendPartitionResult(chk *chunk.Chunk) error {
+	remained := mathutil.Min(chk.RequiredRows()-chk.NumRows(), int(e.partition.numRows-e.numOutputRows))
+	for i := 0; i < remained; i++ {
+		row, err := e.partition.getRow(e.numOutputRows + uint64(i))
+		if err != nil {
+			return err
 		}
+		chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
+	}
+	if err := e.processor.appendResult2Chunk(e.ctx, e.partition, chk, remained); err != nil {
+		return errors.Trace(err)
+	}
+	e.numOutputRows += uint64(remained)
+	if e.numOutputRows == e.partition.numRows {
+		e.processor.resetPartialResult()
+		e.partition = nil
+		return e.rowContainer.Reset()
 	}
 	return nil
 }
 
 func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	mSize := e.childResult.MemoryUsage()
+	err = Next(ctx, e.children[0], e.childResult)
+	e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
+	if e.childResult.NumRows() == 0 {
+		e.executed = true
 		return true, nil
 	}
+	return false, nil
+}
 
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
+// windowPartition provides the random access to the rows of a partition. The leading rows of the
+// partition are stored in a RowContainer which may be spilled to disk, and the others are in a chunk.
+type windowPartition struct {
+	rowContainer *chunk.RowContainer
+	// chkEnds[i] is the number of rows in the first i+1 chunks of rowContainer.
+	chkEnds            []uint64
+	numRowsInContainer uint64
+	// tail and tailBegin indicate the rows of the partition which are not in rowContainer.
+	tail      *chunk.Chunk
+	tailBegin int
+	numRows   uint64
+
+	// cachedChk caches the recently accessed chunk of rowContainer,
+	// to avoid reading the rows from disk one by one.
+	cachedChkIdx int
+	cachedChk    *chunk.Chunk
+	rowsBuf      []chunk.Row
+}
+
+func newWindowPartition(rowContainer *chunk.RowContainer, tail *chunk.Chunk, tailBegin, tailEnd int) *windowPartition {
+	p := &windowPartition{
+		rowContainer: rowContainer,
+		tail:         tail,
+		tailBegin:    tailBegin,
+		cachedChkIdx: -1,
 	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+	numChks := rowContainer.NumChunks()
+	p.chkEnds = make([]uint64, 0, numChks)
+	for i := 0; i < numChks; i++ {
+		p.numRowsInContainer += uint64(rowContainer.NumRowsOfChunk(i))
+		p.chkEnds = append(p.chkEnds, p.numRowsInContainer)
+	}
+	p.numRows = p.numRowsInContainer + uint64(tailEnd-tailBegin)
+	return p
+}
 
-	e.childResult = childResult
-	return false, nil
+func (p *windowPartition) getRow(idx uint64) (chunk.Row, error) {
+	if idx >= p.numRowsInContainer {
+		return p.tail.GetRow(p.tailBegin + int(idx-p.numRowsInContainer)), nil
+	}
+	chkIdx := sort.Search(len(p.chkEnds), func(i int) bool { return p.chkEnds[i] > idx })
+	if chkIdx != p.cachedChkIdx {
+		chk, err := p.rowContainer.GetChunk(chkIdx)
+		if err != nil {
+			return chunk.Row{}, err
+		}
+		p.cachedChkIdx, p.cachedChk = chkIdx, chk
+	}
+	chkBegin := uint64(0)
+	if chkIdx > 0 {
+		chkBegin = p.chkEnds[chkIdx-1]
+	}
+	return p.cachedChk.GetRow(int(idx - chkBegin)), nil
 }
 
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
+// getRows returns the rows in [start, end). The returned slice is reused by the next call.
+func (p *windowPartition) getRows(start, end uint64) ([]chunk.Row, error) {
+	rows, err := p.appendRows(p.rowsBuf[:0], start, end)
+	p.rowsBuf = rows
+	return rows, err
+}
+
+// appendRows appends the rows in [start, end) to dst.
+func (p *windowPartition) appendRows(dst []chunk.Row, start, end uint64) ([]chunk.Row, error) {
+	for i := start; i < end; i++ {
+		row, err := p.getRow(i)
+		if err != nil {
+			return dst, err
 		}
+		dst = append(dst, row)
 	}
-	return nil
+	return dst, nil
+}
+
+// slide calls Slide of the window function with the rows which are moved into or out of the frame.
+func (p *windowPartition) slide(ctx sessionctx.Context, windowFunc aggfuncs.SlidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd uint64, pr aggfuncs.PartialResult) error {
+	removedRows, err := p.appendRows(nil, lastStart, lastStart+shiftStart)
+	if err != nil {
+		return err
+	}
+	addedRows, err := p.appendRows(nil, lastEnd, lastEnd+shiftEnd)
+	if err != nil {
+		return err
+	}
+	return windowFunc.Slide(ctx, func(u uint64) chunk.Row {
+		if u >= lastEnd {
+			return addedRows[u-lastEnd]
+		}
+		return removedRows[u-lastStart]
+	}, lastStart, lastEnd, shiftStart, shiftEnd, pr)
 }
 
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
+	consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error
 	// appendResult2Chunk appends the final results to chunk.
 	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -200,30 +357,36 @@ type aggWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	batchSize := uint64(ctx.GetSessionVars().MaxChunkSize)
+	for begin := uint64(0); begin < rows.numRows; begin += batchSize {
+		batch, err := rows.getRows(begin, mathutil.MinUint64(begin+batchSize, rows.numRows))
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for i, windowFunc := range p.windowFuncs {
+			// @todo Add memory trace
+			_, err = windowFunc.UpdatePartialResult(ctx, batch, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -286,12 +449,15 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+// appendFrameResult2Chunk appends the results of the window functions for the next `remained` rows of the partition,
+// getFrame returns the frame [start, end) of the next row. The sliding window functions are updated incrementally
+// when the frame moves, and the others are evaluated on the rows of each frame.
+func appendFrameResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int,
+	windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult, getFrame func() (start, end uint64, err error)) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -302,44 +468,41 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		shiftStart               uint64
 		shiftEnd                 uint64
 	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
+	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(windowFuncs))
+	for i, windowFunc := range windowFuncs {
 		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
 			slidingWindowAggFuncs[i] = slidingWindowAggFunc
 		}
 	}
 	for ; remained > 0; lastStart, lastEnd = start, end {
-		start = p.getStartOffset(numRows)
-		end = p.getEndOffset(numRows)
-		p.curRowIdx++
+		start, end, err = getFrame()
+		if err != nil {
+			return err
+		}
 		remained--
 		shiftStart = start - lastStart
 		shiftEnd = end - lastEnd
 		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
+			for i, windowFunc := range windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = rows.slide(ctx, slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+				err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
 		}
 
-		for i, windowFunc := range p.windowFuncs {
+		for i, windowFunc := range windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = rows.slide(ctx, slidingWindowAggFunc, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
 			} else {
 				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
 				// whether elements inside deque are out of current window.
@@ -347,27 +510,39 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				var frameRows []chunk.Row
+				frameRows, err = rows.getRows(start, end)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, frameRows, partialResults[i])
+				}
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+			err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
+				windowFunc.ResetPartialResult(partialResults[i])
 			}
 		}
 		if !initializedSlidingWindow {
 			initializedSlidingWindow = true
 		}
 	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
+	for i, windowFunc := range windowFuncs {
+		windowFunc.ResetPartialResult(partialResults[i])
 	}
-	return rows, nil
+	return nil
+}
+
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
+	return appendFrameResult2Chunk(ctx, rows, chk, remained, p.windowFuncs, p.partialResults, func() (start, end uint64, err error) {
+		start, end = p.getStartOffset(rows.numRows), p.getEndOffset(rows.numRows)
+		p.curRowIdx++
+		return start, end, nil
+	})
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -387,16 +562,22 @@ type rangeFrameWindowProcessor struct {
 	expectedCmpResult int64
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
-	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
+	for ; p.lastStartOffset < rows.numRows; p.lastStartOffset++ {
+		row, err := rows.getRow(p.lastStartOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], row, curRow)
 			if err != nil {
 				return 0, err
 			}
@@ -413,16 +594,22 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
 	if p.end.UnBounded {
-		return numRows, nil
+		return rows.numRows, nil
+	}
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
 	}
-	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
+	for ; p.lastEndOffset < rows.numRows; p.lastEndOffset++ {
+		row, err := rows.getRow(p.lastEndOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], curRow, row)
 			if err != nil {
 				return 0, err
 			}
@@ -439,94 +626,141 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	var (
-		err                      error
-		initializedSlidingWindow bool
-		start                    uint64
-		end                      uint64
-		lastStart                uint64
-		lastEnd                  uint64
-		shiftStart               uint64
-		shiftEnd                 uint64
-	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			slidingWindowAggFuncs[i] = slidingWindowAggFunc
-		}
-	}
-	for ; remained > 0; lastStart, lastEnd = start, end {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
+	return appendFrameResult2Chunk(ctx, rows, chk, remained, p.windowFuncs, p.partialResults, func() (start, end uint64, err error) {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
 		}
 		p.curRowIdx++
-		remained--
-		shiftStart = start - lastStart
-		shiftEnd = end - lastEnd
-		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
-				slidingWindowAggFunc := slidingWindowAggFuncs[i]
-				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-					if err != nil {
-						return nil, err
-					}
-				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
-				if err != nil {
-					return nil, err
-				}
-			}
-			continue
-		}
+		return start, end, nil
+	})
+}
 
-		for i, windowFunc := range p.windowFuncs {
-			slidingWindowAggFunc := slidingWindowAggFuncs[i]
-			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-			} else {
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
-				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
-			}
-			if err != nil {
-				return nil, err
-			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
+}
+
+func (p *rangeFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.lastStartOffset = 0
+	p.lastEndOffset = 0
+}
+
+// groupsFrameWindowProcessor processes the GROUPS frames, whose offsets are counted in peer groups.
+// The rows of a peer group have the same values of the order by columns.
+type groupsFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	start          *core.FrameBound
+	end            *core.FrameBound
+	orderByCols    []*expression.Column
+	cmpFuncs       []expression.CompareFunc
+	curRowIdx      uint64
+	// groupEnds[i] is the number of rows in the first i+1 peer groups of the partition.
+	groupEnds   []uint64
+	curGroupIdx uint64
+}
+
+func (p *groupsFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	p.groupEnds = p.groupEnds[:0]
+	if rows.numRows == 0 {
+		return nil
+	}
+	prevRow, err := rows.getRow(0)
+	if err != nil {
+		return err
+	}
+	for i := uint64(1); i < rows.numRows; i++ {
+		row, err := rows.getRow(i)
+		if err != nil {
+			return err
+		}
+		for j, col := range p.orderByCols {
+			res, _, err := p.cmpFuncs[j](ctx, col, col, prevRow, row)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
+			if res != 0 {
+				p.groupEnds = append(p.groupEnds, i)
+				break
 			}
 		}
-		if !initializedSlidingWindow {
-			initializedSlidingWindow = true
+		prevRow = row
+	}
+	p.groupEnds = append(p.groupEnds, rows.numRows)
+	return nil
+}
+
+func (p *groupsFrameWindowProcessor) groupBegin(groupIdx uint64) uint64 {
+	if groupIdx == 0 {
+		return 0
+	}
+	return p.groupEnds[groupIdx-1]
+}
+
+func (p *groupsFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
+	if p.start.UnBounded {
+		return 0
+	}
+	switch p.start.Type {
+	case ast.Preceding:
+		if p.curGroupIdx >= p.start.Num {
+			return p.groupBegin(p.curGroupIdx - p.start.Num)
+		}
+		return 0
+	case ast.Following:
+		groupIdx := p.curGroupIdx + p.start.Num
+		if groupIdx >= uint64(len(p.groupEnds)) {
+			return numRows
 		}
+		return p.groupBegin(groupIdx)
+	case ast.CurrentRow:
+		return p.groupBegin(p.curGroupIdx)
 	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
+	// It will never reach here.
+	return 0
+}
+
+func (p *groupsFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
+	if p.end.UnBounded {
+		return numRows
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if p.curGroupIdx >= p.end.Num {
+			return p.groupEnds[p.curGroupIdx-p.end.Num]
+		}
+		return 0
+	case ast.Following:
+		groupIdx := p.curGroupIdx + p.end.Num
+		if groupIdx >= uint64(len(p.groupEnds)) {
+			return numRows
+		}
+		return p.groupEnds[groupIdx]
+	case ast.CurrentRow:
+		return p.groupEnds[p.curGroupIdx]
 	}
-	return rows, nil
+	// It will never reach here.
+	return 0
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *groupsFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
+	return appendFrameResult2Chunk(ctx, rows, chk, remained, p.windowFuncs, p.partialResults, func() (start, end uint64, err error) {
+		for p.groupEnds[p.curGroupIdx] <= p.curRowIdx {
+			p.curGroupIdx++
+		}
+		start, end = p.getStartOffset(rows.numRows), p.getEndOffset(rows.numRows)
+		p.curRowIdx++
+		return start, end, nil
+	})
 }
 
-func (p *rangeFrameWindowProcessor) resetPartialResult() {
+func (p *groupsFrameWindowProcessor) resetPartialResult() {
 	p.curRowIdx = 0
-	p.lastStartOffset = 0
-	p.lastEndOffset = 0
+	p.curGroupIdx = 0
 }
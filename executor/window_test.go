@@ -15,8 +15,12 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -443,6 +447,158 @@ func baseTestSlidingWindowFunctions(tk *testkit.TestKit) {
 	result.Check(testkit.Rows("<nil> 11", "<nil> 11", "M 5", "F 5", "F 4", "F 3", "M 2"))
 }
 
+func (s *testSuite7) TestWindowFunctionsWithGroupsFrameAndModifiers(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, c int)")
+	tk.MustExec("insert into t1 values (1,1),(2,1),(3,2),(4,3),(5,3),(6,3)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t2 values (1,null),(2,20),(3,null),(4,40),(5,null)")
+	defer func() {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = 1")
+	}()
+	for _, pipelined := range []int{0, 1} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %d", pipelined))
+		for _, chunkSize := range []int{1, 32} {
+			tk.Se.GetSessionVars().MaxChunkSize = chunkSize
+
+			// GROUPS frames.
+			tk.MustQuery("select c, sum(a) over(order by c groups between 1 preceding and current row) from t1").Sort().Check(
+				testkit.Rows("1 3", "1 3", "2 6", "3 18", "3 18", "3 18"))
+			tk.MustQuery("select c, sum(a) over(order by c groups between current row and 1 following) from t1").Sort().Check(
+				testkit.Rows("1 6", "1 6", "2 18", "3 15", "3 15", "3 15"))
+			tk.MustQuery("select c, sum(a) over(order by c groups between 1 following and unbounded following) from t1").Sort().Check(
+				testkit.Rows("1 18", "1 18", "2 15", "3 <nil>", "3 <nil>", "3 <nil>"))
+			tk.MustQuery("select c, count(a) over(order by c groups between 2 preceding and 2 preceding) from t1").Sort().Check(
+				testkit.Rows("1 0", "1 0", "2 0", "3 2", "3 2", "3 2"))
+			tk.MustQuery("select c, sum(a) over(order by c desc groups 1 preceding) from t1").Sort().Check(
+				testkit.Rows("1 6", "1 6", "2 18", "3 15", "3 15", "3 15"))
+			tk.MustQuery("select c, sum(a) over(partition by c > 1 order by c groups between current row and 1 following) from t1").Sort().Check(
+				testkit.Rows("1 3", "1 3", "2 18", "3 15", "3 15", "3 15"))
+			tk.MustQuery("select a, sum(a) over(groups between current row and current row) from t1").Check(
+				testkit.Rows("1 21", "2 21", "3 21", "4 21", "5 21", "6 21"))
+
+			// IGNORE NULLS and FROM LAST.
+			tk.MustQuery("select a, first_value(b) ignore nulls over(order by a rows between current row and unbounded following) from t2").Check(
+				testkit.Rows("1 20", "2 20", "3 40", "4 40", "5 <nil>"))
+			tk.MustQuery("select a, last_value(b) ignore nulls over(order by a rows between unbounded preceding and current row) from t2").Check(
+				testkit.Rows("1 <nil>", "2 20", "3 20", "4 40", "5 40"))
+			tk.MustQuery("select a, nth_value(b, 2) ignore nulls over(order by a rows between unbounded preceding and current row) from t2").Check(
+				testkit.Rows("1 <nil>", "2 <nil>", "3 <nil>", "4 40", "5 40"))
+			tk.MustQuery("select a, nth_value(b, 2) from last over(order by a rows between unbounded preceding and current row) from t2").Check(
+				testkit.Rows("1 <nil>", "2 <nil>", "3 20", "4 <nil>", "5 40"))
+			tk.MustQuery("select a, nth_value(b, 2) from last ignore nulls over(order by a rows between unbounded preceding and unbounded following) from t2").Check(
+				testkit.Rows("1 20", "2 20", "3 20", "4 20", "5 20"))
+			tk.MustQuery("select a, lag(b) ignore nulls over(order by a), lead(b) ignore nulls over(order by a) from t2").Check(
+				testkit.Rows("1 <nil> 20", "2 <nil> 40", "3 20 40", "4 20 <nil>", "5 40 <nil>"))
+			tk.MustQuery("select a, lag(b, 1, -1) ignore nulls over(order by a), lead(b, 2, 0) ignore nulls over(order by a) from t2").Check(
+				testkit.Rows("1 -1 40", "2 -1 0", "3 20 0", "4 20 0", "5 40 0"))
+			tk.MustQuery("select a, lag(b, 0) ignore nulls over(order by a) from t2").Check(
+				testkit.Rows("1 <nil>", "2 20", "3 <nil>", "4 40", "5 <nil>"))
+
+			// DISTINCT.
+			tk.MustQuery("select a, sum(distinct c) over(order by a rows between 1 preceding and 1 following) from t1").Check(
+				testkit.Rows("1 1", "2 3", "3 6", "4 5", "5 3", "6 3"))
+			tk.MustQuery("select a, sum(distinct c) over(), avg(distinct c) over(order by a rows between unbounded preceding and current row) from t1").Check(
+				testkit.Rows("1 6 1.0000", "2 6 1.0000", "3 6 1.5000", "4 6 2.0000", "5 6 2.0000", "6 6 2.0000"))
+		}
+	}
+	rows := tk.MustQuery("explain format = 'brief' select sum(a) over(order by c groups 1 preceding), nth_value(a, 2) from last ignore nulls over() from t1").Rows()
+	c.Assert(fmt.Sprintf("%v", rows), Matches, ".*over\\(order by test.t1.c groups between 1 preceding and current row\\).*")
+	c.Assert(fmt.Sprintf("%v", rows), Matches, ".*nth_value\\(test.t1.a, 2\\) from last ignore nulls.*")
+}
+
+func (s *testSuite7) TestAggregateWindowFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, v varchar(10), k varchar(10))")
+	tk.MustExec("insert into t values (1,'a','x'),(2,null,'y'),(3,'c','x'),(4,'d','z'),(5,'e','y')")
+	defer func() {
+		tk.MustExec("set @@tidb_enable_pipelined_window_function = 1")
+		tk.MustExec("set @@group_concat_max_len = default")
+	}()
+	for _, pipelined := range []int{0, 1} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %d", pipelined))
+		for _, chunkSize := range []int{1, 32} {
+			tk.Se.GetSessionVars().MaxChunkSize = chunkSize
+			tk.MustExec("set @@group_concat_max_len = default")
+
+			tk.MustQuery("select id, group_concat(v) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows("1 a", "2 a,c", "3 c,d", "4 c,d,e", "5 d,e"))
+			tk.MustQuery("select id, group_concat(v separator '-') over(order by id rows between 2 following and 3 following) from t").Check(
+				testkit.Rows("1 c-d", "2 d-e", "3 e", "4 <nil>", "5 <nil>"))
+			tk.MustQuery("select id, group_concat(v, k) over(order by id rows between current row and 1 following) from t").Check(
+				testkit.Rows("1 ax", "2 cx", "3 cx,dz", "4 dz,ey", "5 ey"))
+			tk.MustQuery("select id, group_concat(distinct k) over(order by id rows between unbounded preceding and current row) from t").Check(
+				testkit.Rows("1 x", "2 x,y", "3 x,y", "4 x,y,z", "5 x,y,z"))
+			tk.MustQuery("select id, group_concat(v) over(order by id range between 1 preceding and current row) from t").Check(
+				testkit.Rows("1 a", "2 a", "3 c", "4 c,d", "5 d,e"))
+			tk.MustQuery("select id, group_concat(v) over(order by k, id groups between current row and current row) from t").Sort().Check(
+				testkit.Rows("1 a", "2 <nil>", "3 c", "4 d", "5 e"))
+			tk.MustQuery("select k, group_concat(v) over(partition by k) from t where k <> 'y'").Sort().Check(
+				testkit.Rows("x a,c", "x a,c", "z d"))
+			tk.MustQuery("select id, group_concat(v order by v desc) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows("1 a", "2 c,a", "3 d,c", "4 e,d,c", "5 e,d"))
+			tk.MustQuery("select id, group_concat(k, id order by 1, id desc) over() from t").Check(
+				testkit.Rows("1 x3,x1,y5,y2,z4", "2 x3,x1,y5,y2,z4", "3 x3,x1,y5,y2,z4", "4 x3,x1,y5,y2,z4", "5 x3,x1,y5,y2,z4"))
+			tk.MustQuery("select id, group_concat(distinct k order by k desc) over(order by id rows between unbounded preceding and current row) from t").Check(
+				testkit.Rows("1 x", "2 y,x", "3 y,x", "4 z,y,x", "5 z,y,x"))
+
+			tk.MustExec("set @@group_concat_max_len = 4")
+			tk.MustQuery("select id, group_concat(v) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows("1 a", "2 a,c", "3 c,d", "4 c,d,", "5 d,e"))
+			tk.MustQuery("select id, group_concat(v order by v desc) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows("1 a", "2 c,a", "3 d,c", "4 e,d,", "5 e,d"))
+
+			tk.MustQuery("select id, json_objectagg(k, id) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows(`1 {"x": 1, "y": 2}`, `2 {"x": 3, "y": 2}`, `3 {"x": 3, "y": 2, "z": 4}`, `4 {"x": 3, "y": 5, "z": 4}`, `5 {"y": 5, "z": 4}`))
+			tk.MustQuery("select id, json_objectagg(k, v) over(order by id rows between 3 following and unbounded following) from t").Check(
+				testkit.Rows(`1 {"y": "e", "z": "d"}`, `2 {"y": "e"}`, "3 <nil>", "4 <nil>", "5 <nil>"))
+			tk.MustQuery("select id, json_objectagg(k, v) over() from t").Check(
+				testkit.Rows(`1 {"x": "c", "y": "e", "z": "d"}`, `2 {"x": "c", "y": "e", "z": "d"}`, `3 {"x": "c", "y": "e", "z": "d"}`, `4 {"x": "c", "y": "e", "z": "d"}`, `5 {"x": "c", "y": "e", "z": "d"}`))
+
+			tk.MustQuery("select id, approx_percentile(id, 50) over(order by id rows between 1 preceding and 1 following) from t").Check(
+				testkit.Rows("1 1", "2 2", "3 3", "4 4", "5 4"))
+			tk.MustQuery("select id, approx_percentile(id, 100) over(order by id rows between unbounded preceding and current row) from t").Check(
+				testkit.Rows("1 1", "2 2", "3 3", "4 4", "5 5"))
+			tk.MustQuery("select k, approx_percentile(id, 50) over(partition by k) from t").Sort().Check(
+				testkit.Rows("x 1", "x 1", "y 2", "y 2", "z 4"))
+		}
+	}
+
+}
+
+func (s *testSuite7) TestPipelinedWindowSpillActionInApply(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1), (2), (3), (4), (5)")
+	tk.MustExec("insert into t2 values (1, 1), (1, 2), (2, 3), (3, 4), (4, 5), (5, 6)")
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = 1")
+	defer tk.MustExec("set @@tidb_enable_pipelined_window_function = default")
+
+	countSpillActions := func() (cnt int) {
+		for action := tk.Se.GetSessionVars().StmtCtx.MemTracker.GetFallbackForTest(); action != nil; action = action.GetFallback() {
+			if action.GetPriority() == memory.DefSpillPriority {
+				cnt++
+			}
+		}
+		return cnt
+	}
+	// The window is the inner side of the Apply, so it is reopened for every outer row,
+	// but its spill action is registered only once.
+	sql := "select a, (select max(s) from (select sum(b) over (rows between 1 preceding and current row) s from t2 where t2.a = t1.a) w) from t1 where a <= %v order by a"
+	tk.MustQuery(fmt.Sprintf(sql, 1)).Check(testkit.Rows("1 3"))
+	expected := countSpillActions()
+	c.Assert(expected, Greater, 0)
+	tk.MustQuery(fmt.Sprintf(sql, 5)).Check(testkit.Rows("1 3", "2 3", "3 4", "4 5", "5 6"))
+	c.Assert(countSpillActions(), Equals, expected)
+}
+
 func (s *testSuite7) TestIssue24264(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -470,3 +626,61 @@ func (s *testSuite7) TestIssue24264(c *C) {
 		"8297270320597030697",
 		"<nil>"))
 }
+
+func (s *testSerialSuite) TestWindowInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill"), IsNil)
+	}()
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testSortedRowContainerSpill"), IsNil)
+	}()
+
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key idx(b, a))")
+	var buf strings.Builder
+	buf.WriteString("insert into t values (0, 0)")
+	for i := 1; i < 3000; i++ {
+		buf.WriteString(fmt.Sprintf(",(%v, %v)", i, i%3))
+	}
+	tk.MustExec(buf.String())
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	tk.MustExec("set @@tidb_enable_rate_limit_action = 0")
+	defer tk.MustExec("set @@tidb_enable_rate_limit_action = default")
+	sqls := []string{
+		"select a, b, sum(a) over (partition by b) from t",
+		"select a, b, count(a) over (partition by b order by a rows between 2 preceding and unbounded following) from t",
+		"select a, b, max(a) over (partition by b order by a rows between 5 following and unbounded following) from t",
+		"select a, b, sum(a) over (partition by b order by a range between 10 preceding and unbounded following) from t",
+		"select a, b, first_value(a) over (partition by b order by a desc range between current row and unbounded following) from t",
+		"select a, b, rank() over (partition by b order by a), lead(a, 3) over (partition by b order by a) from t",
+		"select a, b, sum(a) over (partition by b order by a rows between 3 preceding and 1 following) from t",
+		"select a, b, min(a) over (partition by b order by a range between 20 preceding and current row) from t",
+	}
+	for _, pipelined := range []int{0, 1} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %v", pipelined))
+		tk.MustExec("set @@tidb_mem_quota_query = default")
+		expected := make([][][]interface{}, 0, len(sqls))
+		for _, sql := range sqls {
+			expected = append(expected, tk.MustQuery(sql).Sort().Rows())
+		}
+
+		tk.MustExec("set @@tidb_mem_quota_query = 1")
+		for i, sql := range sqls {
+			comment := Commentf("sql: %v, pipelined: %v", sql, pipelined)
+			tk.MustQuery(sql).Sort().Check(expected[i])
+			c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.BytesConsumed(), Equals, int64(0), comment)
+			c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0), comment)
+			c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0), comment)
+		}
+	}
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = default")
+}
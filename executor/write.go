@@ -170,7 +170,41 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 	}
 
-	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
+	// 5. Check the new row against the enforced check constraints.
+	if err = table.CheckRowConstraint(sctx, t, newData); err != nil {
+		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`, the row is left unchanged with a warning.
+		if sc.DupKeyAsWarning && table.ErrCheckConstraintViolated.Equal(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// 6. Check the parent rows referred by the modified foreign key columns exist.
+	if err = checkFKParentsExist(ctx, sctx, t, newData, modified); err != nil {
+		if sc.DupKeyAsWarning && isForeignKeyError(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`, the update rejected by the foreign keys
+	// referring to the row is discarded, so it's written into a staging buffer.
+	var (
+		memBuffer kv.MemBuffer
+		fkStaging kv.StagingHandle
+	)
+	if sc.DupKeyAsWarning && isReferredByFK(sctx, t) {
+		if txn, err = sctx.Txn(true); err != nil {
+			return false, err
+		}
+		memBuffer = txn.GetMemBuffer()
+		fkStaging = memBuffer.Staging()
+		defer memBuffer.Cleanup(fkStaging)
+	}
+
+	// 7. If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
 		// we use the staging buffer so that we don't need to precheck the existence of handle or unique keys by sending
@@ -210,6 +244,24 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 
 	}
+
+	// 8. Apply the ON UPDATE actions of the foreign keys which refer to the modified columns.
+	if isReferredByFK(sctx, t) {
+		physicalID, err := fkPhysicalID(sctx, t, oldData)
+		if err != nil {
+			return false, err
+		}
+		if err = onFKParentRowUpdated(ctx, sctx, t, physicalID, h, oldData, newData, modified, 0); err != nil {
+			if sc.DupKeyAsWarning && isForeignKeyError(err) {
+				sc.AppendWarning(err)
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	if memBuffer != nil {
+		memBuffer.Release(fkStaging)
+	}
 	if onDup {
 		sc.AddAffectedRows(2)
 	} else {
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
@@ -102,3 +103,13 @@ func (s *testEvaluatorSuite) TestAggFunc2Pb(c *C) {
 		}
 	}
 }
+
+func (s *testEvaluatorSuite) TestJSONArrayaggNotPushedDown(c *C) {
+	dg := new(dataGen4Expr2PbTest)
+	args := []expression.Expression{dg.genColumn(mysql.TypeLonglong, 1)}
+	aggFunc, err := NewAggFuncDesc(s.ctx, ast.AggFuncJsonArrayagg, args, false)
+	c.Assert(err, IsNil)
+	for _, storeType := range []kv.StoreType{kv.TiKV, kv.TiFlash} {
+		c.Assert(CheckAggPushDown(aggFunc, storeType), IsFalse)
+	}
+}
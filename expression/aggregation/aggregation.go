@@ -194,7 +194,8 @@ func CheckAggPushDown(aggFunc *AggFuncDesc, storeType kv.StoreType) bool {
 	if len(aggFunc.OrderByItems) > 0 {
 		return false
 	}
-	if aggFunc.Name == ast.AggFuncApproxPercentile {
+	switch aggFunc.Name {
+	case ast.AggFuncApproxPercentile, ast.AggFuncJsonArrayagg:
 		return false
 	}
 	ret := true
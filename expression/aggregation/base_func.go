@@ -115,7 +115,7 @@ func (a *baseFuncDesc) typeInfer(ctx sessionctx.Context) error {
 		a.typeInfer4LeadLag(ctx)
 	case ast.AggFuncVarPop, ast.AggFuncStddevPop, ast.AggFuncVarSamp, ast.AggFuncStddevSamp:
 		a.typeInfer4PopOrSamp(ctx)
-	case ast.AggFuncJsonObjectAgg:
+	case ast.AggFuncJsonObjectAgg, ast.AggFuncJsonArrayagg:
 		a.typeInfer4JsonFuncs(ctx)
 	default:
 		return errors.Errorf("unsupported agg function: %s", a.Name)
@@ -415,6 +415,10 @@ func (a *baseFuncDesc) WrapCastForAggArgs(ctx sessionctx.Context) {
 			continue
 		}
 		a.Args[i] = castFunc(ctx, a.Args[i])
+		if a.Name == ast.AggFuncJsonArrayagg {
+			// Like JSON_ARRAY, the strings are collected as JSON strings instead of being parsed.
+			expression.DisableParseJSONFlag4Expr(a.Args[i])
+		}
 		if a.Name != ast.AggFuncAvg && a.Name != ast.AggFuncSum {
 			continue
 		}
@@ -14,16 +14,27 @@
 package aggregation
 
 import (
+	"bytes"
 	"strings"
 
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
 )
 
 // WindowFuncDesc describes a window function signature, only used in planner.
 type WindowFuncDesc struct {
 	baseFuncDesc
+	// HasDistinct indicates whether the aggregate window function is called with DISTINCT.
+	HasDistinct bool
+	// IgnoreNull indicates whether NULL values are skipped, it is only valid for
+	// FIRST_VALUE, LAST_VALUE, NTH_VALUE, LEAD and LAG.
+	IgnoreNull bool
+	// FromLast indicates whether NTH_VALUE counts the rows from the last row of the frame.
+	FromLast bool
+	// OrderByItems represents the order by clause used in GROUP_CONCAT.
+	OrderByItems []*util.ByItems
 }
 
 // NewWindowFuncDesc creates a window function signature descriptor.
@@ -54,7 +65,30 @@ func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Ex
 	if err != nil {
 		return nil, err
 	}
-	return &WindowFuncDesc{base}, nil
+	return &WindowFuncDesc{baseFuncDesc: base}, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (a *WindowFuncDesc) String() string {
+	buffer := bytes.NewBufferString(a.Name)
+	buffer.WriteString("(")
+	if a.HasDistinct {
+		buffer.WriteString("distinct ")
+	}
+	for i, arg := range a.Args {
+		buffer.WriteString(arg.String())
+		if i+1 != len(a.Args) {
+			buffer.WriteString(", ")
+		}
+	}
+	buffer.WriteString(")")
+	if a.FromLast {
+		buffer.WriteString(" from last")
+	}
+	if a.IgnoreNull {
+		buffer.WriteString(" ignore nulls")
+	}
+	return buffer.String()
 }
 
 // noFrameWindowFuncs is the functions that operate on the entire partition,
@@ -764,6 +764,9 @@ var funcs = map[string]functionClass{
 	ast.SessionUser:  &userFunctionClass{baseFunctionClass{ast.SessionUser, 0, 0}},
 	ast.SystemUser:   &userFunctionClass{baseFunctionClass{ast.SystemUser, 0, 0}},
 
+	// See https://dev.mysql.com/doc/refman/8.0/en/query-attribute-udfs.html
+	mysqlQueryAttributeString: &queryAttributeStringFunctionClass{baseFunctionClass{mysqlQueryAttributeString, 1, 1}},
+
 	// See https://dev.mysql.com/doc/refman/8.0/en/performance-schema-functions.html
 	ast.FormatBytes:    &formatBytesFunctionClass{baseFunctionClass{ast.FormatBytes, 1, 1}},
 	ast.FormatNanoTime: &formatNanoTimeFunctionClass{baseFunctionClass{ast.FormatNanoTime, 1, 1}},
@@ -830,6 +833,10 @@ var funcs = map[string]functionClass{
 	ast.IsFalsity:          &isTrueOrFalseFunctionClass{baseFunctionClass{ast.IsFalsity, 1, 1}, opcode.IsFalsity, false},
 	ast.Like:               &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
 	ast.Regexp:             &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+	regexpLike:             &regexpLikeFunctionClass{baseFunctionClass{regexpLike, 2, 3}},
+	regexpSubstr:           &regexpSubstrFunctionClass{baseFunctionClass{regexpSubstr, 2, 5}},
+	regexpInStr:            &regexpInStrFunctionClass{baseFunctionClass{regexpInStr, 2, 6}},
+	regexpReplace:          &regexpReplaceFunctionClass{baseFunctionClass{regexpReplace, 3, 6}},
 	ast.Case:               &caseWhenFunctionClass{baseFunctionClass{ast.Case, 1, -1}},
 	ast.RowFunc:            &rowFunctionClass{baseFunctionClass{ast.RowFunc, 2, -1}},
 	ast.SetVar:             &setVarFunctionClass{baseFunctionClass{ast.SetVar, 2, 2}},
@@ -34,6 +34,9 @@ import (
 	"github.com/pingcap/tipb/go-tipb"
 )
 
+// mysqlQueryAttributeString is the name of the function which returns the value of a query attribute.
+const mysqlQueryAttributeString = "mysql_query_attribute_string"
+
 var (
 	_ functionClass = &databaseFunctionClass{}
 	_ functionClass = &foundRowsFunctionClass{}
@@ -57,6 +60,7 @@ var (
 	_ functionClass = &setValFunctionClass{}
 	_ functionClass = &formatBytesFunctionClass{}
 	_ functionClass = &formatNanoTimeFunctionClass{}
+	_ functionClass = &queryAttributeStringFunctionClass{}
 )
 
 var (
@@ -73,6 +77,7 @@ var (
 	_ builtinFunc = &builtinTiDBDecodeKeySig{}
 	_ builtinFunc = &builtinNextValSig{}
 	_ builtinFunc = &builtinLastValSig{}
+	_ builtinFunc = &builtinQueryAttributeStringSig{}
 	_ builtinFunc = &builtinSetValSig{}
 	_ builtinFunc = &builtinFormatBytesSig{}
 	_ builtinFunc = &builtinFormatNanoTimeSig{}
@@ -1050,3 +1055,41 @@ func (b *builtinFormatNanoTimeSig) evalString(row chunk.Row) (string, bool, erro
 	}
 	return GetFormatNanoTime(val), false, nil
 }
+
+type queryAttributeStringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *queryAttributeStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	sig := &builtinQueryAttributeStringSig{bf}
+	return sig, nil
+}
+
+type builtinQueryAttributeStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinQueryAttributeStringSig) Clone() builtinFunc {
+	newSig := &builtinQueryAttributeStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinQueryAttributeStringSig, it returns NULL if the attribute is not sent by the client.
+// See https://dev.mysql.com/doc/refman/8.0/en/query-attribute-udfs.html#udf_mysql-query-attribute-string
+func (b *builtinQueryAttributeStringSig) evalString(row chunk.Row) (string, bool, error) {
+	name, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	val, ok := b.ctx.GetSessionVars().QueryAttributes[name]
+	return val, !ok, nil
+}
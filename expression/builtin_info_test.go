@@ -343,3 +343,35 @@ func (s *testEvaluatorSuite) TestFormatNanoTime(c *C) {
 		c.Assert(v, testutil.DatumEquals, t["Ret"][0])
 	}
 }
+
+func (s *testEvaluatorSuite) TestQueryAttributeString(c *C) {
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().QueryAttributes = map[string]string{"trace_id": "abc", "empty": ""}
+	tbl := []struct {
+		Arg interface{}
+		Ret interface{}
+	}{
+		{nil, nil},
+		{"trace_id", "abc"},
+		{"empty", ""},
+		{"TRACE_ID", nil},
+		{"span", nil},
+	}
+	Dtbl := tblToDtbl(tbl)
+
+	for _, t := range Dtbl {
+		fc := funcs[mysqlQueryAttributeString]
+		f, err := fc.getFunction(ctx, s.datumsToConstants(t["Arg"]))
+		c.Assert(err, IsNil)
+		v, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(v, testutil.DatumEquals, t["Ret"][0])
+	}
+
+	ctx.GetSessionVars().QueryAttributes = nil
+	f, err := funcs[mysqlQueryAttributeString].getFunction(ctx, s.datumsToConstants(types.MakeDatums("trace_id")))
+	c.Assert(err, IsNil)
+	v, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.IsNull(), IsTrue)
+}
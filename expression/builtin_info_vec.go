@@ -354,3 +354,33 @@ func (b *builtinTiDBDecodeKeySig) vecEvalString(input *chunk.Chunk, result *chun
 	}
 	return nil
 }
+
+func (b *builtinQueryAttributeStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinQueryAttributeStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	attrs := b.ctx.GetSessionVars().QueryAttributes
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if val, ok := attrs[buf.GetString(i)]; ok {
+			result.AppendString(val)
+		} else {
+			result.AppendNull()
+		}
+	}
+	return nil
+}
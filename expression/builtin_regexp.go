@@ -0,0 +1,614 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// The names of the MySQL 8.0 regexp functions, the parser doesn't define them
+// because they are parsed as normal function calls.
+const (
+	regexpLike    = "regexp_like"
+	regexpSubstr  = "regexp_substr"
+	regexpInStr   = "regexp_instr"
+	regexpReplace = "regexp_replace"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeFuncSig{}
+	_ builtinFunc = &builtinRegexpSubstrFuncSig{}
+	_ builtinFunc = &builtinRegexpInStrFuncSig{}
+	_ builtinFunc = &builtinRegexpReplaceFuncSig{}
+)
+
+// regexpArg indicates the meaning of an argument of the regexp functions.
+type regexpArg int
+
+const (
+	regexpArgExpr regexpArg = iota
+	regexpArgPattern
+	regexpArgRepl
+	regexpArgPos
+	regexpArgOccurrence
+	regexpArgReturnOption
+	regexpArgMatchType
+)
+
+func (a regexpArg) evalType() types.EvalType {
+	switch a {
+	case regexpArgPos, regexpArgOccurrence, regexpArgReturnOption:
+		return types.ETInt
+	}
+	return types.ETString
+}
+
+var (
+	// regexp_like(expr, pat[, match_type])
+	regexpLikeArgs = []regexpArg{regexpArgExpr, regexpArgPattern, regexpArgMatchType}
+	// regexp_substr(expr, pat[, pos[, occurrence[, match_type]]])
+	regexpSubstrArgs = []regexpArg{regexpArgExpr, regexpArgPattern, regexpArgPos, regexpArgOccurrence, regexpArgMatchType}
+	// regexp_instr(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])
+	regexpInStrArgs = []regexpArg{regexpArgExpr, regexpArgPattern, regexpArgPos, regexpArgOccurrence, regexpArgReturnOption, regexpArgMatchType}
+	// regexp_replace(expr, pat, repl[, pos[, occurrence[, match_type]]])
+	regexpReplaceArgs = []regexpArg{regexpArgExpr, regexpArgPattern, regexpArgRepl, regexpArgPos, regexpArgOccurrence, regexpArgMatchType}
+)
+
+func regexpArgTypes(argKinds []regexpArg, argNum int) []types.EvalType {
+	argTps := make([]types.EvalType, 0, argNum)
+	for _, kind := range argKinds[:argNum] {
+		argTps = append(argTps, kind.evalType())
+	}
+	return argTps
+}
+
+// regexpParams holds the evaluated arguments of a regexp function.
+type regexpParams struct {
+	expr         string
+	pattern      string
+	repl         string
+	matchType    string
+	pos          int64
+	occurrence   int64
+	returnOption int64
+}
+
+func (p *regexpParams) reset(defaultOccurrence int64) {
+	*p = regexpParams{pos: 1, occurrence: defaultOccurrence}
+}
+
+func (p *regexpParams) setString(kind regexpArg, val string) {
+	switch kind {
+	case regexpArgExpr:
+		p.expr = val
+	case regexpArgPattern:
+		p.pattern = val
+	case regexpArgRepl:
+		p.repl = val
+	case regexpArgMatchType:
+		p.matchType = val
+	}
+}
+
+func (p *regexpParams) setInt(kind regexpArg, val int64) {
+	switch kind {
+	case regexpArgPos:
+		p.pos = val
+	case regexpArgOccurrence:
+		p.occurrence = val
+	case regexpArgReturnOption:
+		p.returnOption = val
+	}
+}
+
+// regexpBaseFuncSig is the shared part of the regexp functions.
+type regexpBaseFuncSig struct {
+	baseBuiltinFunc
+	argKinds []regexpArg
+	// defaultOccurrence is used when the occurrence argument is omitted.
+	defaultOccurrence int64
+	// isBinary indicates the regexp is matched byte-wise, and the positions are counted in bytes
+	// rather than characters.
+	isBinary bool
+
+	// memorizedRegexp and memorizedErr are not serialized with the function, they are used as a cache
+	// of the compiled regexp when both the pattern and the match type are constant.
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+	isMemorized     bool
+	once            sync.Once
+}
+
+func newRegexpBaseFuncSig(bf baseBuiltinFunc, argKinds []regexpArg, defaultOccurrence int64) regexpBaseFuncSig {
+	return regexpBaseFuncSig{
+		baseBuiltinFunc:   bf,
+		argKinds:          argKinds[:len(bf.args)],
+		defaultOccurrence: defaultOccurrence,
+		isBinary:          bf.collation == charset.CollationBin,
+	}
+}
+
+func (b *regexpBaseFuncSig) cloneFromRegexpBase(from *regexpBaseFuncSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.argKinds = from.argKinds
+	b.defaultOccurrence = from.defaultOccurrence
+	b.isBinary = from.isBinary
+}
+
+// evalParams evaluates the arguments, isNull is true if any of them is null.
+func (b *regexpBaseFuncSig) evalParams(row chunk.Row, params *regexpParams) (isNull bool, err error) {
+	params.reset(b.defaultOccurrence)
+	for i, arg := range b.args {
+		kind := b.argKinds[i]
+		if kind.evalType() == types.ETInt {
+			val, isNull, err := arg.EvalInt(b.ctx, row)
+			if isNull || err != nil {
+				return true, err
+			}
+			params.setInt(kind, val)
+			continue
+		}
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return true, err
+		}
+		params.setString(kind, val)
+	}
+	return false, nil
+}
+
+// compile compiles the pattern with the flags specified by the collation and the match type.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *regexpBaseFuncSig) compile(pattern, matchType string) (*regexp.Regexp, error) {
+	if len(pattern) == 0 {
+		return nil, ErrRegexpIllegalArgument.GenWithStackByArgs()
+	}
+	ci := !b.isBinary && collate.IsCICollation(b.collation)
+	multiLine, dotAll := false, false
+	for _, flag := range matchType {
+		switch flag {
+		case 'c':
+			ci = false
+		case 'i':
+			ci = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Only the '\n' line ending is recognized, which is the default behavior.
+		default:
+			return nil, ErrRegexpInvalidFlag.GenWithStackByArgs()
+		}
+	}
+	var flags strings.Builder
+	if ci {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	if flags.Len() > 0 {
+		pattern = "(?" + flags.String() + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+func (b *regexpBaseFuncSig) canMemorize() bool {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i, arg := range b.args {
+		if kind := b.argKinds[i]; (kind == regexpArgPattern || kind == regexpArgMatchType) && !arg.ConstItem(sc) {
+			return false
+		}
+	}
+	return true
+}
+
+// getRegexp returns the compiled regexp, it is only compiled once if the pattern and the match type are constant.
+func (b *regexpBaseFuncSig) getRegexp(pattern, matchType string) (*regexp.Regexp, error) {
+	// Only be executed once to achieve thread-safe
+	b.once.Do(func() {
+		if b.canMemorize() {
+			b.memorizedRegexp, b.memorizedErr = b.compile(pattern, matchType)
+			b.isMemorized = true
+		}
+	})
+	if b.isMemorized {
+		return b.memorizedRegexp, b.memorizedErr
+	}
+	return b.compile(pattern, matchType)
+}
+
+// byteOffset converts the 1-based position pos of expr to the byte offset.
+func (b *regexpBaseFuncSig) byteOffset(expr string, pos int64) (int, error) {
+	if pos < 1 {
+		return 0, ErrRegexpIndexOutOfBounds.GenWithStackByArgs()
+	}
+	if b.isBinary {
+		if pos > int64(len(expr))+1 {
+			return 0, ErrRegexpIndexOutOfBounds.GenWithStackByArgs()
+		}
+		return int(pos - 1), nil
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(expr) {
+			return 0, ErrRegexpIndexOutOfBounds.GenWithStackByArgs()
+		}
+		_, size := utf8.DecodeRuneInString(expr[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+// position converts the byte offset of expr to the 1-based position.
+func (b *regexpBaseFuncSig) position(expr string, offset int) int64 {
+	if b.isBinary {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+// findMatch finds the occurrence-th match of re in expr starting from pos.
+// It returns the byte indexes of the match and its submatches, or nil if there is no such match.
+func (b *regexpBaseFuncSig) findMatch(re *regexp.Regexp, expr string, pos, occurrence int64) ([]int, error) {
+	offset, err := b.byteOffset(expr, pos)
+	if err != nil {
+		return nil, err
+	}
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	locs := re.FindAllStringSubmatchIndex(expr[offset:], int(occurrence))
+	if int64(len(locs)) < occurrence {
+		return nil, nil
+	}
+	loc := locs[occurrence-1]
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += offset
+		}
+	}
+	return loc, nil
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, regexpArgTypes(regexpLikeArgs, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := newBuiltinRegexpLikeFuncSig(bf)
+	if sig.isBinary {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpLikeFuncSig(bf baseBuiltinFunc) *builtinRegexpLikeFuncSig {
+	return &builtinRegexpLikeFuncSig{newRegexpBaseFuncSig(bf, regexpLikeArgs, 1)}
+}
+
+func (b *builtinRegexpLikeFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeFuncSig{}
+	newSig.cloneFromRegexpBase(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+func (b *builtinRegexpLikeFuncSig) evalRegexpLike(params *regexpParams) (int64, error) {
+	re, err := b.getRegexp(params.pattern, params.matchType)
+	if err != nil {
+		return 0, err
+	}
+	return boolToInt64(re.MatchString(params.expr)), nil
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	var params regexpParams
+	if isNull, err := b.evalParams(row, &params); isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.evalRegexpLike(&params)
+	return res, err != nil, err
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, regexpArgTypes(regexpSubstrArgs, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := newBuiltinRegexpSubstrFuncSig(bf)
+	if sig.isBinary {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpSubstrFuncSig(bf baseBuiltinFunc) *builtinRegexpSubstrFuncSig {
+	return &builtinRegexpSubstrFuncSig{newRegexpBaseFuncSig(bf, regexpSubstrArgs, 1)}
+}
+
+func (b *builtinRegexpSubstrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrFuncSig{}
+	newSig.cloneFromRegexpBase(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+func (b *builtinRegexpSubstrFuncSig) evalRegexpSubstr(params *regexpParams) (string, bool, error) {
+	re, err := b.getRegexp(params.pattern, params.matchType)
+	if err != nil {
+		return "", true, err
+	}
+	loc, err := b.findMatch(re, params.expr, params.pos, params.occurrence)
+	if loc == nil || err != nil {
+		return "", true, err
+	}
+	return params.expr[loc[0]:loc[1]], false, nil
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	var params regexpParams
+	if isNull, err := b.evalParams(row, &params); isNull || err != nil {
+		return "", true, err
+	}
+	return b.evalRegexpSubstr(&params)
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, regexpArgTypes(regexpInStrArgs, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxIntWidth
+	sig := newBuiltinRegexpInStrFuncSig(bf)
+	if sig.isBinary {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpInStrFuncSig(bf baseBuiltinFunc) *builtinRegexpInStrFuncSig {
+	return &builtinRegexpInStrFuncSig{newRegexpBaseFuncSig(bf, regexpInStrArgs, 1)}
+}
+
+func (b *builtinRegexpInStrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrFuncSig{}
+	newSig.cloneFromRegexpBase(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+func (b *builtinRegexpInStrFuncSig) evalRegexpInStr(params *regexpParams) (int64, error) {
+	if params.returnOption != 0 && params.returnOption != 1 {
+		return 0, errIncorrectArgs.GenWithStackByArgs("regexp_instr: return_option must be 1 or 0.")
+	}
+	re, err := b.getRegexp(params.pattern, params.matchType)
+	if err != nil {
+		return 0, err
+	}
+	loc, err := b.findMatch(re, params.expr, params.pos, params.occurrence)
+	if loc == nil || err != nil {
+		return 0, err
+	}
+	if params.returnOption == 1 {
+		return b.position(params.expr, loc[1]), nil
+	}
+	return b.position(params.expr, loc[0]), nil
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	var params regexpParams
+	if isNull, err := b.evalParams(row, &params); isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.evalRegexpInStr(&params)
+	return res, err != nil, err
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, regexpArgTypes(regexpReplaceArgs, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := newBuiltinRegexpReplaceFuncSig(bf)
+	if sig.isBinary {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpReplaceFuncSig(bf baseBuiltinFunc) *builtinRegexpReplaceFuncSig {
+	// All the matches are replaced if the occurrence is omitted.
+	return &builtinRegexpReplaceFuncSig{newRegexpBaseFuncSig(bf, regexpReplaceArgs, 0)}
+}
+
+func (b *builtinRegexpReplaceFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceFuncSig{}
+	newSig.cloneFromRegexpBase(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+func (b *builtinRegexpReplaceFuncSig) evalRegexpReplace(params *regexpParams) (string, error) {
+	re, err := b.getRegexp(params.pattern, params.matchType)
+	if err != nil {
+		return "", err
+	}
+	offset, err := b.byteOffset(params.expr, params.pos)
+	if err != nil {
+		return "", err
+	}
+	prefix, str := params.expr[:offset], params.expr[offset:]
+	n := -1
+	if params.occurrence >= 1 {
+		n = int(params.occurrence)
+	}
+	locs := re.FindAllStringSubmatchIndex(str, n)
+	if params.occurrence >= 1 {
+		if int64(len(locs)) < params.occurrence {
+			return params.expr, nil
+		}
+		locs = locs[params.occurrence-1:]
+	}
+	res := make([]byte, 0, len(params.expr)+len(params.repl))
+	res = append(res, prefix...)
+	last := 0
+	for _, loc := range locs {
+		res = append(res, str[last:loc[0]]...)
+		if res, err = expandRegexpReplacement(res, params.repl, str, loc); err != nil {
+			return "", err
+		}
+		last = loc[1]
+	}
+	res = append(res, str[last:]...)
+	return string(res), nil
+}
+
+// expandRegexpReplacement appends the replacement of a match to dst. As in MySQL, `$n` and `\n` are replaced
+// by the text of the capture group n, the longest sequence of digits which is a valid group number is used.
+// A backslash quotes the next character, and a `$` which isn't followed by a digit is a literal.
+func expandRegexpReplacement(dst []byte, repl, src string, match []int) ([]byte, error) {
+	numGroups := len(match)/2 - 1
+	for i := 0; i < len(repl); i++ {
+		c := repl[i]
+		if (c != '$' && c != '\\') || i+1 == len(repl) {
+			dst = append(dst, c)
+			continue
+		}
+		next := repl[i+1]
+		if next < '0' || next > '9' {
+			if c == '\\' {
+				i++
+				dst = append(dst, next)
+			} else {
+				dst = append(dst, c)
+			}
+			continue
+		}
+		group := int(next - '0')
+		if group > numGroups {
+			return nil, ErrRegexpIndexOutOfBounds
+		}
+		i++
+		for i+1 < len(repl) && repl[i+1] >= '0' && repl[i+1] <= '9' {
+			g := group*10 + int(repl[i+1]-'0')
+			if g > numGroups {
+				break
+			}
+			group = g
+			i++
+		}
+		if start := match[2*group]; start >= 0 {
+			dst = append(dst, src[start:match[2*group+1]]...)
+		}
+	}
+	return dst, nil
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	var params regexpParams
+	if isNull, err := b.evalParams(row, &params); isNull || err != nil {
+		return "", true, err
+	}
+	res, err := b.evalRegexpReplace(&params)
+	return res, err != nil, err
+}
@@ -0,0 +1,192 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestRegexpLike(c *C) {
+	tests := []struct {
+		args  []interface{}
+		match interface{}
+		err   error
+	}{
+		{[]interface{}{"abc", "b"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "^a.c$"}, 1, nil},
+		{[]interface{}{"aBc", "abc"}, 0, nil},
+		{[]interface{}{"aBc", "abc", "i"}, 1, nil},
+		{[]interface{}{"aBc", "abc", "ic"}, 0, nil},
+		{[]interface{}{"aBc", "abc", "ci"}, 1, nil},
+		{[]interface{}{"a\nb", "^b", ""}, 0, nil},
+		{[]interface{}{"a\nb", "^b", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b", ""}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", ""}, nil, ErrRegexpIllegalArgument},
+		{[]interface{}{"a", "("}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, ErrRegexpInvalidFlag},
+	}
+	for _, tt := range tests {
+		commentf := Commentf("%v", tt.args)
+		f, err := funcs[regexpLike].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tt.args...)))
+		c.Assert(err, IsNil, commentf)
+		match, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, commentf)
+			continue
+		}
+		c.Assert(err, IsNil, commentf)
+		c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), commentf)
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpSubstr(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 5, 2}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 0}, "abc", nil},
+		{[]interface{}{"abc def ghi", "X", 1, 1}, nil, nil},
+		{[]interface{}{"abc DEF", "def", 1, 1, "i"}, "DEF", nil},
+		{[]interface{}{"你好世界", "世.", 2}, "世界", nil},
+		{[]interface{}{"abc", "c", 4}, nil, nil},
+		{[]interface{}{"abc", "c", 5}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "c", 0}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{nil, "c"}, nil, nil},
+	}
+	for _, tt := range tests {
+		commentf := Commentf("%v", tt.args)
+		f, err := funcs[regexpSubstr].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tt.args...)))
+		c.Assert(err, IsNil, commentf)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, commentf)
+			continue
+		}
+		c.Assert(err, IsNil, commentf)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), commentf)
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpInStr(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "c"}, 0, nil},
+		{[]interface{}{"你好世界", "世"}, 3, nil},
+		{[]interface{}{"你好世界", "世", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog", "dog", 1, 1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"dog", "dog", 5}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"dog", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		commentf := Commentf("%v", tt.args)
+		f, err := funcs[regexpInStr].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tt.args...)))
+		c.Assert(err, IsNil, commentf)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, commentf)
+			continue
+		}
+		c.Assert(err, IsNil, commentf)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), commentf)
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpReplace(c *C) {
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 2}, "abc X ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5}, "abc X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 3}, "abc def ghi", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "$2$1"}, "bca efd", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "$2$1", 1, 2}, "abc efd", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", `\2\1`}, "bca efd", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "$0-$2$10"}, "abc-bca0 def-efd0", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "${2}"}, "${2} ${2}", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", `\$2 \\ $ \x`}, "$2 \\ $ x $2 \\ $ x", nil},
+		{[]interface{}{"abc", "(b)", "$2"}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"ABC abc", "abc", "X", 1, 0, "i"}, "X X", nil},
+		{[]interface{}{"你好世界", "世界", "朋友", 2}, "你好朋友", nil},
+		{[]interface{}{"abc", "b", "X", 0}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		commentf := Commentf("%v", tt.args)
+		f, err := funcs[regexpReplace].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tt.args...)))
+		c.Assert(err, IsNil, commentf)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			c.Assert(terror.ErrorEqual(err, tt.err), IsTrue, commentf)
+			continue
+		}
+		c.Assert(err, IsNil, commentf)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.res), commentf)
+	}
+}
+
+func (s *testEvaluatorSerialSuites) TestCIRegexpLike(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tests := []struct {
+		args      []interface{}
+		collation string
+		match     int64
+	}{
+		{[]interface{}{"aBc", "abc"}, "utf8mb4_bin", 0},
+		{[]interface{}{"aBc", "abc"}, "utf8mb4_general_ci", 1},
+		{[]interface{}{"aBc", "abc", "c"}, "utf8mb4_general_ci", 0},
+		{[]interface{}{"aBc", "abc", "i"}, "utf8mb4_bin", 1},
+	}
+	for _, tt := range tests {
+		commentf := Commentf("%v %v", tt.args, tt.collation)
+		args := s.datumsToConstants(types.MakeDatums(tt.args...))
+		for _, arg := range args {
+			arg.GetType().Collate = tt.collation
+		}
+		f, err := funcs[regexpLike].getFunction(s.ctx, args)
+		c.Assert(err, IsNil, commentf)
+		match, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil, commentf)
+		c.Assert(match, testutil.DatumEquals, types.NewDatum(tt.match), commentf)
+	}
+}
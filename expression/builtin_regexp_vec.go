@@ -0,0 +1,143 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalParams evaluates the arguments of all the rows in input, then calls fn for every row.
+// isNull is true if any argument of the row is null, params is only valid when isNull is false.
+func (b *regexpBaseFuncSig) vecEvalParams(input *chunk.Chunk, fn func(i int, isNull bool, params *regexpParams) error) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for i, arg := range b.args {
+		evalType := b.argKinds[i].evalType()
+		buf, err := b.bufAllocator.get(evalType, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if evalType == types.ETInt {
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		} else {
+			err = arg.VecEvalString(b.ctx, input, buf)
+		}
+		if err != nil {
+			return err
+		}
+		bufs[i] = buf
+	}
+
+	var params regexpParams
+	for i := 0; i < n; i++ {
+		isNull := false
+		params.reset(b.defaultOccurrence)
+		for j, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			kind := b.argKinds[j]
+			if kind.evalType() == types.ETInt {
+				params.setInt(kind, buf.GetInt64(i))
+			} else {
+				params.setString(kind, buf.GetString(i))
+			}
+		}
+		if err := fn(i, isNull, &params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpLikeFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeInt64(input.NumRows(), false)
+	i64s := result.Int64s()
+	return b.vecEvalParams(input, func(i int, isNull bool, params *regexpParams) (err error) {
+		if isNull {
+			result.SetNull(i, true)
+			return nil
+		}
+		i64s[i], err = b.evalRegexpLike(params)
+		return err
+	})
+}
+
+func (b *builtinRegexpSubstrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	result.ReserveString(input.NumRows())
+	return b.vecEvalParams(input, func(i int, isNull bool, params *regexpParams) error {
+		if isNull {
+			result.AppendNull()
+			return nil
+		}
+		res, isNull, err := b.evalRegexpSubstr(params)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+		return nil
+	})
+}
+
+func (b *builtinRegexpInStrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeInt64(input.NumRows(), false)
+	i64s := result.Int64s()
+	return b.vecEvalParams(input, func(i int, isNull bool, params *regexpParams) (err error) {
+		if isNull {
+			result.SetNull(i, true)
+			return nil
+		}
+		i64s[i], err = b.evalRegexpInStr(params)
+		return err
+	})
+}
+
+func (b *builtinRegexpReplaceFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	result.ReserveString(input.NumRows())
+	return b.vecEvalParams(input, func(i int, isNull bool, params *regexpParams) error {
+		if isNull {
+			result.AppendNull()
+			return nil
+		}
+		res, err := b.evalRegexpReplace(params)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+		return nil
+	})
+}
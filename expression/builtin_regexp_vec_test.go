@@ -0,0 +1,60 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinRegexpCases = map[string][]vecExprBenchCase{
+	regexpLike: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"a", "^[a-m]", "[0-9]+$", "(?:ab|cd)"})},
+		},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners:    []dataGenerator{nil, nil, newSelectStringGener([]string{"", "c", "i", "mn"})},
+			constants: []*Constant{nil, {Value: types.NewDatum("[A-Z]+"), RetType: types.NewFieldType(mysql.TypeString)}, nil},
+		},
+	},
+	regexpSubstr: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"[a-z]", "[A-Z]+", "[0-9]{2}"}), newRangeInt64Gener(1, 2), newRangeInt64Gener(0, 3), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+	regexpInStr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"[a-z]", "[A-Z]+", "[0-9]{2}"}), newRangeInt64Gener(1, 2), newRangeInt64Gener(0, 3), newRangeInt64Gener(0, 2), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+	regexpReplace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"[a-z]", "([A-Z])([a-z]+)"}), newSelectStringGener([]string{"", "X", "${2}${1}"})},
+		},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{nil, newSelectStringGener([]string{"[a-z]", "[A-Z]+"}), nil, newRangeInt64Gener(1, 2), newRangeInt64Gener(0, 3), newSelectStringGener([]string{"", "i"})},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinRegexpFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinRegexpCases)
+}
+
+func BenchmarkVectorizedBuiltinRegexpFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinRegexpCases)
+}
@@ -652,6 +652,22 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 	// 	f = &builtinRegexpSig{base}
 	// case tipb.ScalarFuncSig_RegexpUTF8Sig:
 	// 	f = &builtinRegexpUTF8Sig{base}
+	case tipb.ScalarFuncSig_RegexpLikeSig, tipb.ScalarFuncSig_RegexpLikeUTF8Sig:
+		sig := newBuiltinRegexpLikeFuncSig(base)
+		sig.isBinary = sigCode == tipb.ScalarFuncSig_RegexpLikeSig
+		f = sig
+	case tipb.ScalarFuncSig_RegexpSubstrSig, tipb.ScalarFuncSig_RegexpSubstrUTF8Sig:
+		sig := newBuiltinRegexpSubstrFuncSig(base)
+		sig.isBinary = sigCode == tipb.ScalarFuncSig_RegexpSubstrSig
+		f = sig
+	case tipb.ScalarFuncSig_RegexpInStrSig, tipb.ScalarFuncSig_RegexpInStrUTF8Sig:
+		sig := newBuiltinRegexpInStrFuncSig(base)
+		sig.isBinary = sigCode == tipb.ScalarFuncSig_RegexpInStrSig
+		f = sig
+	case tipb.ScalarFuncSig_RegexpReplaceSig, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig:
+		sig := newBuiltinRegexpReplaceFuncSig(base)
+		sig.isBinary = sigCode == tipb.ScalarFuncSig_RegexpReplaceSig
+		f = sig
 	case tipb.ScalarFuncSig_JsonExtractSig:
 		f = &builtinJSONExtractSig{base}
 	case tipb.ScalarFuncSig_JsonUnquoteSig:
@@ -33,6 +33,9 @@ var (
 	ErrInvalidArgumentForLogarithm = dbterror.ClassExpression.NewStd(mysql.ErrInvalidArgumentForLogarithm)
 	ErrIncorrectType               = dbterror.ClassExpression.NewStd(mysql.ErrIncorrectType)
 	ErrInvalidTableSample          = dbterror.ClassExpression.NewStd(mysql.ErrInvalidTableSample)
+	ErrRegexpIllegalArgument       = dbterror.ClassExpression.NewStd(mysql.ErrRegexpIllegalArgument)
+	ErrRegexpIndexOutOfBounds      = dbterror.ClassExpression.NewStd(mysql.ErrRegexpIndexOutOfBounds)
+	ErrRegexpInvalidFlag           = dbterror.ClassExpression.NewStd(mysql.ErrRegexpInvalidFlag)
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists             = dbterror.ClassExpression.NewStd(mysql.ErrSpDoesNotExist)
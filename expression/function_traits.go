@@ -48,6 +48,8 @@ var unFoldableFunctions = map[string]struct{}{
 	ast.NextVal:   {},
 	ast.LastVal:   {},
 	ast.SetVal:    {},
+
+	mysqlQueryAttributeString: {},
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
@@ -116,6 +118,8 @@ var IllegalFunctions4GeneratedColumns = map[string]struct{}{
 	ast.SetVar:           {},
 	ast.GetVar:           {},
 	ast.ReleaseAllLocks:  {},
+
+	mysqlQueryAttributeString: {},
 }
 
 // DeferredFunctions stores functions which are foldable but should be deferred as well when plan cache is enabled.
@@ -252,6 +256,7 @@ var booleanFunctions = map[string]struct{}{
 	ast.IsNull:             {},
 	ast.Like:               {},
 	ast.Regexp:             {},
+	regexpLike:             {},
 	ast.IsIPv4:             {},
 	ast.IsIPv4Compat:       {},
 	ast.IsIPv4Mapped:       {},
@@ -194,6 +194,61 @@ func (s *testIntegrationSuite) TestFuncREPEAT(c *C) {
 	r.Check(testkit.Rows("<nil> <nil> <nil> <nil> <nil> <nil>"))
 }
 
+func (s *testIntegrationSuite) TestFuncRegexp(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a varchar(64), b varchar(64))")
+	tk.MustExec("insert into t values ('abc def ghi', '[a-z]+'), ('123 456', '[0-9]{3}'), (null, 'a'), ('a', null)")
+
+	tk.MustQuery("select regexp_like(a, b), regexp_substr(a, b, 1, 2), regexp_instr(a, b, 1, 2), regexp_replace(a, b, 'X') from t").Check(testkit.Rows(
+		"1 def 5 X X X",
+		"1 456 5 X X",
+		"<nil> <nil> <nil> <nil>",
+		"<nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select regexp_like('aBc', 'abc'), regexp_like('aBc', 'abc', 'i'), regexp_like(binary 'aBc', 'abc', 'i')").Check(testkit.Rows("0 1 1"))
+	tk.MustQuery("select regexp_instr('dog cat dog', 'dog', 1, 2, 1), regexp_replace('abc def', '([a-z])([a-z]+)', '$2$1', 1, 2)").Check(testkit.Rows("12 abc efd"))
+	tk.MustQuery("select regexp_replace('abc def', '([a-z])([a-z]+)', '\\\\2\\\\1'), regexp_replace('abc def', '([a-z]+)', '${1}'), regexp_replace('a$c', '\\\\$', '\\\\$1')").Check(testkit.Rows("bca efd ${1} ${1} a$1c"))
+	tk.MustQuery("select regexp_substr('你好世界', '世.'), regexp_instr('你好世界', '界')").Check(testkit.Rows("世界 4"))
+
+	// The functions are evaluated by TiDB, TiKV doesn't implement them yet.
+	sql := "select a from t where regexp_like(a, b) and regexp_instr(a, b, 1, 2) = 5 and regexp_substr(a, b) is not null and regexp_replace(a, b, 'X') != ''"
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("123 456", "abc def ghi"))
+	rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+	c.Assert(rows[1][0], Matches, ".*Selection.*")
+	c.Assert(rows[1][2], Equals, "root")
+
+	err := tk.QueryToErr("select regexp_like('a', 'a', 'x')")
+	c.Assert(expression.ErrRegexpInvalidFlag.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr("select regexp_substr('abc', 'a', 5)")
+	c.Assert(expression.ErrRegexpIndexOutOfBounds.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr("select regexp_like('abc', '')")
+	c.Assert(expression.ErrRegexpIllegalArgument.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr("select regexp_instr('abc', 'a', 1, 1, 2)")
+	c.Assert(err, ErrorMatches, ".*return_option must be 1 or 0.*")
+	tk.MustGetErrCode("select regexp_replace('abc', 'a')", mysql.ErrWrongParamcountToNativeFct)
+}
+
+func (s *testIntegrationSuite) TestQueryAttributeString(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustQuery("select mysql_query_attribute_string('trace_id')").Check(testkit.Rows("<nil>"))
+	tk.Se.GetSessionVars().QueryAttributes = map[string]string{"trace_id": "abc"}
+	tk.MustQuery("select mysql_query_attribute_string('trace_id'), mysql_query_attribute_string('span')").Check(testkit.Rows("abc <nil>"))
+
+	// The attributes are evaluated in each execution.
+	tk.MustExec("prepare stmt from 'select mysql_query_attribute_string(?)'")
+	tk.MustExec("set @a = 'trace_id'")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("abc"))
+	tk.Se.GetSessionVars().QueryAttributes = map[string]string{"trace_id": "def"}
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("def"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustGetErrCode("create table t(a varchar(64) as (mysql_query_attribute_string('trace_id')))", mysql.ErrGeneratedColumnFunctionIsNotAllowed)
+}
+
 func (s *testIntegrationSuite) TestFuncLpadAndRpad(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	defer s.cleanEnv(c)
@@ -5108,7 +5163,10 @@ func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
 	tk.MustExec("SET FOREIGN_KEY_CHECKS=1")
-	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8047 variable 'foreign_key_checks' does not yet support value: 1"))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows())
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("1"))
+	tk.MustExec("SET FOREIGN_KEY_CHECKS=0")
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("0"))
 }
 
 func (s *testIntegrationSuite) TestUserVarMockWindFunc(c *C) {
@@ -9501,13 +9559,13 @@ func (s *testIntegrationSuite) TestCTEInvalidUsage(c *C) {
 	tk.MustGetErrCode("with recursive cte(n) as (select 1 union select sum(n) from cte group by n) select * from cte;", errno.ErrCTERecursiveForbidsAggregation)
 	// Window function is not allowed in the recursive part.
 	tk.MustGetErrCode("with recursive cte(n) as (select 1 union select row_number() over(partition by n) from cte ) select * from cte;", errno.ErrCTERecursiveForbidsAggregation)
-	// Group by is not allowed in the recursive part.
-	tk.MustGetErrCode("with recursive cte(n) as (select 1 union (select * from cte order by n)) select * from cte;", errno.ErrNotSupportedYet)
-	tk.MustGetErrCode("with recursive cte(n) as (select 1 union (select * from cte order by n)) select * from cte;", errno.ErrNotSupportedYet)
-	// Distinct is not allowed in the recursive part.
-	tk.MustGetErrCode("with recursive cte(n) as (select 1 union select distinct  * from cte) select * from cte;", errno.ErrNotSupportedYet)
-	// Limit is not allowed in the recursive part.
-	tk.MustGetErrCode("with recursive cte(n) as (select 1 union (select * from cte limit 2)) select * from cte;", errno.ErrNotSupportedYet)
+	// Order by, distinct and limit are allowed in the recursive part.
+	tk.MustQuery("with recursive cte(n) as (select 1 union (select * from cte order by n)) select * from cte;").Check(testkit.Rows("1"))
+	tk.MustQuery("with recursive cte(n) as (select 1 union select distinct  * from cte) select * from cte;").Check(testkit.Rows("1"))
+	tk.MustQuery("with recursive cte(n) as (select 1 union (select * from cte limit 2)) select * from cte;").Check(testkit.Rows("1"))
+	tk.MustQuery("with recursive cte(n) as (select 1 union (select * from cte order by n limit 2)) select * from cte;").Check(testkit.Rows("1"))
+	// Limit is not allowed in the nested query blocks of the recursive part.
+	tk.MustGetErrCode("with recursive cte(n) as (select 1 union all ((select n + 1 from cte where n < 3 limit 1) union all (select n + 2 from cte where n < 3))) select * from cte;", errno.ErrNotSupportedYet)
 	// The recursive SELECT part must reference the CTE only once and only in its FROM clause, not in any subquery.
 	tk.MustGetErrCode("with recursive cte(n) as (select 1 union select * from cte, cte c1) select * from cte;", errno.ErrInvalidRequiresSingleReference)
 	tk.MustGetErrCode("with recursive cte(n) as (select 1 union select * from (select * from cte) c1) select * from cte;", errno.ErrInvalidRequiresSingleReference)
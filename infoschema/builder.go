@@ -159,6 +159,12 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 			case model.ActionDropTable, model.ActionDropTablePartition:
 				b.applyPlacementDelete(placement.GroupID(opt.OldTableID))
 				continue
+			case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+				// The old partitions are replaced by the new ones, so the transactions which
+				// have written the old partitions are related to the change as well.
+				tblIDs = append(tblIDs, opt.OldTableID)
+				b.applyPlacementDelete(placement.GroupID(opt.OldTableID))
+				continue
 			case model.ActionTruncateTable:
 				b.applyPlacementDelete(placement.GroupID(opt.OldTableID))
 				err := b.applyPlacementUpdate(placement.GroupID(opt.TableID))
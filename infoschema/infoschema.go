@@ -55,6 +55,15 @@ type InfoSchema interface {
 	SetBundle(*placement.Bundle)
 	// RuleBundles will return a copy of all rule bundles.
 	RuleBundles() []*placement.Bundle
+	// ReferredFKs returns the public and enforced foreign keys which refer to the table.
+	ReferredFKs(tableID int64) []*ReferredFKInfo
+}
+
+// ReferredFKInfo is a foreign key of the child table which refers to another table.
+type ReferredFKInfo struct {
+	ChildSchema model.CIStr
+	ChildTable  table.Table
+	FK          *model.FKInfo
 }
 
 type sortedTables []table.Table
@@ -100,6 +109,10 @@ type infoSchema struct {
 
 	// schemaMetaVersion is the version of schema, and we should check version when change schema.
 	schemaMetaVersion int64
+
+	// referredFKs indexes the foreign keys by the ID of the referred table, it's built when it's used for the first time.
+	referredFKsOnce sync.Once
+	referredFKs     map[int64][]*ReferredFKInfo
 }
 
 // MockInfoSchema only serves for test.
@@ -229,6 +242,45 @@ func (is *infoSchema) SchemaByTable(tableInfo *model.TableInfo) (val *model.DBIn
 	return nil, false
 }
 
+// ReferredFKs implements InfoSchema.ReferredFKs.
+func (is *infoSchema) ReferredFKs(tableID int64) []*ReferredFKInfo {
+	is.referredFKsOnce.Do(is.buildReferredFKs)
+	return is.referredFKs[tableID]
+}
+
+func (is *infoSchema) buildReferredFKs() {
+	is.referredFKs = make(map[int64][]*ReferredFKInfo)
+	for _, v := range is.schemaMap {
+		for _, child := range v.tables {
+			for _, fk := range child.Meta().ForeignKeys {
+				if fk.State != model.StatePublic || fk.Version < model.FKVersion1 {
+					continue
+				}
+				// The foreign key refers to the table in the same schema.
+				parent, ok := v.tables[fk.RefTable.L]
+				if !ok {
+					continue
+				}
+				parentID := parent.Meta().ID
+				is.referredFKs[parentID] = append(is.referredFKs[parentID], &ReferredFKInfo{
+					ChildSchema: v.dbInfo.Name,
+					ChildTable:  child,
+					FK:          fk,
+				})
+			}
+		}
+	}
+	// Sort the foreign keys, so that the referential actions are applied in a stable order.
+	for _, fks := range is.referredFKs {
+		sort.Slice(fks, func(i, j int) bool {
+			if fks[i].ChildTable.Meta().ID != fks[j].ChildTable.Meta().ID {
+				return fks[i].ChildTable.Meta().ID < fks[j].ChildTable.Meta().ID
+			}
+			return fks[i].FK.ID < fks[j].FK.ID
+		})
+	}
+}
+
 func (is *infoSchema) TableByID(id int64) (val table.Table, ok bool) {
 	slice := is.sortedTablesBuckets[tableBucketIdx(id)]
 	idx := slice.searchTable(id)
@@ -218,6 +218,19 @@ func (*testSuite) TestT(c *C) {
 	schema, ok = is.SchemaByID(dbID)
 	c.Assert(ok, IsTrue)
 	c.Assert(len(schema.Tables), Equals, 1)
+
+	// The IDs of the partitions which are replaced by reorganizing partitions are returned.
+	for _, tp := range []model.ActionType{model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning} {
+		txn, err = store.Begin()
+		c.Assert(err, IsNil)
+		diff := &model.SchemaDiff{Type: tp, SchemaID: dbID, TableID: tbID,
+			AffectedOpts: []*model.AffectedOption{{OldTableID: 1001}, {OldTableID: 1002}}}
+		tblIDs, err := builder.ApplyDiff(meta.NewMeta(txn), diff)
+		c.Assert(err, IsNil)
+		c.Assert(tblIDs, DeepEquals, []int64{tbID, 1001, 1002}, Commentf("action: %v", tp))
+		err = txn.Rollback()
+		c.Assert(err, IsNil)
+	}
 }
 
 func (testSuite) TestMockInfoSchema(c *C) {
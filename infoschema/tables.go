@@ -172,6 +172,8 @@ const (
 	TableDeadlocks = "DEADLOCKS"
 	// TableDataLockWaits is current lock waiting status table.
 	TableDataLockWaits = "DATA_LOCK_WAITS"
+	// TableCheckConstraints is the string constant of CHECK_CONSTRAINTS.
+	TableCheckConstraints = "CHECK_CONSTRAINTS"
 )
 
 var tableIDMap = map[string]int64{
@@ -251,6 +253,7 @@ var tableIDMap = map[string]int64{
 	TableDataLockWaits:                      autoid.InformationSchemaDBID + 74,
 	TableStatementsSummaryEvicted:           autoid.InformationSchemaDBID + 75,
 	ClusterTableStatementsSummaryEvicted:    autoid.InformationSchemaDBID + 76,
+	TableCheckConstraints:                   autoid.InformationSchemaDBID + 77,
 }
 
 type columnInfo struct {
@@ -328,6 +331,21 @@ func buildTableMeta(tableName string, cs []columnInfo) *model.TableInfo {
 	return tblInfo
 }
 
+// GetMemTableColumns returns the columns of the memory table, or nil if the table doesn't exist.
+func GetMemTableColumns(tableName string) []*model.ColumnInfo {
+	cs, ok := tableNameToColumns[tableName]
+	if !ok {
+		return nil
+	}
+	cols := make([]*model.ColumnInfo, 0, len(cs))
+	for i, c := range cs {
+		col := buildColumnInfo(c)
+		col.Offset = i
+		cols = append(cols, col)
+	}
+	return cols
+}
+
 var schemataCols = []columnInfo{
 	{name: "CATALOG_NAME", tp: mysql.TypeVarchar, size: 512},
 	{name: "SCHEMA_NAME", tp: mysql.TypeVarchar, size: 64},
@@ -543,6 +561,13 @@ var tableConstraintsCols = []columnInfo{
 	{name: "CONSTRAINT_TYPE", tp: mysql.TypeVarchar, size: 64},
 }
 
+var tableCheckConstraintsCols = []columnInfo{
+	{name: "CONSTRAINT_CATALOG", tp: mysql.TypeVarchar, size: 64},
+	{name: "CONSTRAINT_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "CONSTRAINT_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "CHECK_CLAUSE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
 var tableTriggersCols = []columnInfo{
 	{name: "TRIGGER_CATALOG", tp: mysql.TypeVarchar, size: 512},
 	{name: "TRIGGER_SCHEMA", tp: mysql.TypeVarchar, size: 64},
@@ -1429,6 +1454,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// CheckConstraintType is the string constant of CHECK.
+	CheckConstraintType = "CHECK"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1779,6 +1806,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableTiDBTrx:                            tableTiDBTrxCols,
 	TableDeadlocks:                          tableDeadlocksCols,
 	TableDataLockWaits:                      tableDataLockWaitsCols,
+	TableCheckConstraints:                   tableCheckConstraintsCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -51,6 +51,7 @@ import (
 	"github.com/pingcap/tidb/store/mockstore/mockstorage"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/resourcegrouptag"
@@ -1268,6 +1269,130 @@ func (s *testClusterTableSuite) TestStmtSummaryHistoryTable(c *C) {
 	))
 }
 
+// Test persisting statements_summary_history.
+func (s *testClusterTableSuite) TestStmtSummaryHistoryPersist(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("drop table if exists test_summary")
+	tk.MustExec("create table test_summary(a int)")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	tk.MustExec("set global tidb_stmt_summary_refresh_interval = 1800")
+	defer func() {
+		// Clear the summaries so that they won't affect other tests.
+		tk.MustExec("set global tidb_enable_stmt_summary = 0")
+		tk.MustExec("set global tidb_enable_stmt_summary = 1")
+		tk.MustExec("set global tidb_stmt_summary_refresh_interval = default")
+	}()
+
+	tk = s.newTestKitWithRoot(c)
+	tk.MustExec("insert into test_summary values(1)")
+	tk.MustExec("insert into test_summary values(2)")
+
+	persistedSQL := "select instance is not null, stmt_type, exec_count from mysql.statements_summary_history where digest_text like 'insert into `test_summary`%'"
+	allSQL := "select stmt_type, exec_count from mysql.cluster_statements_summary_history_all where digest_text like 'insert into `test_summary`%'"
+	tk.MustQuery(persistedSQL).Check(testkit.Rows())
+	tk.MustQuery(allSQL).Check(testkit.Rows("Insert 2"))
+
+	// The current summary expires at most 1800 seconds later.
+	now := time.Now().Unix()
+	persistTime, err := s.dom.PersistStmtSummary(tk.Se, 0, now+1800)
+	c.Assert(err, IsNil)
+	c.Assert(persistTime, Equals, now+1800)
+	tk.MustQuery(persistedSQL).Check(testkit.Rows("1 Insert 2"))
+	// The persisted summaries are not shown twice.
+	tk.MustQuery(allSQL).Check(testkit.Rows("Insert 2"))
+
+	// Summaries are persisted only once.
+	persistTime, err = s.dom.PersistStmtSummary(tk.Se, persistTime, now+3600)
+	c.Assert(err, IsNil)
+	c.Assert(persistTime, Equals, now+3600)
+	tk.MustQuery(persistedSQL).Check(testkit.Rows("1 Insert 2"))
+
+	// Summaries out of retention are removed.
+	retention := int64(config.GetGlobalConfig().StmtSummary.PersistRetentionDays) * 24 * 3600
+	_, err = s.dom.PersistStmtSummary(tk.Se, persistTime, now+3600+retention)
+	c.Assert(err, IsNil)
+	tk.MustQuery(persistedSQL).Check(testkit.Rows())
+}
+
+// Test persisting deadlock history.
+func (s *testClusterTableSuite) TestDeadlockHistoryPersist(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	history := deadlockhistory.NewDeadlockHistory(10)
+	// The same deadlock seen from 2 instances.
+	history.Push(&deadlockhistory.DeadlockRecord{
+		OccurTime:   time.Now(),
+		IsRetryable: false,
+		WaitChain: []deadlockhistory.WaitChainItem{
+			{TryLockTxn: 101, SQLDigest: "aabb", Key: []byte("k1"), TxnHoldingLock: 102, ResourceGroupTag: []byte{1, 2}},
+			{TryLockTxn: 102, SQLDigest: "ccdd", Key: []byte("k2"), TxnHoldingLock: 101},
+		},
+	})
+	history.Push(&deadlockhistory.DeadlockRecord{
+		OccurTime:   time.Now(),
+		IsRetryable: true,
+		WaitChain: []deadlockhistory.WaitChainItem{
+			{TryLockTxn: 102, SQLDigest: "ccdd", Key: []byte("k2"), TxnHoldingLock: 101},
+			{TryLockTxn: 101, TxnHoldingLock: 102},
+		},
+	})
+
+	lastID, err := s.dom.PersistDeadlockHistory(tk.Se, history, 0)
+	c.Assert(err, IsNil)
+	c.Assert(lastID, Equals, uint64(2))
+	tk.MustQuery("select deadlock_id, retryable, try_lock_trx_id, current_sql_digest, `key`, trx_holding_lock, resource_group_tag, deadlock_key " +
+		"from mysql.deadlock_history order by deadlock_id, try_lock_trx_id").Check(testkit.Rows(
+		"1 0 101 aabb 6B31 102 0102 101,102",
+		"1 0 102 ccdd 6B32 101 <nil> 101,102",
+		"2 1 101 <nil> <nil> 102 <nil> 101,102",
+		"2 1 102 ccdd 6B32 101 <nil> 101,102",
+	))
+
+	// Records are persisted only once.
+	history.Push(&deadlockhistory.DeadlockRecord{
+		OccurTime: time.Now(),
+		WaitChain: []deadlockhistory.WaitChainItem{
+			{TryLockTxn: 201, TxnHoldingLock: 202},
+			{TryLockTxn: 202, TxnHoldingLock: 201},
+		},
+	})
+	lastID, err = s.dom.PersistDeadlockHistory(tk.Se, history, lastID)
+	c.Assert(err, IsNil)
+	c.Assert(lastID, Equals, uint64(3))
+	lastID, err = s.dom.PersistDeadlockHistory(tk.Se, history, lastID)
+	c.Assert(err, IsNil)
+	c.Assert(lastID, Equals, uint64(3))
+	tk.MustQuery("select count(*) from mysql.deadlock_history").Check(testkit.Rows("6"))
+
+	tk.MustQuery("select deadlock_key, occur_count, instance_count, sql_digests, retryable from mysql.cluster_deadlocks_summary order by deadlock_key").Check(testkit.Rows(
+		"101,102 2 1 aabb,ccdd 1",
+		"201,202 1 1 <nil> 0",
+	))
+
+	// The IDs are allocated from 1 again after restarting, the deadlocks with the same IDs are still counted.
+	restartedHistory := deadlockhistory.NewDeadlockHistory(10)
+	restartedHistory.Push(&deadlockhistory.DeadlockRecord{
+		OccurTime: time.Now().Add(time.Second),
+		WaitChain: []deadlockhistory.WaitChainItem{
+			{TryLockTxn: 101, TxnHoldingLock: 102},
+			{TryLockTxn: 102, TxnHoldingLock: 101},
+		},
+	})
+	restartedID, err := s.dom.PersistDeadlockHistory(tk.Se, restartedHistory, 0)
+	c.Assert(err, IsNil)
+	c.Assert(restartedID, Equals, uint64(1))
+	tk.MustQuery("select deadlock_key, occur_count, instance_count from mysql.cluster_deadlocks_summary order by deadlock_key").Check(testkit.Rows(
+		"101,102 3 1",
+		"201,202 1 1",
+	))
+
+	// Records out of retention are removed.
+	tk.MustExec("update mysql.deadlock_history set occur_time = date_sub(occur_time, interval 30 day) where deadlock_id = 1")
+	_, err = s.dom.PersistDeadlockHistory(tk.Se, history, lastID)
+	c.Assert(err, IsNil)
+	tk.MustQuery("select distinct deadlock_id from mysql.deadlock_history order by deadlock_id").Check(testkit.Rows("2", "3"))
+	tk.MustExec("delete from mysql.deadlock_history")
+}
+
 // Test statements_summary_history.
 func (s *testTableSuite) TestStmtSummaryInternalQuery(c *C) {
 	tk := s.newTestKitWithRoot(c)
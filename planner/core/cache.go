@@ -173,10 +173,13 @@ type PSTMTPlanCacheValue struct {
 	OutPutNames       []*types.FieldName
 	TblInfo2UnionScan map[*model.TableInfo]bool
 	UserVarTypes      FieldSlice
+	// BindSQL is the bind SQLs of the bindings which may be applied when the plan is built,
+	// the plan is out of date once they are changed.
+	BindSQL string
 }
 
 // NewPSTMTPlanCacheValue creates a SQLCacheValue.
-func NewPSTMTPlanCacheValue(plan Plan, names []*types.FieldName, srcMap map[*model.TableInfo]bool, userVarTps []*types.FieldType) *PSTMTPlanCacheValue {
+func NewPSTMTPlanCacheValue(plan Plan, names []*types.FieldName, srcMap map[*model.TableInfo]bool, userVarTps []*types.FieldType, bindSQL string) *PSTMTPlanCacheValue {
 	dstMap := make(map[*model.TableInfo]bool)
 	for k, v := range srcMap {
 		dstMap[k] = v
@@ -190,6 +193,7 @@ func NewPSTMTPlanCacheValue(plan Plan, names []*types.FieldName, srcMap map[*mod
 		OutPutNames:       names,
 		TblInfo2UnionScan: dstMap,
 		UserVarTypes:      userVarTypes,
+		BindSQL:           bindSQL,
 	}
 }
 
@@ -205,4 +209,10 @@ type CachedPrepareStmt struct {
 	PlanDigest          *parser.Digest
 	ForUpdateRead       bool
 	SnapshotTSEvaluator func(sessionctx.Context) (uint64, error)
+	// BindSQL is the bind SQLs of the bindings which may be applied when the point plan in PreparedAst.CachedPlan
+	// is built, BindingVersion and UsePlanBaselines are the version of the binding caches and the session variable
+	// tidb_use_plan_baselines then.
+	BindSQL          string
+	BindingVersion   uint64
+	UsePlanBaselines bool
 }
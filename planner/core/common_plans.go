@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
@@ -380,8 +381,11 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	prepared := preparedStmt.PreparedAst
 	stmtCtx.UseCache = prepared.UseCache
 	var cacheKey kvcache.Key
+	var bindSQL string
+	bindingVersion := bindinfo.CacheVersion()
 	if prepared.UseCache {
 		cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+		bindSQL = GetBindSQL4PlanCache(sctx, prepared.Stmt)
 	}
 	tps := make([]*types.FieldType, len(e.UsingVars))
 	for i, param := range e.UsingVars {
@@ -391,6 +395,9 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 			tps[i] = types.NewFieldType(mysql.TypeNull)
 		}
 	}
+	if prepared.CachedPlan != nil && !CheckCachedPointPlanBinding(sctx, preparedStmt) {
+		prepared.CachedPlan = nil
+	}
 	if prepared.CachedPlan != nil {
 		// Rewriting the expression in the select.where condition  will convert its
 		// type from "paramMarker" to "Constant".When Point Select queries are executed,
@@ -424,6 +431,12 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 			}
 			cachedVals := cacheValue.([]*PSTMTPlanCacheValue)
 			for _, cachedVal := range cachedVals {
+				if cachedVal.BindSQL != bindSQL {
+					// The bindings have been created, dropped or evolved since the plans were cached,
+					// so none of the cached plans can be used.
+					sctx.PreparedPlanCache().Delete(cacheKey)
+					break
+				}
 				if !cachedVal.UserVarTypes.Equal(tps) {
 					continue
 				}
@@ -447,6 +460,13 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 					if err != nil {
 						return err
 					}
+					if len(bindSQL) > 0 {
+						// The cached plan is built with the bindings.
+						err = sessVars.SetSystemVar(variable.TiDBFoundInBinding, variable.BoolToOnOff(true))
+						if err != nil {
+							return err
+						}
+					}
 					if metrics.ResettablePlanCacheCounterFortTest {
 						metrics.PlanCacheCounter.WithLabelValues("prepare").Inc()
 					} else {
@@ -463,12 +483,16 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	}
 
 REBUILD:
+	if !prepared.UseCache {
+		// The bind SQLs are kept along with the cached point plan.
+		bindSQL = GetBindSQL4PlanCache(sctx, prepared.Stmt)
+	}
 	stmt := TryAddExtraLimit(sctx, prepared.Stmt)
 	p, names, err := OptimizeAstNode(ctx, sctx, stmt, is)
 	if err != nil {
 		return err
 	}
-	err = e.tryCachePointPlan(ctx, sctx, preparedStmt, is, p)
+	err = e.tryCachePointPlan(ctx, sctx, preparedStmt, is, p, bindSQL, bindingVersion)
 	if err != nil {
 		return err
 	}
@@ -482,7 +506,7 @@ REBUILD:
 			cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
 			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
 		}
-		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps)
+		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps, bindSQL)
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
 		if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
@@ -509,10 +533,14 @@ REBUILD:
 // tryCachePointPlan will try to cache point execution plan, there may be some
 // short paths for these executions, currently "point select" and "point update"
 func (e *Execute) tryCachePointPlan(ctx context.Context, sctx sessionctx.Context,
-	preparedStmt *CachedPrepareStmt, is infoschema.InfoSchema, p Plan) error {
+	preparedStmt *CachedPrepareStmt, is infoschema.InfoSchema, p Plan, bindSQL string, bindingVersion uint64) error {
 	if sctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst {
 		return nil
 	}
+	// The short paths don't report the usage of bindings, so the plans matched with bindings are not cached here.
+	if sctx.GetSessionVars().FoundInBinding {
+		return nil
+	}
 	var (
 		prepared = preparedStmt.PreparedAst
 		ok       bool
@@ -531,12 +559,30 @@ func (e *Execute) tryCachePointPlan(ctx context.Context, sctx sessionctx.Context
 		// just cache point plan now
 		prepared.CachedPlan = p
 		prepared.CachedNames = names
+		preparedStmt.BindSQL, preparedStmt.BindingVersion = bindSQL, bindingVersion
+		preparedStmt.UsePlanBaselines = sctx.GetSessionVars().UsePlanBaselines
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		sctx.GetSessionVars().StmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
 	}
 	return err
 }
 
+// CheckCachedPointPlanBinding checks whether the bindings which may be applied to the prepared statement are still
+// the same as the ones when the point plan was cached. The statement is matched with the bindings again only if
+// the binding caches have changed since then, so it's cheap to call it on every execution.
+func CheckCachedPointPlanBinding(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt) bool {
+	version := bindinfo.CacheVersion()
+	usePlanBaselines := sctx.GetSessionVars().UsePlanBaselines
+	if version == preparedStmt.BindingVersion && usePlanBaselines == preparedStmt.UsePlanBaselines {
+		return true
+	}
+	if GetBindSQL4PlanCache(sctx, preparedStmt.PreparedAst.Stmt) != preparedStmt.BindSQL {
+		return false
+	}
+	preparedStmt.BindingVersion, preparedStmt.UsePlanBaselines = version, usePlanBaselines
+	return true
+}
+
 func (e *Execute) rebuildRange(p Plan) error {
 	sctx := p.SCtx()
 	sc := p.SCtx().GetSessionVars().StmtCtx
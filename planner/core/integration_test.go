@@ -941,6 +941,45 @@ func (s *testIntegrationSerialSuite) TestPartitionTableDynamicModeUnderNewCollat
 	tk.MustQuery(`select * from strlist where a in ('D', 'e')`).Sort().Check(testkit.Rows("D 1", "d 1", "e 1"))
 }
 
+func (s *testIntegrationSerialSuite) TestKeyPartitionUnderNewCollation(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create database test_key_partition")
+	defer tk.MustExec("drop database test_key_partition")
+	tk.MustExec("use test_key_partition")
+	tk.MustExec(`create table tkey (a int, c varchar(20) charset utf8mb4 collate utf8mb4_general_ci, key(a)) partition by key(c, a) partitions 8`)
+	tk.MustExec(`insert into tkey values (1, 'a'), (1, 'A'), (1, 'a '), (2, 'b'), (2, 'B'), (3, 'c'), (3, null), (null, null)`)
+	// The values which are equal under the collation are located in the same partition.
+	for _, mode := range []string{"static", "dynamic"} {
+		tk.MustExec("set @@tidb_partition_prune_mode = '" + mode + "'")
+		tk.MustQuery(`select * from tkey where c = 'a' and a = 1`).Sort().Check(testkit.Rows("1 A", "1 a", "1 a "))
+		tk.MustQuery(`select * from tkey where c in ('B', 'c') and a in (2, 3)`).Sort().Check(testkit.Rows("2 B", "2 b", "3 c"))
+		tk.MustQuery(`select * from tkey where c is null and a = 3`).Check(testkit.Rows("3 <nil>"))
+		tk.MustQuery(`select * from tkey where c is null and a is null`).Check(testkit.Rows("<nil> <nil>"))
+		tk.MustQuery(`select count(*) from tkey where c = 'a'`).Check(testkit.Rows("3"))
+	}
+
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	accessedPartitions := func(sql string) int {
+		partitions := make(map[string]struct{})
+		for _, row := range tk.MustQuery(sql).Rows() {
+			for _, field := range strings.Split(row[3].(string), " ") {
+				if strings.HasPrefix(field, "partition:") {
+					partitions[strings.TrimSuffix(field, ",")] = struct{}{}
+				}
+			}
+		}
+		return len(partitions)
+	}
+	// Only one partition is accessed when all the partitioning columns are fixed.
+	c.Assert(accessedPartitions(`explain format = 'brief' select * from tkey where c = 'A' and a = 1`), Equals, 1)
+	c.Assert(accessedPartitions(`explain format = 'brief' select * from tkey where c = 'A' and a = 1 or c = 'a ' and a = 1`), Equals, 1)
+	// All partitions are accessed unless all the partitioning columns are fixed.
+	c.Assert(accessedPartitions(`explain format = 'brief' select * from tkey where c = 'A'`), Equals, 8)
+	tk.MustQuery(`explain format = 'brief' select * from tkey where c = 'a' and c = 'b' and a = 1`).Check(testkit.Rows("TableDual 0.00 root  rows:0"))
+}
+
 func (s *testIntegrationSerialSuite) TestMPPAvgRewrite(c *C) {
 	defer collate.SetNewCollationEnabledForTest(false)
 	tk := testkit.NewTestKit(c, s.store)
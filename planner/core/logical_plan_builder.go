@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/format"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
@@ -49,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -336,6 +338,9 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, v, x.AsName)
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -351,7 +356,7 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 				name.TblName = x.AsName
 			}
 		}
-		// `TableName` is not a select block, so we do not need to handle it.
+		// `TableName` and `JSONTable` are not select blocks, so we do not need to handle them.
 		if !isTableName && b.ctx.GetSessionVars().PlannerSelectBlockAsName != nil {
 			b.ctx.GetSessionVars().PlannerSelectBlockAsName[p.SelectBlockOffset()] = ast.HintTable{DBName: p.OutputNames()[0].DBName, TableName: p.OutputNames()[0].TblName}
 		}
@@ -375,6 +380,122 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 	}
 }
 
+// buildJSONTable builds a LogicalJSONTable. The JSON document can refer to the columns of the tables
+// on the left side of JSON_TABLE, which are found in the outer schemas and become correlated columns.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, jt *ast.JSONTable, asName model.CIStr) (LogicalPlan, error) {
+	dual := LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
+	dual.SetSchema(expression.NewSchema())
+	expr, np, err := b.rewrite(ctx, jt.Expr, dual, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != dual {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("subqueries in JSON_TABLE")
+	}
+	if expr.GetType().EvalType() != types.ETJson {
+		expr = expression.BuildCastFunction(b.ctx, expr, types.NewFieldType(mysql.TypeJSON))
+	}
+	path, err := json.ParseJSONPathExpr(jt.Path)
+	if err != nil {
+		return nil, err
+	}
+	p := LogicalJSONTable{AsName: asName, Expr: expr, Path: path}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	names := make(types.NameSlice, 0, len(jt.Columns))
+	p.Columns, err = b.buildJSONTableColumns(jt.Columns, asName, schema, &names)
+	if err != nil {
+		return nil, err
+	}
+	p.SetSchema(schema)
+	p.names = names
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildJSONTableColumns builds the column definitions of JSON_TABLE. The nested columns are flattened into the
+// schema in the order they are defined.
+func (b *PlanBuilder) buildJSONTableColumns(cols []*ast.JSONTableColumn, asName model.CIStr, schema *expression.Schema, names *types.NameSlice) ([]*JSONTableColumn, error) {
+	result := make([]*JSONTableColumn, 0, len(cols))
+	for _, col := range cols {
+		jc := &JSONTableColumn{Tp: col.Tp, Name: col.Name}
+		if col.Tp != ast.JSONTableColumnOrdinality {
+			path, err := json.ParseJSONPathExpr(col.Path)
+			if err != nil {
+				return nil, err
+			}
+			jc.Path = path
+		}
+		if col.Tp == ast.JSONTableColumnNested {
+			nested, err := b.buildJSONTableColumns(col.NestedColumns, asName, schema, names)
+			if err != nil {
+				return nil, err
+			}
+			jc.NestedColumns = nested
+			result = append(result, jc)
+			continue
+		}
+		var ft *types.FieldType
+		if col.Tp == ast.JSONTableColumnOrdinality {
+			ft = types.NewFieldType(mysql.TypeLonglong)
+			ft.Flag |= mysql.UnsignedFlag
+		} else {
+			ft = col.FieldType.Clone()
+		}
+		b.completeJSONTableFieldType(ft)
+		if col.Tp == ast.JSONTableColumnPath {
+			var err error
+			if jc.OnEmpty, err = buildJSONTableOnResponse(col.OnEmpty); err != nil {
+				return nil, err
+			}
+			if jc.OnError, err = buildJSONTableOnResponse(col.OnError); err != nil {
+				return nil, err
+			}
+		}
+		jc.Offset = schema.Len()
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  ft,
+		})
+		*names = append(*names, &types.FieldName{TblName: asName, ColName: col.Name, OrigColName: col.Name})
+		result = append(result, jc)
+	}
+	return result, nil
+}
+
+// completeJSONTableFieldType fills the charset, the collation, the length and the decimal of a JSON_TABLE column
+// which are not specified in its definition.
+func (b *PlanBuilder) completeJSONTableFieldType(ft *types.FieldType) {
+	if ft.Charset == "" {
+		if ft.EvalType() == types.ETString {
+			ft.Charset, ft.Collate = b.ctx.GetSessionVars().GetCharsetInfo()
+		} else {
+			ft.Charset, ft.Collate = charset.CharsetBin, charset.CollationBin
+		}
+	}
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.Tp)
+	if ft.Flen == types.UnspecifiedLength {
+		ft.Flen = defaultFlen
+	}
+	if ft.Decimal == types.UnspecifiedLength {
+		ft.Decimal = defaultDecimal
+	}
+}
+
+func buildJSONTableOnResponse(resp *ast.JSONTableOnResponse) (*JSONTableOnResponse, error) {
+	if resp == nil {
+		return &JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}, nil
+	}
+	result := &JSONTableOnResponse{Tp: resp.Tp}
+	if resp.Tp == ast.JSONTableOnResponseDefault {
+		def, err := json.ParseBinaryFromString(resp.Default)
+		if err != nil {
+			return nil, err
+		}
+		result.Default = def
+	}
+	return result, nil
+}
+
 // pushDownConstExpr checks if the condition is from filter condition, if true, push it down to both
 // children of join, whatever the join type is; if false, push it down to inner child of outer join,
 // and both children of non-outer-join.
@@ -680,7 +801,18 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
+	// JSON_TABLE can refer to the columns of the tables on its left side, like a LATERAL derived table.
+	jtSource, isJSONTable := joinNode.Right.(*ast.TableSource)
+	isJSONTable = isJSONTable && isJSONTableSource(jtSource)
+	if isJSONTable {
+		b.outerSchemas = append(b.outerSchemas, leftPlan.Schema())
+		b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	}
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	if isJSONTable {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -701,6 +833,19 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	copy(joinPlan.names, leftPlan.OutputNames())
 	copy(joinPlan.names[leftPlan.Schema().Len():], rightPlan.OutputNames())
 
+	// The JSON_TABLE which refers to the left side is evaluated for every row of the left side by an Apply.
+	var resultPlan LogicalPlan = joinPlan
+	if isJSONTable && len(extractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())) > 0 {
+		if joinNode.Tp == ast.RightJoin {
+			return nil, ErrTFForbiddenJoinType.GenWithStackByArgs(jtSource.AsName.O)
+		}
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		ap := &LogicalApply{LogicalJoin: *joinPlan}
+		ap.tp = plancodec.TypeApply
+		ap.self = ap
+		joinPlan, resultPlan = &ap.LogicalJoin, ap
+	}
+
 	// Set join type.
 	switch joinNode.Tp {
 	case ast.LeftJoin:
@@ -771,7 +916,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(resultPlan)
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -781,7 +926,12 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return resultPlan, nil
+}
+
+func isJSONTableSource(ts *ast.TableSource) bool {
+	_, ok := ts.Source.(*ast.JSONTable)
+	return ok
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -3388,8 +3538,10 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		b.popTableHints()
 	}()
 	if b.buildingRecursivePartForCTE {
-		if sel.Distinct || sel.OrderBy != nil || sel.Limit != nil {
-			return nil, ErrNotSupportedYet.GenWithStackByArgs("ORDER BY / LIMIT / SELECT DISTINCT in recursive query block of Common Table Expression")
+		// ORDER BY and DISTINCT take effect on the rows produced by each iteration. The LIMIT of a top level
+		// recursive query block has been moved to the whole CTE, see buildRecursiveCTE.
+		if sel.Limit != nil {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("LIMIT in nested recursive query block of Common Table Expression")
 		}
 		if sel.GroupBy != nil {
 			return nil, ErrCTERecursiveForbidsAggregation.FastGenByArgs(b.genCTETableNameForError())
@@ -5246,7 +5398,8 @@ func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *a
 		return bound, nil
 	}
 
-	if frameType == ast.Rows {
+	// For GROUPS frames, Num is the number of peer groups.
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Type == ast.CurrentRow {
 			return bound, nil
 		}
@@ -5366,9 +5519,6 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 
 func (b *PlanBuilder) checkWindowFuncArgs(ctx context.Context, p LogicalPlan, windowFuncExprs []*ast.WindowFuncExpr, windowAggMap map[*ast.AggregateFuncExpr]int) error {
 	for _, windowFuncExpr := range windowFuncExprs {
-		if strings.ToLower(windowFuncExpr.F) == ast.AggFuncGroupConcat {
-			return ErrNotSupportedYet.GenWithStackByArgs("group_concat as window function")
-		}
 		args, err := b.buildArgs4WindowFunc(ctx, p, windowFuncExpr.Args, windowAggMap)
 		if err != nil {
 			return err
@@ -5448,8 +5598,15 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 	for _, window := range sortWindowSpecs(groupedFuncs, orderedSpec) {
 		args = args[:0]
 		spec, funcs := window.spec, window.funcs
+		orderByExprs := make([][]ast.ExprNode, 0, len(funcs))
 		for _, windowFunc := range funcs {
 			args = append(args, windowFunc.Args...)
+			exprs, err := b.resolveWindowFuncOrderBy(windowFunc)
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, exprs...)
+			orderByExprs = append(orderByExprs, exprs)
 		}
 		np, partitionBy, orderBy, args, err := b.buildProjectionForWindow(ctx, p, spec, args, aggMap)
 		if err != nil {
@@ -5483,7 +5640,7 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 		schema := np.Schema().Clone()
 		descs := make([]*aggregation.WindowFuncDesc, 0, len(funcs))
 		preArgs := 0
-		for _, windowFunc := range funcs {
+		for i, windowFunc := range funcs {
 			desc, err := aggregation.NewWindowFuncDesc(b.ctx, windowFunc.F, args[preArgs:preArgs+len(windowFunc.Args)])
 			if err != nil {
 				return nil, nil, err
@@ -5492,6 +5649,13 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 				return nil, nil, ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.F))
 			}
 			preArgs += len(windowFunc.Args)
+			for j := range orderByExprs[i] {
+				desc.OrderByItems = append(desc.OrderByItems, &util.ByItems{Expr: args[preArgs], Desc: windowFunc.Order.Items[j].Desc})
+				preArgs++
+			}
+			desc.HasDistinct = windowFunc.Distinct
+			desc.IgnoreNull = windowFunc.IgnoreNull
+			desc.FromLast = windowFunc.FromLast
 			desc.WrapCastForAggArgs(b.ctx)
 			descs = append(descs, desc)
 			windowMap[windowFunc] = schema.Len()
@@ -5509,19 +5673,33 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 	return p, windowMap, nil
 }
 
+// resolveWindowFuncOrderBy resolves the positions in the ORDER BY clause of the window function GROUP_CONCAT,
+// the returned expressions are evaluated together with the arguments of the window function.
+func (b *PlanBuilder) resolveWindowFuncOrderBy(windowFunc *ast.WindowFuncExpr) ([]ast.ExprNode, error) {
+	if windowFunc.Order == nil {
+		return nil, nil
+	}
+	resolver := &aggOrderByResolver{
+		ctx:  b.ctx,
+		args: windowFunc.Args[:len(windowFunc.Args)-1], // the last argument is SEPARATOR, remove it.
+	}
+	exprs := make([]ast.ExprNode, 0, len(windowFunc.Order.Items))
+	for _, byItem := range windowFunc.Order.Items {
+		resolver.exprDepth = 0
+		resolver.err = nil
+		retExpr, _ := byItem.Expr.Accept(resolver)
+		if resolver.err != nil {
+			return nil, errors.Trace(resolver.err)
+		}
+		exprs = append(exprs, retExpr.(ast.ExprNode))
+	}
+	return exprs, nil
+}
+
 // checkOriginWindowFuncs checks the validity for original window specifications for a group of functions.
 // Because the grouped specification is different from them, we should especially check them before build window frame.
 func (b *PlanBuilder) checkOriginWindowFuncs(funcs []*ast.WindowFuncExpr, orderByItems []property.SortItem) error {
 	for _, f := range funcs {
-		if f.IgnoreNull {
-			return ErrNotSupportedYet.GenWithStackByArgs("IGNORE NULLS")
-		}
-		if f.Distinct {
-			return ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
-		}
-		if f.FromLast {
-			return ErrNotSupportedYet.GenWithStackByArgs("FROM LAST")
-		}
 		spec := &f.Spec
 		if f.Spec.Name.L != "" {
 			spec = b.windowSpecs[f.Spec.Name.L]
@@ -5538,9 +5716,6 @@ func (b *PlanBuilder) checkOriginWindowSpec(spec *ast.WindowSpec, orderByItems [
 	if spec.Frame == nil {
 		return nil
 	}
-	if spec.Frame.Type == ast.Groups {
-		return ErrNotSupportedYet.GenWithStackByArgs("GROUPS")
-	}
 	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
 	if start.Type == ast.Following && start.UnBounded {
 		return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
@@ -5572,7 +5747,8 @@ func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *a
 	}
 
 	frameType := spec.Frame.Type
-	if frameType == ast.Rows {
+	// The offsets of GROUPS frames are counted in peer groups, so they are checked in the same way as ROWS frames.
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Unit != ast.TimeUnitInvalid {
 			return ErrWindowRowsIntervalUse.GenWithStackByArgs(getWindowName(spec.Name.O))
 		}
@@ -6007,6 +6183,9 @@ func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, cte ast.ResultSetNo
 		// 2. Build plans for each part of SetOprStmt.
 		recursive := make([]LogicalPlan, 0)
 		tmpAfterSetOptsForRecur := []*ast.SetOprType{nil}
+		// recurLimit is the LIMIT of the recursive query block. Like MySQL, it has the same effect as the LIMIT of the whole CTE,
+		// so the recursive query block is built from a copy without the LIMIT.
+		var recurLimit *ast.Limit
 
 		expectSeed := true
 		for i := 0; i < len(x.SelectList.Selects); i++ {
@@ -6016,6 +6195,15 @@ func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, cte ast.ResultSetNo
 			var afterOpr *ast.SetOprType
 			switch y := x.SelectList.Selects[i].(type) {
 			case *ast.SelectStmt:
+				if !expectSeed && y.Limit != nil {
+					if x.Limit != nil || recurLimit != nil {
+						return ErrNotSupportedYet.GenWithStackByArgs("more than one LIMIT in recursive Common Table Expression")
+					}
+					recurLimit = y.Limit
+					sel := *y
+					sel.Limit = nil
+					y = &sel
+				}
 				p, err = b.buildSelect(ctx, y)
 				afterOpr = y.AfterSetOperator
 			case *ast.SetOprSelectList:
@@ -6117,8 +6305,12 @@ func (b *PlanBuilder) buildRecursiveCTE(ctx context.Context, cte ast.ResultSetNo
 		// 4. Finally, we get the seed part plan and recursive part plan.
 		cInfo.recurLP = recurPart
 		// Only need to handle limit if x is SetOprStmt.
-		if x.Limit != nil {
-			limit, err := b.buildLimit(cInfo.seedLP, x.Limit)
+		limitClause := x.Limit
+		if recurLimit != nil {
+			limitClause = recurLimit
+		}
+		if limitClause != nil {
+			limit, err := b.buildLimit(cInfo.seedLP, limitClause)
 			if err != nil {
 				return err
 			}
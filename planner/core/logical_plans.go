@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -50,6 +51,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalJSONTable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -858,12 +860,32 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 		if err != nil {
 			return err
 		}
+		if len(path.AccessConds) > 0 && ds.isHypoIndex(path.Index) {
+			// The hypothetical index has no statistics, so we estimate the row count by the
+			// column statistics of the access conditions instead of the pseudo statistics.
+			selectivity, _, err := ds.tableStats.HistColl.Selectivity(ds.ctx, path.AccessConds, nil)
+			if err != nil {
+				logutil.BgLogger().Debug("calculate selectivity failed, use the pseudo row count", zap.Error(err))
+			} else {
+				path.CountAfterAccess = selectivity * float64(ds.statisticTable.Count)
+			}
+		}
 	} else {
 		path.TableFilters = conds
 	}
 	return nil
 }
 
+// isHypoIndex checks whether the index is a hypothetical index of the session, see SessionVars.HypoIndexes.
+func (ds *DataSource) isHypoIndex(index *model.IndexInfo) bool {
+	for _, hypoIndex := range ds.ctx.GetSessionVars().HypoIndexes[ds.tableInfo.ID] {
+		if hypoIndex == index {
+			return true
+		}
+	}
+	return false
+}
+
 // deriveIndexPathStats will fulfill the information that the AccessPath need.
 // And it will check whether this index is full matched by point query. We will use this check to
 // determine whether we remove other paths or not.
@@ -1085,6 +1107,9 @@ func (p *LogicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 		for _, arg := range windowFunc.Args {
 			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
 		}
+		for _, byItem := range windowFunc.OrderByItems {
+			corCols = append(corCols, expression.ExtractCorColumns(byItem.Expr)...)
+		}
 	}
 	if p.Frame != nil {
 		if p.Frame.Start != nil {
@@ -1231,3 +1256,40 @@ func (p *LogicalCTE) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
 	}
 	return corCols
 }
+
+// JSONTableOnResponse is the planned ON EMPTY or ON ERROR clause of a JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp ast.JSONTableOnResponseType
+	// Default is the parsed default value, it's only used by ast.JSONTableOnResponseDefault.
+	Default json.BinaryJSON
+}
+
+// JSONTableColumn is the planned column definition of JSON_TABLE.
+type JSONTableColumn struct {
+	Tp   ast.JSONTableColumnType
+	Name model.CIStr
+	// Offset is the offset of the column in the schema of JSON_TABLE, it's unused by nested paths.
+	Offset int
+	Path   json.PathExpression
+	// OnEmpty and OnError are never nil for the path columns.
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+
+	NestedColumns []*JSONTableColumn
+}
+
+// LogicalJSONTable is the JSON_TABLE table function. Its expression can only refer to the
+// columns of the tables on its left side, which are correlated columns in an Apply.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	AsName  model.CIStr
+	Expr    expression.Expression
+	Path    json.PathExpression
+	Columns []*JSONTableColumn
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
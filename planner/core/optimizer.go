@@ -45,6 +45,10 @@ var AllowCartesianProduct = atomic.NewBool(true)
 // IsReadOnly check whether the ast.Node is a read only statement.
 var IsReadOnly func(node ast.Node, vars *variable.SessionVars) bool
 
+// GetBindSQL4PlanCache returns the bind SQLs of the bindings which may be applied to the statement, it's used to
+// invalidate the cached plans of prepared statements when the bindings are created, dropped or evolved.
+var GetBindSQL4PlanCache func(sctx sessionctx.Context, stmt ast.StmtNode) string
+
 const (
 	flagGcSubstitute uint64 = 1 << iota
 	flagPrunColumns
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tipb/go-tipb"
@@ -66,6 +67,7 @@ var (
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
 	_ PhysicalPlan = &PhysicalTableSample{}
+	_ PhysicalPlan = &PhysicalJSONTable{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -1275,6 +1277,9 @@ func (p *PhysicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn
 		for _, arg := range windowFunc.Args {
 			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
 		}
+		for _, byItem := range windowFunc.OrderByItems {
+			corCols = append(corCols, expression.ExtractCorColumns(byItem.Expr)...)
+		}
 	}
 	if p.Frame != nil {
 		if p.Frame.Start != nil {
@@ -1494,3 +1499,18 @@ func (p *CTEDefinition) ExplainID() fmt.Stringer {
 		return "CTE_" + strconv.Itoa(p.CTE.IDForStorage)
 	})
 }
+
+// PhysicalJSONTable is the physical operator of JSON_TABLE.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	AsName  model.CIStr
+	Expr    expression.Expression
+	Path    json.PathExpression
+	Columns []*JSONTableColumn
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
@@ -1014,6 +1014,9 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			publicPaths = append(publicPaths, &util.AccessPath{Index: index})
 		}
 	}
+	for _, index := range ctx.GetSessionVars().HypoIndexes[tblInfo.ID] {
+		publicPaths = append(publicPaths, &util.AccessPath{Index: index})
+	}
 
 	hasScanHint, hasUseOrForce := false, false
 	available := make([]*util.AccessPath, 0, len(publicPaths))
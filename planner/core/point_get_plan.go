@@ -1525,6 +1525,37 @@ func getPartitionInfo(ctx sessionctx.Context, tbl *model.TableInfo, pairs []name
 				return &pi.Definitions[pos], i, false
 			}
 		}
+	case model.PartitionTypeKey:
+		// All the partitioning columns must be given to locate the partition.
+		fts := make([]*types.FieldType, 0, len(pi.Columns))
+		vals := make([]types.Datum, 0, len(pi.Columns))
+		pos := 0
+		for i, col := range pi.Columns {
+			colInfo := model.FindColumnInfo(tbl.Columns, col.L)
+			if colInfo == nil {
+				return nil, 0, false
+			}
+			found := false
+			for j, pair := range pairs {
+				if col.L == pair.colName {
+					if i == 0 {
+						pos = j
+					}
+					vals = append(vals, pair.value)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, 0, false
+			}
+			fts = append(fts, &colInfo.FieldType)
+		}
+		idx, err := tables.LocateKeyPartition(ctx.GetSessionVars().StmtCtx, pi.Num, fts, vals)
+		if err != nil {
+			return nil, 0, false
+		}
+		return &pi.Definitions[idx], pos, false
 	case model.PartitionTypeRange:
 		// left range columns partition for future development
 		if len(pi.Columns) == 0 {
@@ -40,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/domainutil"
 	utilparser "github.com/pingcap/tidb/util/parser"
+	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/tikv/client-go/v2/oracle"
 )
 
@@ -268,11 +269,7 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 			p.err = ddl.ErrDerivedMustHaveAlias.GenWithStackByArgs()
 		}
 		if v, ok := node.Source.(*ast.TableName); ok && v.TableSample != nil {
-			switch v.TableSample.SampleMethod {
-			case ast.SampleMethodTypeTiDBRegion:
-			default:
-				p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports REGIONS sampling method")
-			}
+			p.checkTableSample(v.TableSample)
 		}
 	case *ast.GroupByClause:
 		p.checkGroupBy(node)
@@ -672,6 +669,44 @@ func (p *preprocessor) checkDropDatabaseGrammar(stmt *ast.DropDatabaseStmt) {
 	}
 }
 
+func (p *preprocessor) checkTableSample(ts *ast.TableSample) {
+	switch ts.SampleMethod {
+	case ast.SampleMethodTypeTiDBRegion:
+	case ast.SampleMethodTypeSystem, ast.SampleMethodTypeBernoulli:
+		if ts.SampleClauseUnit == ast.SampleClauseUnitTypeRow {
+			p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports PERCENT for SYSTEM and BERNOULLI sampling method")
+			return
+		}
+		percent, ok := ts.Expr.(*driver.ValueExpr)
+		if !ok || !isSampleNumericLiteral(percent) {
+			p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("The sampling percentage must be a numeric literal")
+			return
+		}
+		f, err := percent.ToFloat64(p.ctx.GetSessionVars().StmtCtx)
+		if err != nil || f < 0 || f > 100 {
+			p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("The sampling percentage must be between 0 and 100")
+			return
+		}
+		if ts.RepeatableSeed != nil {
+			seed, ok := ts.RepeatableSeed.(*driver.ValueExpr)
+			if !ok || !isSampleNumericLiteral(seed) {
+				p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("The REPEATABLE seed must be a numeric literal")
+				return
+			}
+		}
+	default:
+		p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports REGIONS, SYSTEM and BERNOULLI sampling method")
+	}
+}
+
+func isSampleNumericLiteral(v *driver.ValueExpr) bool {
+	switch v.Kind() {
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+		return true
+	}
+	return false
+}
+
 func (p *preprocessor) checkAdminCheckTableGrammar(stmt *ast.AdminStmt) {
 	for _, table := range stmt.Tables {
 		tableInfo, err := p.tableByName(table)
@@ -864,7 +899,7 @@ func (p *preprocessor) checkDropTableGrammar(stmt *ast.DropTableStmt) {
 			p.err = err
 			return
 		}
-		if tableInfo.Meta().TempTableType != model.TempTableGlobal {
+		if !tableutil.IsGlobalTemporaryTable(tableInfo.Meta().TempTableType) {
 			p.err = ErrDropTableOnTemporaryTable
 			return
 		}
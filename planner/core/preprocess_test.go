@@ -299,8 +299,13 @@ func (s *testValidatorSuite) TestValidator(c *C) {
 		// TABLESAMPLE
 		{"select * from t tablesample bernoulli();", false, expression.ErrInvalidTableSample},
 		{"select * from t tablesample bernoulli(10 rows);", false, expression.ErrInvalidTableSample},
-		{"select * from t tablesample bernoulli(23 percent) repeatable (23);", false, expression.ErrInvalidTableSample},
+		{"select * from t tablesample bernoulli(23 percent) repeatable (23);", false, nil},
+		{"select * from t tablesample system(10.5);", false, nil},
 		{"select * from t tablesample system() repeatable (10);", false, expression.ErrInvalidTableSample},
+		{"select * from t tablesample system(101 percent);", false, expression.ErrInvalidTableSample},
+		{"select * from t tablesample system(-1 percent);", false, expression.ErrInvalidTableSample},
+		{"select * from t tablesample bernoulli(10 percent) repeatable (a);", false, expression.ErrInvalidTableSample},
+		{"select * from t tablesample regions();", false, nil},
 	}
 
 	_, err := s.se.Execute(context.Background(), "use test")
@@ -447,6 +447,12 @@ func (p *PhysicalWindow) ResolveIndices() (err error) {
 				return err
 			}
 		}
+		for _, byItem := range desc.OrderByItems {
+			byItem.Expr, err = byItem.Expr.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
 	}
 	if p.Frame != nil {
 		for i := range p.Frame.Start.CalcFuncs {
@@ -449,6 +449,9 @@ func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*
 		for _, arg := range desc.Args {
 			parentUsedCols = append(parentUsedCols, expression.ExtractColumns(arg)...)
 		}
+		for _, byItem := range desc.OrderByItems {
+			parentUsedCols = append(parentUsedCols, expression.ExtractColumns(byItem.Expr)...)
+		}
 	}
 	for _, by := range p.PartitionBy {
 		parentUsedCols = append(parentUsedCols, by.Col)
@@ -274,6 +274,9 @@ func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column
 		for _, arg := range desc.Args {
 			ResolveExprAndReplace(arg, replace)
 		}
+		for _, byItem := range desc.OrderByItems {
+			ResolveExprAndReplace(byItem.Expr, replace)
+		}
 	}
 	for _, item := range p.PartitionBy {
 		resolveColumnAndReplace(item.Col, replace)
@@ -270,6 +270,81 @@ func (s *partitionProcessor) pruneHashPartition(ctx sessionctx.Context, tbl tabl
 	return used, nil
 }
 
+// pruneKeyPartition locates the KEY partitions by the point ranges of the partitioning columns,
+// all the partitions are used unless every partitioning column is fixed by the conditions.
+func (s *partitionProcessor) pruneKeyPartition(ctx sessionctx.Context, tbl table.Table, partitionNames []model.CIStr,
+	conds []expression.Expression, columns []*expression.Column) ([]int, error) {
+	pi := tbl.Meta().Partition
+	partCols := make([]*expression.Column, 0, len(pi.Columns))
+	colLen := make([]int, 0, len(pi.Columns))
+	fts := make([]*types.FieldType, 0, len(pi.Columns))
+	for i, name := range pi.Columns {
+		colInfo := model.FindColumnInfo(tbl.Meta().Cols(), name.L)
+		if colInfo == nil {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(name.O, "partition function")
+		}
+		var partCol *expression.Column
+		for _, col := range columns {
+			if col.ID == colInfo.ID {
+				partCol = col.Clone().(*expression.Column)
+				break
+			}
+		}
+		if partCol == nil {
+			return s.convertToIntSlice(fullRange(len(pi.Definitions)), pi, partitionNames), nil
+		}
+		partCol.Index = i
+		partCols = append(partCols, partCol)
+		colLen = append(colLen, types.UnspecifiedLength)
+		fts = append(fts, partCol.RetType)
+	}
+	detachedResult, err := ranger.DetachCondAndBuildRangeForPartition(ctx, conds, partCols, colLen)
+	if err != nil {
+		return nil, err
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	used := make([]int, 0, len(detachedResult.Ranges))
+	for _, r := range detachedResult.Ranges {
+		if len(r.LowVal) != len(partCols) || !r.IsPointNullable(sc) {
+			used = []int{FullRange}
+			break
+		}
+		idx, err := tables.LocateKeyPartition(sc, pi.Num, fts, r.LowVal)
+		if err != nil {
+			used = []int{FullRange}
+			break
+		}
+		if len(partitionNames) > 0 && !s.findByName(partitionNames, pi.Definitions[idx].Name.L) {
+			continue
+		}
+		used = append(used, idx)
+	}
+	if len(used) == 1 && used[0] == FullRange {
+		return s.convertToIntSlice(fullRange(len(pi.Definitions)), pi, partitionNames), nil
+	}
+	sort.Ints(used)
+	ret := used[:0]
+	for i := 0; i < len(used); i++ {
+		if i == 0 || used[i] != used[i-1] {
+			ret = append(ret, used[i])
+		}
+	}
+	return ret, nil
+}
+
+func (s *partitionProcessor) processKeyPartition(ds *DataSource, pi *model.PartitionInfo) (LogicalPlan, error) {
+	used, err := s.pruneKeyPartition(ds.SCtx(), ds.table, ds.partitionNames, ds.allConds, ds.TblCols)
+	if err != nil {
+		return nil, err
+	}
+	if len(used) > 0 {
+		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi))
+	}
+	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
+	tableDual.schema = ds.Schema()
+	return tableDual, nil
+}
+
 // reconstructTableColNames reconstructs FieldsNames according to ds.TblCols.
 // ds.names may not match ds.TblCols since ds.names is pruned while ds.TblCols contains all original columns.
 // please see https://github.com/pingcap/tidb/issues/22635 for more details.
@@ -621,6 +696,8 @@ func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
 		return s.processRangePartition(ds, pi)
 	case model.PartitionTypeHash:
 		return s.processHashPartition(ds, pi)
+	case model.PartitionTypeKey:
+		return s.processKeyPartition(ds, pi)
 	case model.PartitionTypeList:
 		return s.processListPartition(ds, pi)
 	}
@@ -79,6 +79,26 @@ func (p *LogicalShow) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	return p.stats, nil
 }
 
+// jsonTableDefaultRowCount is the estimated row count of JSON_TABLE, the rows of a JSON document are unknown
+// until it's evaluated.
+const jsonTableDefaultRowCount = 10
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	profile := &property.StatsInfo{
+		RowCount:    jsonTableDefaultRowCount,
+		Cardinality: make(map[int64]float64, selfSchema.Len()),
+	}
+	for _, col := range selfSchema.Columns {
+		profile.Cardinality[col.UniqueID] = jsonTableDefaultRowCount
+	}
+	p.stats = profile
+	return p.stats, nil
+}
+
 func getFakeStats(schema *expression.Schema) *property.StatsInfo {
 	profile := &property.StatsInfo{
 		RowCount:    1,
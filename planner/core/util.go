@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/pingcap/tidb/util/tableutil"
 )
 
 // AggregateFuncExtractor visits Expr tree.
@@ -295,6 +296,10 @@ func extractStringFromStringSet(set set.StringSet) string {
 }
 
 func tableHasDirtyContent(ctx sessionctx.Context, tableInfo *model.TableInfo) bool {
+	// The committed rows of some temporary tables are kept in the session, they can only be read by UnionScan.
+	if tableutil.KeepsSessionData(tableInfo.TempTableType) && ctx.GetSessionVars().TemporaryTableData != nil {
+		return true
+	}
 	pi := tableInfo.GetPartitionInfo()
 	if pi == nil {
 		return ctx.HasDirtyContent(tableInfo.ID)
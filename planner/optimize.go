@@ -220,6 +220,14 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	return bestPlan, names, nil
 }
 
+// OptimizeWithCost builds the best physical plan of the node and returns its estimated cost.
+// Unlike Optimize, it neither tries the fast plan nor applies plan bindings, so the cost
+// always comes from the cost model. The node must be prepared first.
+func OptimizeWithCost(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, float64, error) {
+	p, _, cost, err := optimize(ctx, sctx, node, is)
+	return p, cost, err
+}
+
 func optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, float64, error) {
 	// build logical plan
 	sctx.GetSessionVars().PlanID = 0
@@ -365,6 +373,27 @@ func getBindRecord(ctx sessionctx.Context, stmt ast.StmtNode) (*bindinfo.BindRec
 	return bindRecord, metrics.ScopeGlobal, nil
 }
 
+// GetBindSQL4PlanCache returns the bind SQLs of the using bindings which may be applied to the statement in Optimize.
+// The prepared plan cache keeps it along with the cached plans, so that the cached plans are rebuilt once the
+// bindings are created, dropped or evolved.
+func GetBindSQL4PlanCache(sctx sessionctx.Context, stmt ast.StmtNode) string {
+	sessVars := sctx.GetSessionVars()
+	if !sessVars.UsePlanBaselines || sessVars.SelectLimit != math.MaxUint64 {
+		return ""
+	}
+	bindRecord, _, err := getBindRecord(sctx, stmt)
+	if err != nil || bindRecord == nil {
+		return ""
+	}
+	bindSQLs := make([]string, 0, len(bindRecord.Bindings))
+	for _, binding := range bindRecord.Bindings {
+		if binding.Status == bindinfo.Using {
+			bindSQLs = append(bindSQLs, binding.BindSQL)
+		}
+	}
+	return strings.Join(bindSQLs, ";")
+}
+
 func handleInvalidBindRecord(ctx context.Context, sctx sessionctx.Context, level string, bindRecord bindinfo.BindRecord) {
 	sessionHandle := sctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle)
 	err := sessionHandle.DropBindRecord(bindRecord.OriginalSQL, bindRecord.Db, &bindRecord.Bindings[0])
@@ -570,4 +599,5 @@ func setFoundInBinding(sctx sessionctx.Context, opt bool) error {
 func init() {
 	plannercore.OptimizeAstNode = Optimize
 	plannercore.IsReadOnly = IsReadOnly
+	plannercore.GetBindSQL4PlanCache = GetBindSQL4PlanCache
 }
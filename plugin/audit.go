@@ -82,6 +82,7 @@ type AuditManifest struct {
 	// return error will ignore and close current connection.
 	OnConnectionEvent func(ctx context.Context, event ConnectionEvent, info *variable.ConnectionInfo) error
 	// OnGeneralEvent will be called during TiDB execution.
+	// The query attributes sent by the client with the statement are in sctx.QueryAttributes.
 	OnGeneralEvent func(ctx context.Context, sctx *variable.SessionVars, event GeneralEvent, cmd string)
 	// OnGlobalVariableEvent will be called when Change GlobalVariable.
 	OnGlobalVariableEvent func(ctx context.Context, sctx *variable.SessionVars, varName, varValue string)
@@ -15,6 +15,7 @@ package privilege
 
 import (
 	"crypto/tls"
+	"time"
 
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
@@ -54,6 +55,8 @@ type Manager interface {
 	RequestDynamicVerificationWithUser(privName string, grantable bool, user *auth.UserIdentity) bool
 
 	// ConnectionVerification verifies user privilege for connection.
+	// For caching_sha2_password and sha256_password, the salt is nil if auth is the cleartext password,
+	// otherwise auth is the scramble of the fast authentication of caching_sha2_password.
 	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, bool)
 
 	// GetAuthWithoutVerification uses to get auth name without verification.
@@ -83,6 +86,37 @@ type Manager interface {
 
 	// Get the authentication plugin for a user
 	GetAuthPlugin(user, host string) (string, error)
+
+	// GetPasswordPolicy returns the password management policy of the account identified by the user and host.
+	GetPasswordPolicy(user, host string) (PasswordPolicy, bool)
+}
+
+// PasswordPolicy is the password management policy of an account.
+type PasswordPolicy struct {
+	// Expired is true if the password has been expired manually.
+	Expired bool
+	// LastChanged is the time when the password was changed last time.
+	LastChanged time.Time
+	// Lifetime is the number of days the password is valid for, 0 means the password never expires.
+	// It's -1 if default_password_lifetime is used.
+	Lifetime int64
+	// ReuseHistory is the number of the most recent passwords which can't be reused.
+	// It's -1 if password_history is used.
+	ReuseHistory int64
+	// ReuseInterval is the number of days in which the passwords can't be reused.
+	// It's -1 if password_reuse_interval is used.
+	ReuseInterval int64
+	// FailedLoginAttempts is the number of consecutive failed logins which lock the account.
+	FailedLoginAttempts int64
+	// LockTime is the number of days the account is locked for after too many failed logins,
+	// -1 means the account is locked until it's unlocked by ALTER USER ... ACCOUNT UNLOCK.
+	// The account is never locked if either FailedLoginAttempts or LockTime is 0.
+	LockTime int64
+}
+
+// LockingEnabled returns whether the account is locked after too many consecutive failed logins.
+func (p *PasswordPolicy) LockingEnabled() bool {
+	return p.FailedLoginAttempts > 0 && p.LockTime != 0
 }
 
 const key keyType = 0
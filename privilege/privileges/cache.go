@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -64,6 +65,9 @@ const (
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
 	account_locked,plugin FROM mysql.user`
+	// The password management columns are loaded separately, they're absent if mysql.user is synchronized from MySQL.
+	sqlLoadPasswordPolicy = `SELECT HIGH_PRIORITY Host,User,password_expired,password_last_changed,password_lifetime,
+	Password_reuse_history,Password_reuse_time,Failed_login_attempts,Password_lock_time FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -97,6 +101,7 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+	PasswordPolicy       privilege.PasswordPolicy
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -378,6 +383,16 @@ func noSuchTable(err error) bool {
 	return false
 }
 
+func noSuchColumn(err error) bool {
+	e1 := errors.Cause(err)
+	if e2, ok := e1.(*terror.Error); ok {
+		if terror.ErrCode(e2.Code()) == terror.ErrCode(mysql.ErrBadField) {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadRoleGraph loads the mysql.role_edges table from database.
 func (p *MySQLPrivilege) LoadRoleGraph(ctx sessionctx.Context) error {
 	p.RoleGraph = make(map[string]roleGraphEdgesTable)
@@ -394,6 +409,21 @@ func (p *MySQLPrivilege) LoadUserTable(ctx sessionctx.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	policies := make(map[string]privilege.PasswordPolicy, len(p.User))
+	err = p.loadTable(ctx, sqlLoadPasswordPolicy, func(row chunk.Row, fs []*ast.ResultField) error {
+		return decodePasswordPolicyRow(row, fs, policies)
+	})
+	if err != nil {
+		if !noSuchColumn(err) {
+			return errors.Trace(err)
+		}
+		logutil.BgLogger().Warn("mysql.user has no password management columns", zap.Error(err))
+	}
+	for i := range p.User {
+		if policy, ok := policies[p.User[i].User+"@"+p.User[i].Host]; ok {
+			p.User[i].PasswordPolicy = policy
+		}
+	}
 	// See https://dev.mysql.com/doc/refman/8.0/en/connection-access.html
 	// When multiple matches are possible, the server must determine which of them to use. It resolves this issue as follows:
 	// 1. Whenever the server reads the user table into memory, it sorts the rows.
@@ -625,6 +655,7 @@ func (record *baseRecord) assignUserOrHost(row chunk.Row, i int, f *ast.ResultFi
 
 func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField) error {
 	var value UserRecord
+	value.PasswordPolicy = privilege.PasswordPolicy{Lifetime: -1, ReuseHistory: -1, ReuseInterval: -1}
 	for i, f := range fs {
 		switch {
 		case f.ColumnAsName.L == "authentication_string":
@@ -656,6 +687,50 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 	return nil
 }
 
+func decodePasswordPolicyRow(row chunk.Row, fs []*ast.ResultField, policies map[string]privilege.PasswordPolicy) error {
+	var user, host string
+	var policy privilege.PasswordPolicy
+	for i, f := range fs {
+		switch f.ColumnAsName.L {
+		case "user":
+			user = row.GetString(i)
+		case "host":
+			host = row.GetString(i)
+		case "password_expired":
+			policy.Expired = row.GetEnum(i).String() == "Y"
+		case "password_last_changed":
+			if row.IsNull(i) {
+				continue
+			}
+			var err error
+			policy.LastChanged, err = row.GetTime(i).GoTime(time.Local)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		case "password_lifetime":
+			policy.Lifetime = decodeNullableInt(row, i)
+		case "password_reuse_history":
+			policy.ReuseHistory = decodeNullableInt(row, i)
+		case "password_reuse_time":
+			policy.ReuseInterval = decodeNullableInt(row, i)
+		case "failed_login_attempts":
+			policy.FailedLoginAttempts = int64(row.GetUint64(i))
+		case "password_lock_time":
+			policy.LockTime = row.GetInt64(i)
+		}
+	}
+	policies[user+"@"+host] = policy
+	return nil
+}
+
+// decodeNullableInt decodes the unsigned integer column, NULL is decoded as -1.
+func decodeNullableInt(row chunk.Row, i int) int64 {
+	if row.IsNull(i) {
+		return -1
+	}
+	return int64(row.GetUint64(i))
+}
+
 func (p *MySQLPrivilege) decodeGlobalPrivTableRow(row chunk.Row, fs []*ast.ResultField) error {
 	var value globalPrivRecord
 	for i, f := range fs {
@@ -1497,6 +1572,8 @@ func (p *MySQLPrivilege) getAllRoles(user, host string) []*auth.RoleIdentity {
 // Handle wraps MySQLPrivilege providing thread safe access.
 type Handle struct {
 	priv atomic.Value
+	// sha2Cache is kept across the reloading of privileges.
+	sha2Cache sha2PasswordCache
 }
 
 // NewHandle returns a Handle.
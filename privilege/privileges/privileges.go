@@ -177,11 +177,11 @@ func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 		return false
 	}
 
-	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+	if record.AuthPlugin == mysql.AuthCachingSha2Password || record.AuthPlugin == AuthSha256Password {
 		if len(pwd) == mysql.SHAPWDHashLen {
 			return true
 		}
-		logutil.BgLogger().Error("user password from system DB not like a caching_sha2_password format", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin), zap.Int("hash_length", len(pwd)))
+		logutil.BgLogger().Error("user password from system DB not like a sha2 password format", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin), zap.Int("hash_length", len(pwd)))
 		return false
 	}
 
@@ -220,6 +220,20 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	return "", errors.New("Failed to get plugin for user")
 }
 
+// GetPasswordPolicy implements the Manager interface.
+func (p *UserPrivileges) GetPasswordPolicy(user, host string) (privilege.PasswordPolicy, bool) {
+	if SkipWithGrant {
+		return privilege.PasswordPolicy{}, false
+	}
+	mysqlPriv := p.Handle.Get()
+	for _, record := range mysqlPriv.UserMap[user] {
+		if record.Host == host {
+			return record.PasswordPolicy, true
+		}
+	}
+	return privilege.PasswordPolicy{}, false
+}
+
 // GetAuthWithoutVerification implements the Manager interface.
 func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -312,12 +326,20 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 			return
 		}
 	} else if record.AuthPlugin == mysql.AuthCachingSha2Password {
-		authok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
-		if err != nil {
-			logutil.BgLogger().Error("Failed to check caching_sha2_password", zap.Error(err))
+		// The salt is nil if the client sends the cleartext password by the full authentication,
+		// otherwise the authentication is the scramble of the fast authentication.
+		if salt != nil {
+			if !p.sha2Cache.verify(record.User, record.Host, pwd, authentication, salt) {
+				return
+			}
+		} else {
+			if !checkShaPassword(record.AuthPlugin, pwd, authentication) {
+				return
+			}
+			p.sha2Cache.add(record.User, record.Host, pwd, authentication)
 		}
-
-		if !authok {
+	} else if record.AuthPlugin == AuthSha256Password {
+		if salt != nil || !checkShaPassword(record.AuthPlugin, pwd, authentication) {
 			return
 		}
 	} else {
@@ -331,6 +353,14 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	return
 }
 
+func checkShaPassword(plugin, pwd string, authentication []byte) bool {
+	authok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
+	if err != nil {
+		logutil.BgLogger().Error("Failed to check "+plugin, zap.Error(err))
+	}
+	return authok
+}
+
 type checkResult int
 
 const (
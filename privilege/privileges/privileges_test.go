@@ -16,6 +16,7 @@ package privileges_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -1745,3 +1746,84 @@ func (s *testPrivilegeSuite) TestDynamicPrivsRegistration(c *C) {
 		tk.MustExec(sqlGrant)
 	}
 }
+
+// scrambleSha2Password returns the scramble of caching_sha2_password sent by the client for the fast authentication.
+func scrambleSha2Password(password string, salt []byte) []byte {
+	stage1 := sha256.Sum256([]byte(password))
+	stage2 := sha256.Sum256(stage1[:])
+	h := sha256.New()
+	h.Write(stage2[:])
+	h.Write(salt)
+	scramble := h.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+func (s *testPrivilegeSuite) TestSha2PasswordAuth(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `CREATE USER 'sha2user'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'sha2pass'`)
+	mustExec(c, rootSe, `CREATE USER 'sha256user'@'localhost' IDENTIFIED WITH 'sha256_password' BY 'sha256pass'`)
+	salt := []byte("abcdefghijklmnopqrst")
+
+	se := newSession(c, s.store, s.dbName)
+	user := &auth.UserIdentity{Username: "sha2user", Hostname: "localhost"}
+	// The fast authentication fails until the user passes the full authentication.
+	c.Assert(se.Auth(user, scrambleSha2Password("sha2pass", salt), salt), IsFalse)
+	c.Assert(se.Auth(user, []byte("wrongpass"), nil), IsFalse)
+	c.Assert(se.Auth(user, []byte("sha2pass"), nil), IsTrue)
+	c.Assert(se.Auth(user, scrambleSha2Password("sha2pass", salt), salt), IsTrue)
+	c.Assert(se.Auth(user, scrambleSha2Password("sha2pass", []byte("01234567890123456789")), salt), IsFalse)
+	c.Assert(se.Auth(user, scrambleSha2Password("wrongpass", salt), salt), IsFalse)
+
+	// The cached password is stale once the password is changed.
+	mustExec(c, rootSe, `ALTER USER 'sha2user'@'localhost' IDENTIFIED BY 'newpass'`)
+	c.Assert(se.Auth(user, scrambleSha2Password("sha2pass", salt), salt), IsFalse)
+	c.Assert(se.Auth(user, scrambleSha2Password("newpass", salt), salt), IsFalse)
+	c.Assert(se.Auth(user, []byte("newpass"), nil), IsTrue)
+	c.Assert(se.Auth(user, scrambleSha2Password("newpass", salt), salt), IsTrue)
+
+	// sha256_password always requires the cleartext password.
+	user = &auth.UserIdentity{Username: "sha256user", Hostname: "localhost"}
+	c.Assert(se.Auth(user, []byte("sha256pass"), nil), IsTrue)
+	c.Assert(se.Auth(user, scrambleSha2Password("sha256pass", salt), salt), IsFalse)
+	c.Assert(se.Auth(user, []byte("wrongpass"), nil), IsFalse)
+	mustExec(c, rootSe, `SET PASSWORD FOR 'sha256user'@'localhost' = 'newpass'`)
+	c.Assert(se.Auth(user, []byte("sha256pass"), nil), IsFalse)
+	c.Assert(se.Auth(user, []byte("newpass"), nil), IsTrue)
+}
+
+func (s *testPrivilegeSuite) TestPasswordPolicy(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `CREATE USER 'policyuser'@'localhost' IDENTIFIED BY 'pass' PASSWORD EXPIRE INTERVAL 30 DAY`)
+	pc := privilege.GetPrivilegeManager(rootSe)
+	policy, ok := pc.GetPasswordPolicy("policyuser", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(policy.Expired, IsFalse)
+	c.Assert(policy.LastChanged.IsZero(), IsFalse)
+	c.Assert(policy.Lifetime, Equals, int64(30))
+	c.Assert(policy.ReuseHistory, Equals, int64(-1))
+	c.Assert(policy.ReuseInterval, Equals, int64(-1))
+	c.Assert(policy.LockingEnabled(), IsFalse)
+
+	// The host is matched exactly.
+	_, ok = pc.GetPasswordPolicy("policyuser", "%")
+	c.Assert(ok, IsFalse)
+
+	mustExec(c, rootSe, `ALTER USER 'policyuser'@'localhost' PASSWORD EXPIRE`)
+	mustExec(c, rootSe, `UPDATE mysql.user SET Failed_login_attempts = 3, Password_lock_time = -1 WHERE User = 'policyuser'`)
+	mustExec(c, rootSe, `FLUSH PRIVILEGES`)
+	policy, ok = pc.GetPasswordPolicy("policyuser", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(policy.Expired, IsTrue)
+	c.Assert(policy.FailedLoginAttempts, Equals, int64(3))
+	c.Assert(policy.LockTime, Equals, int64(-1))
+	c.Assert(policy.LockingEnabled(), IsTrue)
+
+	// Changing the password clears the expired flag.
+	mustExec(c, rootSe, `ALTER USER 'policyuser'@'localhost' IDENTIFIED BY 'newpass'`)
+	policy, ok = pc.GetPasswordPolicy("policyuser", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(policy.Expired, IsFalse)
+}
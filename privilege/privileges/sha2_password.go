@@ -0,0 +1,84 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"sync"
+)
+
+// AuthSha256Password is the sha256_password authentication plugin. The password is stored in the same format as
+// caching_sha2_password, but the client always has to send the password in cleartext over a secure connection
+// or encrypted by the RSA public key of the server.
+const AuthSha256Password = "sha256_password"
+
+// sha2PasswordCache caches the digests of the passwords which have passed the full authentication of
+// caching_sha2_password, the following connections of the same account can be authenticated by the scramble
+// of the password, which is the fast authentication.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_caching_sha2_authentication_exchanges.html.
+type sha2PasswordCache struct {
+	mu      sync.RWMutex
+	entries map[string]sha2PasswordCacheEntry
+}
+
+type sha2PasswordCacheEntry struct {
+	// authString is the stored hash of the password, the entry is stale once the password is changed.
+	authString string
+	// digest is SHA256(SHA256(password)).
+	digest [sha256.Size]byte
+}
+
+func sha2PasswordCacheKey(user, host string) string {
+	return user + "@" + host
+}
+
+// add caches the digest of the cleartext password of the account.
+func (c *sha2PasswordCache) add(user, host, authString string, password []byte) {
+	stage1 := sha256.Sum256(password)
+	entry := sha2PasswordCacheEntry{
+		authString: authString,
+		digest:     sha256.Sum256(stage1[:]),
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]sha2PasswordCacheEntry)
+	}
+	c.entries[sha2PasswordCacheKey(user, host)] = entry
+	c.mu.Unlock()
+}
+
+// verify checks the scramble sent by the client, which is XOR(SHA256(password), SHA256(SHA256(SHA256(password)), salt)).
+// It fails if the account has not passed the full authentication since its password was set.
+func (c *sha2PasswordCache) verify(user, host, authString string, scramble, salt []byte) bool {
+	if len(scramble) != sha256.Size {
+		return false
+	}
+	c.mu.RLock()
+	entry, ok := c.entries[sha2PasswordCacheKey(user, host)]
+	c.mu.RUnlock()
+	if !ok || entry.authString != authString {
+		return false
+	}
+
+	h := sha256.New()
+	h.Write(entry.digest[:])
+	h.Write(salt)
+	stage1 := h.Sum(nil)
+	for i := range stage1 {
+		stage1[i] ^= scramble[i]
+	}
+	digest := sha256.Sum256(stage1)
+	return subtle.ConstantTimeCompare(digest[:], entry.digest[:]) == 1
+}
@@ -0,0 +1,266 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/metrics"
+)
+
+// The compression algorithms of the compressed protocol.
+const (
+	compressionZlib = iota + 1
+	compressionZstd
+)
+
+const (
+	// minCompressLength is the minimal length of the payload to be compressed, smaller payloads are sent
+	// without compression, which is the same as MySQL.
+	minCompressLength = 50
+	// compressedHeaderLen is the length of the header of a compressed packet.
+	compressedHeaderLen = 7
+	// zlibCompressionLevel is the compression level of zlib, the level can't be specified by the client.
+	zlibCompressionLevel = zlib.DefaultCompression
+	// defaultZstdCompressionLevel is the compression level of zstd when the client doesn't specify it.
+	defaultZstdCompressionLevel = 3
+	minZstdCompressionLevel     = 1
+	maxZstdCompressionLevel     = 22
+)
+
+var (
+	readCompressedBytes    = metrics.CompressedPacketIOCounter.WithLabelValues("read_compressed")
+	readUncompressedBytes  = metrics.CompressedPacketIOCounter.WithLabelValues("read_uncompressed")
+	writeCompressedBytes   = metrics.CompressedPacketIOCounter.WithLabelValues("write_compressed")
+	writeUncompressedBytes = metrics.CompressedPacketIOCounter.WithLabelValues("write_uncompressed")
+)
+
+var (
+	// zstdDecoder is shared by all the connections, DecodeAll of zstd.Decoder is safe for concurrent use.
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+	zstdDecoderOnce sync.Once
+
+	// zstdEncoders caches the encoders by their levels, EncodeAll of zstd.Encoder is safe for concurrent use.
+	zstdEncoders sync.Map
+)
+
+func getZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		// The uncompressed length of a compressed packet never exceeds MaxPayloadLen, limiting the decoded size
+		// prevents a small malicious payload from being decompressed into a huge buffer.
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(uint64(mysql.MaxPayloadLen)))
+	})
+	return zstdDecoder, zstdDecoderErr
+}
+
+func getZstdEncoder(level int) (*zstd.Encoder, error) {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+	if encoder, ok := zstdEncoders.Load(encoderLevel); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	actual, loaded := zstdEncoders.LoadOrStore(encoderLevel, encoder)
+	if loaded {
+		if err = encoder.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return actual.(*zstd.Encoder), nil
+}
+
+// compressedIO reads and writes the compressed packets, which wrap the MySQL packets and are described in
+// https://dev.mysql.com/doc/internals/en/compressed-packet-header.html.
+type compressedIO struct {
+	pkt       *packetIO
+	algorithm int
+	level     int
+	// sequence is the sequence of compressed packets, which is independent of the sequence of the wrapped packets.
+	sequence uint8
+
+	// readBuf holds the decompressed data which has not been read yet.
+	readBuf []byte
+	// writeBuf holds the MySQL packets which have not been compressed yet.
+	writeBuf   []byte
+	zlibWriter *zlib.Writer
+}
+
+func newCompressedIO(pkt *packetIO, algorithm int, level int) *compressedIO {
+	return &compressedIO{
+		pkt:       pkt,
+		algorithm: algorithm,
+		level:     level,
+	}
+}
+
+// Read implements io.Reader interface, it reads the decompressed data.
+func (c *compressedIO) Read(b []byte) (int, error) {
+	for len(c.readBuf) == 0 {
+		if err := c.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+func (c *compressedIO) readCompressedPacket() error {
+	var header [compressedHeaderLen]byte
+	if _, err := io.ReadFull(c.pkt.bufReadConn, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	compressedLength := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	sequence := header[3]
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	if sequence != c.sequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, c.sequence)
+	}
+	c.sequence++
+
+	data := make([]byte, compressedLength)
+	if _, err := io.ReadFull(c.pkt.bufReadConn, data); err != nil {
+		return errors.Trace(err)
+	}
+	readCompressedBytes.Add(float64(compressedLength + compressedHeaderLen))
+	// The uncompressed length is 0 if the payload is not compressed.
+	if uncompressedLength == 0 {
+		readUncompressedBytes.Add(float64(compressedLength))
+		c.readBuf = data
+		return nil
+	}
+
+	var err error
+	switch c.algorithm {
+	case compressionZlib:
+		var r io.ReadCloser
+		if r, err = zlib.NewReader(bytes.NewReader(data)); err == nil {
+			c.readBuf = make([]byte, uncompressedLength)
+			_, err = io.ReadFull(r, c.readBuf)
+			if err1 := r.Close(); err == nil {
+				err = err1
+			}
+		}
+	case compressionZstd:
+		var decoder *zstd.Decoder
+		if decoder, err = getZstdDecoder(); err == nil {
+			c.readBuf, err = decoder.DecodeAll(data, make([]byte, 0, uncompressedLength))
+		}
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(c.readBuf) != uncompressedLength {
+		return errors.Trace(mysql.ErrMalformPacket)
+	}
+	readUncompressedBytes.Add(float64(uncompressedLength))
+	return nil
+}
+
+// Write implements io.Writer interface, the data is buffered until flush or the buffer is large enough
+// to fill a compressed packet.
+func (c *compressedIO) Write(data []byte) (int, error) {
+	c.writeBuf = append(c.writeBuf, data...)
+	for len(c.writeBuf) >= mysql.MaxPayloadLen {
+		if err := c.writeCompressedPacket(c.writeBuf[:mysql.MaxPayloadLen]); err != nil {
+			return 0, err
+		}
+		c.writeBuf = c.writeBuf[mysql.MaxPayloadLen:]
+	}
+	return len(data), nil
+}
+
+// flush compresses the buffered data and writes it to the buffered writer of the connection.
+func (c *compressedIO) flush() error {
+	if len(c.writeBuf) > 0 {
+		if err := c.writeCompressedPacket(c.writeBuf); err != nil {
+			return err
+		}
+		c.writeBuf = c.writeBuf[:0]
+	}
+	return nil
+}
+
+func (c *compressedIO) writeCompressedPacket(payload []byte) error {
+	uncompressedLength := len(payload)
+	compressed, err := c.compress(payload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Send the original payload if compressing doesn't make it smaller.
+	if compressed == nil || len(compressed) >= len(payload) {
+		compressed = payload
+		uncompressedLength = 0
+	}
+
+	header := [compressedHeaderLen]byte{
+		byte(len(compressed)), byte(len(compressed) >> 8), byte(len(compressed) >> 16),
+		c.sequence,
+		byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+	}
+	if _, err := c.pkt.bufWriter.Write(header[:]); err != nil {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	if _, err := c.pkt.bufWriter.Write(compressed); err != nil {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	c.sequence++
+	writeUncompressedBytes.Add(float64(len(payload)))
+	writeCompressedBytes.Add(float64(len(compressed) + compressedHeaderLen))
+	return nil
+}
+
+// compress returns nil if the payload is too short to be compressed.
+func (c *compressedIO) compress(payload []byte) ([]byte, error) {
+	if len(payload) < minCompressLength {
+		return nil, nil
+	}
+	switch c.algorithm {
+	case compressionZlib:
+		var buf bytes.Buffer
+		if c.zlibWriter == nil {
+			w, err := zlib.NewWriterLevel(&buf, c.level)
+			if err != nil {
+				return nil, err
+			}
+			c.zlibWriter = w
+		} else {
+			c.zlibWriter.Reset(&buf)
+		}
+		if _, err := c.zlibWriter.Write(payload); err != nil {
+			return nil, err
+		}
+		if err := c.zlibWriter.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionZstd:
+		encoder, err := getZstdEncoder(c.level)
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(payload, nil), nil
+	}
+	return nil, nil
+}
@@ -62,6 +62,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
@@ -70,6 +71,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -83,6 +85,7 @@ import (
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
@@ -186,6 +189,7 @@ type clientConn struct {
 	lastActive   time.Time         // last active time
 	authPlugin   string            // default authentication plugin
 	isUnixSocket bool              // connection is Unix Socket file
+	zstdLevel    int               // compression level of zstd requested by client.
 
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
@@ -269,6 +273,10 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		}
 		return initErr
 	}
+	// The session states changed during the handshake are not reported to the client.
+	if cc.ctx != nil {
+		cc.ctx.GetSessionVars().StateTracker.Reset()
+	}
 
 	data := cc.alloc.AllocWithLen(4, 32)
 	data = append(data, mysql.OKHeader)
@@ -279,7 +287,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	}
 
 	err := cc.writePacket(data)
-	cc.pkt.sequence = 0
+	cc.pkt.resetSequence()
 	if err != nil {
 		err = errors.SuspendStack(err)
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -292,6 +300,13 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The compressed protocol is used after the handshake. zlib is preferred if the client supports both.
+	if cc.capability&mysql.ClientCompress > 0 {
+		cc.pkt.setCompression(compressionZlib, zlibCompressionLevel)
+	} else if cc.capability&clientZstdCompressionAlgorithm > 0 {
+		cc.pkt.setCompression(compressionZstd, cc.zstdLevel)
+	}
 	return err
 }
 
@@ -419,6 +434,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  int
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -510,6 +526,7 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 			err = mysql.ErrMalformPacket
 		}
 	}()
+	packet.ZstdLevel = defaultZstdCompressionLevel
 	// user name
 	packet.User = string(data[offset : offset+bytes.IndexByte(data[offset:], 0)])
 	offset += len(packet.User) + 1
@@ -565,12 +582,22 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 		if num, null, off := parseLengthEncodedInt(data[offset:]); !null {
 			offset += off
 			row := data[offset : offset+int(num)]
+			offset += int(num)
 			attrs, err := parseAttrs(row)
 			if err != nil {
 				logutil.Logger(ctx).Warn("parse attrs failed", zap.Error(err))
-				return nil
+			} else {
+				packet.Attrs = attrs
 			}
-			packet.Attrs = attrs
+		}
+	}
+
+	if packet.Capability&clientZstdCompressionAlgorithm > 0 && len(data[offset:]) > 0 {
+		level := int(data[offset])
+		if level >= minZstdCompressionLevel && level <= maxZstdCompressionLevel {
+			packet.ZstdLevel = level
+		} else {
+			logutil.Logger(ctx).Warn("invalid zstd compression level, use the default level", zap.Int("level", level))
 		}
 	}
 
@@ -678,6 +705,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
 	newAuth, err := cc.checkAuthPlugin(ctx, &resp.AuthPlugin)
 	if err != nil {
@@ -689,50 +717,20 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 
 	switch resp.AuthPlugin {
 	case mysql.AuthCachingSha2Password:
-		resp.Auth, err = cc.authSha(ctx)
-		if err != nil {
-			return err
-		}
+		err = cc.authCachingSha2Password(ctx, resp.Auth)
+	case privileges.AuthSha256Password:
+		err = cc.authSha256Password(ctx, resp.Auth)
 	case mysql.AuthNativePassword:
+		err = cc.openSessionAndDoAuth(resp.Auth, cc.salt)
 	default:
 		return errors.New("Unknown auth plugin")
 	}
-
-	err = cc.openSessionAndDoAuth(resp.Auth)
 	if err != nil {
 		logutil.Logger(ctx).Warn("open new session or authentication failure", zap.Error(err))
 	}
 	return err
 }
 
-func (cc *clientConn) authSha(ctx context.Context) ([]byte, error) {
-
-	const (
-		ShaCommand       = 1
-		RequestRsaPubKey = 2
-		FastAuthOk       = 3
-		FastAuthFail     = 4
-	)
-
-	err := cc.writePacket([]byte{0, 0, 0, 0, ShaCommand, FastAuthFail})
-	if err != nil {
-		logutil.Logger(ctx).Error("authSha packet write failed", zap.Error(err))
-		return nil, err
-	}
-	err = cc.flush(ctx)
-	if err != nil {
-		logutil.Logger(ctx).Error("authSha packet flush failed", zap.Error(err))
-		return nil, err
-	}
-
-	data, err := cc.readPacket()
-	if err != nil {
-		logutil.Logger(ctx).Error("authSha packet read failed", zap.Error(err))
-		return nil, err
-	}
-	return bytes.Trim(data, "\x00"), nil
-}
-
 func (cc *clientConn) SessionStatusToString() string {
 	status := cc.ctx.Status()
 	inTxn, autoCommit := 0, 0
@@ -766,7 +764,9 @@ func (cc *clientConn) openSession() error {
 	return nil
 }
 
-func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
+// openSessionAndDoAuth opens the session and authenticates the user, the salt is nil if authData is the
+// cleartext password of caching_sha2_password or sha256_password.
+func (cc *clientConn) openSessionAndDoAuth(authData []byte, salt []byte) error {
 	// Open a context unless this was done before.
 	if cc.ctx == nil {
 		err := cc.openSession()
@@ -783,7 +783,15 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	if err != nil {
 		return err
 	}
-	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
+	user := &auth.UserIdentity{Username: cc.user, Hostname: host}
+	authenticated := cc.ctx.Auth(user, authData, salt)
+	if authenticated {
+		user = cc.ctx.GetSessionVars().User
+	}
+	if err = cc.checkPasswordPolicy(user, authenticated, salt); err != nil {
+		return err
+	}
+	if !authenticated {
 		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
 	}
 	cc.ctx.SetPort(port)
@@ -797,6 +805,157 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	return nil
 }
 
+// checkPasswordPolicy enforces the password management policy of the account during the authentication.
+// The account is locked for PASSWORD_LOCK_TIME days after FAILED_LOGIN_ATTEMPTS consecutive failed logins,
+// and the session enters the sandbox mode if the password has expired.
+func (cc *clientConn) checkPasswordPolicy(user *auth.UserIdentity, authenticated bool, salt []byte) error {
+	pm := privilege.GetPrivilegeManager(cc.ctx)
+	if pm == nil || user.AuthUsername == "" && user.AuthHostname == "" {
+		return nil
+	}
+	policy, ok := pm.GetPasswordPolicy(user.AuthUsername, user.AuthHostname)
+	if !ok {
+		return nil
+	}
+	if policy.LockingEnabled() {
+		// The client falls back to the full authentication if the fast authentication of caching_sha2_password
+		// fails, so it's not counted.
+		countFailure := true
+		if salt != nil {
+			plugin, err := pm.GetAuthPlugin(user.AuthUsername, user.AuthHostname)
+			countFailure = err != nil || plugin != mysql.AuthCachingSha2Password
+		}
+		if err := cc.checkLoginFailures(user, &policy, authenticated, countFailure); err != nil {
+			return err
+		}
+	}
+	if !authenticated {
+		return nil
+	}
+
+	vars := cc.ctx.GetSessionVars()
+	expired, err := passwordExpired(vars, &policy)
+	if err != nil {
+		return err
+	}
+	vars.PasswordExpired = expired
+	// disconnect_on_expired_password is always ON, so the clients which can't handle the sandbox mode are rejected.
+	if expired && cc.capability&clientCanHandleExpiredPasswords == 0 {
+		return errMustChangePasswordLogin
+	}
+	return nil
+}
+
+// passwordExpired returns whether the password is expired manually or exceeds its lifetime.
+func passwordExpired(vars *variable.SessionVars, policy *privilege.PasswordPolicy) (bool, error) {
+	if policy.Expired {
+		return true, nil
+	}
+	lifetime := policy.Lifetime
+	if lifetime < 0 {
+		val, err := variable.GetGlobalSystemVar(vars, variable.DefaultPasswordLifetime)
+		if err != nil {
+			return false, err
+		}
+		if lifetime, err = strconv.ParseInt(val, 10, 64); err != nil {
+			lifetime = 0
+		}
+	}
+	if lifetime <= 0 || policy.LastChanged.IsZero() {
+		return false, nil
+	}
+	return time.Since(policy.LastChanged) >= time.Duration(lifetime)*24*time.Hour, nil
+}
+
+// checkLoginFailures rejects the login if the account is locked, and tracks the consecutive failed logins in
+// mysql.login_failures, which is shared by all TiDB instances. The row of the account is locked by a pessimistic
+// transaction, so the concurrent logins of the same account are counted one by one on all TiDB instances.
+func (cc *clientConn) checkLoginFailures(user *auth.UserIdentity, policy *privilege.PasswordPolicy, authenticated, countFailure bool) error {
+	ctx := context.Background()
+	pool := domain.GetDomain(cc.ctx.Session).SysSessionPool()
+	res, err := pool.Get()
+	if err != nil {
+		return err
+	}
+	res.(sessionctx.Context).GetSessionVars().InRestrictedSQL = true
+	exec := res.(sqlexec.SQLExecutor)
+	defer func() {
+		// It's a no-op if the transaction has been committed.
+		if _, err := exec.ExecuteInternal(ctx, "ROLLBACK"); err != nil {
+			res.Close()
+			return
+		}
+		pool.Put(res)
+	}()
+
+	if _, err = exec.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	rs, err := exec.ExecuteInternal(ctx, `SELECT Failed_count, Locked_time IS NOT NULL, TIMESTAMPDIFF(SECOND, NOW(), DATE_ADD(Locked_time, INTERVAL %? DAY))
+		FROM %n.%n WHERE User=%? AND Host=%? FOR UPDATE`, policy.LockTime, mysql.SystemDB, "login_failures", user.AuthUsername, user.AuthHostname)
+	if err != nil {
+		return err
+	}
+	rows, err := sqlexec.DrainRecordSet(ctx, rs, 8)
+	terror.Call(rs.Close)
+	if err != nil {
+		return err
+	}
+	var failedCount int64
+	if len(rows) > 0 {
+		failedCount = rows[0].GetInt64(0)
+		if rows[0].GetInt64(1) == 1 {
+			remaining := rows[0].GetInt64(2)
+			if policy.LockTime < 0 || remaining > 0 {
+				return accountBlockedError(user, policy, remaining)
+			}
+			// The lock has expired, so the failures are counted from scratch.
+			failedCount = 0
+		}
+	}
+
+	if authenticated {
+		if len(rows) == 0 {
+			return nil
+		}
+		if _, err = exec.ExecuteInternal(ctx, `DELETE FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, "login_failures", user.AuthUsername, user.AuthHostname); err != nil {
+			return err
+		}
+		_, err = exec.ExecuteInternal(ctx, "COMMIT")
+		return err
+	}
+	if !countFailure {
+		return nil
+	}
+
+	// The row is locked, so the count read above is the stored one and no failure is lost.
+	failedCount++
+	locked := failedCount >= policy.FailedLoginAttempts
+	if _, err = exec.ExecuteInternal(ctx, `INSERT INTO %n.%n (Host, User, Failed_count, Locked_time) VALUES (%?, %?, %?, IF(%?, NOW(), NULL))
+		ON DUPLICATE KEY UPDATE Failed_count = VALUES(Failed_count), Locked_time = VALUES(Locked_time)`,
+		mysql.SystemDB, "login_failures", user.AuthHostname, user.AuthUsername, failedCount, locked); err != nil {
+		return err
+	}
+	if _, err = exec.ExecuteInternal(ctx, "COMMIT"); err != nil {
+		return err
+	}
+	if locked {
+		logutil.BgLogger().Warn("account is locked due to consecutive failed logins",
+			zap.String("user", user.AuthUsername), zap.String("host", user.AuthHostname), zap.Int64("failed logins", failedCount))
+		return accountBlockedError(user, policy, policy.LockTime*24*3600)
+	}
+	return nil
+}
+
+func accountBlockedError(user *auth.UserIdentity, policy *privilege.PasswordPolicy, remainingSeconds int64) error {
+	lockDays, remainingDays := "unlimited", "unlimited"
+	if policy.LockTime > 0 {
+		lockDays = strconv.FormatInt(policy.LockTime, 10)
+		remainingDays = strconv.FormatInt((remainingSeconds+24*3600-1)/(24*3600), 10)
+	}
+	return errAccountBlockedByPasswordLock.FastGenByArgs(user.AuthUsername, user.AuthHostname, lockDays, remainingDays, policy.FailedLoginAttempts)
+}
+
 // Check if the Authentication Plugin of the server, client and user configuration matches
 func (cc *clientConn) checkAuthPlugin(ctx context.Context, authPlugin *string) ([]byte, error) {
 	// Open a context unless this was done before.
@@ -856,9 +1015,13 @@ func (cc *clientConn) PeerHost(hasPassword string) (host, port string, err error
 // skipInitConnect follows MySQL's rules of when init-connect should be skipped.
 // In 5.7 it is any user with SUPER privilege, but in 8.0 it is:
 // - SUPER or the CONNECTION_ADMIN dynamic privilege.
-// - (additional exception) users with expired passwords (not yet supported)
+// - (additional exception) users with expired passwords
 // In TiDB CONNECTION_ADMIN is satisfied by SUPER, so we only need to check once.
 func (cc *clientConn) skipInitConnect() bool {
+	// init_connect is not executed in the sandbox mode.
+	if cc.ctx.GetSessionVars().PasswordExpired {
+		return true
+	}
 	checker := privilege.GetPrivilegeManager(cc.ctx.Session)
 	activeRoles := cc.ctx.GetSessionVars().ActiveRoles
 	return checker != nil && checker.RequestDynamicVerification(activeRoles, "CONNECTION_ADMIN", false)
@@ -1004,7 +1167,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 			terror.Log(err1)
 		}
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -1135,6 +1298,19 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	cc.lastPacket = data
 	cmd := data[0]
 	data = data[1:]
+	cc.ctx.GetSessionVars().QueryAttributes = nil
+	if cmd == mysql.ComQuery && cc.capability&clientQueryAttributes > 0 {
+		attrs, query, err := parseQueryAttributes(cc.ctx.GetSessionVars().StmtCtx, data)
+		if err != nil {
+			return err
+		}
+		cc.ctx.GetSessionVars().QueryAttributes = attrs
+		// Cut the query attributes off from the last packet which is used to log the query. The attributes
+		// take 2 bytes at least, so the command can be moved to the byte before the query without copying.
+		data = query
+		cc.lastPacket = cc.lastPacket[len(cc.lastPacket)-len(query)-1:]
+		cc.lastPacket[0] = cmd
+	}
 	if variable.TopSQLEnabled() {
 		defer pprof.SetGoroutineLabels(ctx)
 	}
@@ -1181,6 +1357,15 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		cc.ctx.SetCommandValue(cmd)
 	}
 
+	// Only the statements resetting the password are allowed in the sandbox mode.
+	if vars.PasswordExpired {
+		switch cmd {
+		case mysql.ComSleep, mysql.ComQuit, mysql.ComQuery, mysql.ComPing, mysql.ComChangeUser, mysql.ComSetOption:
+		default:
+			return errMustChangePassword
+		}
+	}
+
 	dataStr := string(hack.String(data))
 	switch cmd {
 	case mysql.ComPing, mysql.ComStmtClose, mysql.ComStmtSendLongData, mysql.ComStmtReset,
@@ -1310,10 +1495,22 @@ func (cc *clientConn) writeOK(ctx context.Context) error {
 }
 
 func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows, lastInsertID uint64, status, warnCnt uint16) error {
+	var stateInfo []byte
+	if cc.capability&clientSessionTrack > 0 && cc.ctx != nil {
+		stateInfo = dumpSessionStateChanges(nil, cc.ctx.GetSessionVars().CollectSessionStateChanges())
+		if len(stateInfo) > 0 {
+			status |= serverSessionStateChanged
+		}
+	}
+
 	enclen := 0
-	if len(msg) > 0 {
+	// The info message must be present if the session state info follows it.
+	if len(msg) > 0 || len(stateInfo) > 0 {
 		enclen = lengthEncodedIntSize(uint64(len(msg))) + len(msg)
 	}
+	if len(stateInfo) > 0 {
+		enclen += lengthEncodedIntSize(uint64(len(stateInfo))) + len(stateInfo)
+	}
 
 	data := cc.alloc.AllocWithLen(4, 32+enclen)
 	data = append(data, mysql.OKHeader)
@@ -1328,6 +1525,9 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 		// it is actually string<lenenc>
 		data = dumpLengthEncodedString(data, []byte(msg))
 	}
+	if len(stateInfo) > 0 {
+		data = dumpLengthEncodedString(data, stateInfo)
+	}
 
 	err := cc.writePacket(data)
 	if err != nil {
@@ -1337,6 +1537,42 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 	return cc.flush(ctx)
 }
 
+// The types of the session state changes in the OK packet, see
+// https://dev.mysql.com/doc/internals/en/packet-OK_Packet.html.
+const (
+	sessionTrackSystemVariables byte = iota
+	sessionTrackSchema
+	sessionTrackStateChange
+	sessionTrackGtids
+	sessionTrackTransactionCharacteristics
+	sessionTrackTransactionState
+)
+
+// dumpSessionStateChanges appends the session state changes in the format of the session state info in the OK packet.
+func dumpSessionStateChanges(buffer []byte, changes *variable.SessionStateChanges) []byte {
+	if changes.Empty() {
+		return buffer
+	}
+	dumpEntry := func(tp byte, data []byte) {
+		buffer = append(buffer, tp)
+		buffer = dumpLengthEncodedString(buffer, data)
+	}
+	for _, sysVar := range changes.SysVars {
+		data := dumpLengthEncodedString(nil, hack.Slice(sysVar[0]))
+		dumpEntry(sessionTrackSystemVariables, dumpLengthEncodedString(data, hack.Slice(sysVar[1])))
+	}
+	if changes.SchemaChanged {
+		dumpEntry(sessionTrackSchema, dumpLengthEncodedString(nil, hack.Slice(changes.Schema)))
+	}
+	if len(changes.TxnState) > 0 {
+		dumpEntry(sessionTrackTransactionState, dumpLengthEncodedString(nil, hack.Slice(changes.TxnState)))
+	}
+	if changes.TxnCharacteristicsChanged {
+		dumpEntry(sessionTrackTransactionCharacteristics, dumpLengthEncodedString(nil, hack.Slice(changes.TxnCharacteristics)))
+	}
+	return buffer
+}
+
 func (cc *clientConn) writeError(ctx context.Context, e error) error {
 	var (
 		m  *mysql.SQLError
@@ -1592,8 +1828,8 @@ func (cc *clientConn) handleLoadStats(ctx context.Context, loadStatsInfo *execut
 	return loadStatsInfo.Update(data)
 }
 
-// handleIndexAdvise does the index advise work and returns the advise result for index.
-func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo) error {
+// handleIndexAdvise does the index advise work and writes the advise result for index.
+func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo, status uint16) error {
 	if cc.capability&mysql.ClientLocalFiles == 0 {
 		return errNotAllowedCommand
 	}
@@ -1613,8 +1849,10 @@ func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *ex
 		return err
 	}
 
-	// TODO: Write the rss []ResultSet. It will be done in another PR.
-	return nil
+	rs := &tidbResultSet{recordSet: indexAdviseInfo.Result}
+	defer terror.Call(rs.Close)
+	_, err = cc.writeResultset(ctx, rs, false, status, 0)
+	return err
 }
 
 // handleQuery executes the sql query string and writes result set or result ok to the client.
@@ -1674,6 +1912,9 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) (err error) {
 	}
 	var retryable bool
 	for i, stmt := range stmts {
+		if cc.ctx.GetSessionVars().PasswordExpired && !allowedInSandboxMode(stmt) {
+			return errMustChangePassword
+		}
 		if len(pointPlans) > 0 {
 			// Save the point plan in Session so we don't need to build the point plan again.
 			cc.ctx.SetValue(plannercore.PointPlanKey, plannercore.PointPlanVal{Plan: pointPlans[i]})
@@ -1851,6 +2092,15 @@ func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns [
 	return false, nil
 }
 
+// allowedInSandboxMode returns whether the statement can be executed when the password has expired.
+func allowedInSandboxMode(stmt ast.StmtNode) bool {
+	switch stmt.(type) {
+	case *ast.SetPwdStmt, *ast.AlterUserStmt, *ast.SetStmt:
+		return true
+	}
+	return false
+}
+
 func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bool, error) {
 	handled := false
 	loadDataInfo := cc.ctx.Value(executor.LoadDataVarKey)
@@ -1873,11 +2123,9 @@ func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bo
 
 	indexAdvise := cc.ctx.Value(executor.IndexAdviseVarKey)
 	if indexAdvise != nil {
-		handled = true
 		defer cc.ctx.SetValue(executor.IndexAdviseVarKey, nil)
-		if err := cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo)); err != nil {
-			return handled, err
-		}
+		// The advice is returned as a result set instead of an OK packet.
+		return true, cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo), status)
 	}
 	return handled, cc.writeOkWith(ctx, cc.ctx.LastMessage(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), status, cc.ctx.WarningCount())
 }
@@ -2149,7 +2397,7 @@ func (cc *clientConn) handleChangeUser(ctx context.Context, data []byte) error {
 	if err != nil {
 		logutil.Logger(ctx).Debug("close old context failed", zap.Error(err))
 	}
-	err = cc.openSessionAndDoAuth(pass)
+	err = cc.openSessionAndDoAuth(pass, cc.salt)
 	if err != nil {
 		return err
 	}
@@ -152,8 +152,9 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 	// 0x02 CURSOR_TYPE_FOR_UPDATE
 	// 0x04 CURSOR_TYPE_SCROLLABLE
 	// Now we only support forward-only, read-only cursor.
+	// The client sets 0x08 PARAMETER_COUNT_AVAILABLE if it sends the parameter count for the query attributes.
 	var useCursor bool
-	switch flag {
+	switch flag &^ parameterCountAvailable {
 	case 0:
 		useCursor = false
 	case 1:
@@ -169,11 +170,30 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		nullBitmaps []byte
 		paramTypes  []byte
 		paramValues []byte
+		paramNames  []string
 	)
 	numParams := stmt.NumParams()
-	args := make([]types.Datum, numParams)
-	if numParams > 0 {
-		nullBitmapLen := (numParams + 7) >> 3
+	// The query attributes follow the parameters of the statement, so the parameter count sent by the client
+	// is the sum of the numbers of them.
+	paramCount := numParams
+	withAttrs := cc.capability&clientQueryAttributes > 0
+	if withAttrs && (numParams > 0 || flag&parameterCountAvailable > 0) {
+		if len(data) < pos+1 {
+			return mysql.ErrMalformPacket
+		}
+		count, n, err := parseLengthEncodedIntChecked(data[pos:])
+		if err != nil {
+			return err
+		}
+		if count < uint64(numParams) || count > uint64(len(data)) {
+			return mysql.ErrMalformPacket
+		}
+		paramCount = int(count)
+		pos += n
+	}
+	args := make([]types.Datum, paramCount)
+	if paramCount > 0 {
+		nullBitmapLen := (paramCount + 7) >> 3
 		if len(data) < (pos + nullBitmapLen + 1) {
 			return mysql.ErrMalformPacket
 		}
@@ -183,25 +203,46 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		// new param bound flag
 		if data[pos] == 1 {
 			pos++
-			if len(data) < (pos + (numParams << 1)) {
-				return mysql.ErrMalformPacket
+			if withAttrs {
+				var n int
+				paramTypes, paramNames, n, err = parseNamedParamTypes(data[pos:], paramCount)
+				if err != nil {
+					return err
+				}
+				pos += n
+			} else {
+				if len(data) < (pos + (paramCount << 1)) {
+					return mysql.ErrMalformPacket
+				}
+				paramTypes = data[pos : pos+(paramCount<<1)]
+				pos += paramCount << 1
 			}
-
-			paramTypes = data[pos : pos+(numParams<<1)]
-			pos += numParams << 1
 			paramValues = data[pos:]
 			// Just the first StmtExecute packet contain parameters type,
 			// we need save it for further use.
-			stmt.SetParamsType(paramTypes)
+			stmt.SetParamsType(paramTypes[:numParams<<1])
 		} else {
+			// The types of the query attributes are unknown if they are not sent with the values.
+			if paramCount > numParams {
+				return mysql.ErrMalformPacket
+			}
+			paramTypes = stmt.GetParamsType()
 			paramValues = data[pos+1:]
 		}
 
-		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, stmt.BoundParams(), nullBitmaps, stmt.GetParamsType(), paramValues)
+		boundParams := stmt.BoundParams()
+		if paramCount > numParams {
+			boundParams = append(boundParams[:numParams:numParams], make([][]byte, paramCount-numParams)...)
+		}
+		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, boundParams, nullBitmaps, paramTypes, paramValues)
 		stmt.Reset()
 		if err != nil {
 			return errors.Annotate(err, cc.preparedStmt2String(stmtID))
 		}
+		if paramCount > numParams {
+			cc.ctx.GetSessionVars().QueryAttributes = queryAttributesFromArgs(paramNames[numParams:], args[numParams:])
+			args = args[:numParams]
+		}
 	}
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
@@ -311,8 +352,105 @@ func parseStmtFetchCmd(data []byte) (uint32, uint32, error) {
 	return stmtID, fetchSize, nil
 }
 
-func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) (err error) {
-	pos := 0
+// parameterCountAvailable is the flag of COM_STMT_EXECUTE which indicates the parameter count is sent.
+const parameterCountAvailable = 0x08
+
+// parseQueryAttributes parses the query attributes which are sent before the query in COM_QUERY if the client
+// supports CLIENT_QUERY_ATTRIBUTES, and returns the attributes and the query.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_query.html
+func parseQueryAttributes(sc *stmtctx.StatementContext, data []byte) (map[string]string, []byte, error) {
+	paramCount, pos, err := parseLengthEncodedIntChecked(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	// skip parameter_set_count, always 1
+	_, n, err := parseLengthEncodedIntChecked(data[pos:])
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	if paramCount == 0 {
+		return nil, data[pos:], nil
+	}
+	if paramCount > uint64(len(data)) {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+
+	count := int(paramCount)
+	nullBitmapLen := (count + 7) >> 3
+	if len(data) < (pos + nullBitmapLen + 1) {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	nullBitmap := data[pos : pos+nullBitmapLen]
+	pos += nullBitmapLen
+	// new_params_bind_flag, always 1
+	if data[pos] != 1 {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	pos++
+	paramTypes, names, n, err := parseNamedParamTypes(data[pos:], count)
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	values := make([]types.Datum, count)
+	n, err = parseBinaryParams(sc, values, make([][]byte, count), nullBitmap, paramTypes, data[pos:])
+	if err != nil {
+		return nil, nil, err
+	}
+	pos += n
+	return queryAttributesFromArgs(names, values), data[pos:], nil
+}
+
+// parseNamedParamTypes parses the types of parameters which are followed by their names when the client supports
+// CLIENT_QUERY_ATTRIBUTES, and returns the types without the names, the names and the length of the parsed data.
+func parseNamedParamTypes(data []byte, paramCount int) (paramTypes []byte, names []string, pos int, err error) {
+	paramTypes = make([]byte, 0, paramCount<<1)
+	names = make([]string, 0, paramCount)
+	for i := 0; i < paramCount; i++ {
+		if len(data) < (pos + 3) {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		paramTypes = append(paramTypes, data[pos], data[pos+1])
+		pos += 2
+		nameLen, n, err := parseLengthEncodedIntChecked(data[pos:])
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		pos += n
+		if uint64(len(data)-pos) < nameLen {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		names = append(names, string(data[pos:pos+int(nameLen)]))
+		pos += int(nameLen)
+	}
+	return paramTypes, names, pos, nil
+}
+
+// queryAttributesFromArgs converts the values of the query attributes to strings, the attributes whose values
+// are NULL are omitted. The values are copied since the parsed datums may refer to the packet.
+func queryAttributesFromArgs(names []string, values []types.Datum) map[string]string {
+	attrs := make(map[string]string, len(values))
+	for i := range values {
+		if values[i].IsNull() {
+			continue
+		}
+		val, err := values[i].ToString()
+		if err != nil {
+			continue
+		}
+		attrs[names[i]] = string(hack.Slice(val))
+	}
+	return attrs
+}
+
+func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) error {
+	_, err := parseBinaryParams(sc, args, boundParams, nullBitmap, paramTypes, paramValues)
+	return err
+}
+
+// parseBinaryParams parses the values of parameters in binary protocol, and returns the length of the parsed values.
+func parseBinaryParams(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) (pos int, err error) {
 	var (
 		tmp    interface{}
 		v      []byte
@@ -341,7 +479,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 		}
 
 		if (i<<1)+1 >= len(paramTypes) {
-			return mysql.ErrMalformPacket
+			return pos, mysql.ErrMalformPacket
 		}
 
 		tp := paramTypes[i<<1]
@@ -508,7 +646,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 				var dec types.MyDecimal
 				err = sc.HandleTruncate(dec.FromString(v))
 				if err != nil {
-					return err
+					return
 				}
 				args[i] = types.NewDecimalDatum(&dec)
 			}
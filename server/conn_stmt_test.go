@@ -222,3 +222,46 @@ func (ts *ConnTestSuite) TestParseStmtFetchCmd(c *C) {
 		c.Assert(err, Equals, t.err)
 	}
 }
+
+func (ts *ConnTestSuite) TestParseQueryAttributes(c *C) {
+	tests := []struct {
+		arg   []byte
+		attrs map[string]string
+		query string
+		err   error
+	}{
+		{append([]byte{0x0, 0x1}, "select 1"...), nil, "select 1", nil},
+		{
+			// trace_id is a string and span is NULL.
+			append([]byte{0x2, 0x1, 0x2, 0x1, 0xfe, 0x0, 0x8}, "trace_id\xfe\x00\x04span\x03abcselect 1"...),
+			map[string]string{"trace_id": "abc"},
+			"select 1",
+			nil,
+		},
+		{
+			append([]byte{0x1, 0x1, 0x0, 0x1, 0x8, 0x0, 0x1, 'n', 0x2a, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0}, "do 1"...),
+			map[string]string{"n": "42"},
+			"do 1",
+			nil,
+		},
+		{[]byte{}, nil, "", mysql.ErrMalformPacket},
+		{[]byte{0xfc, 0x1}, nil, "", mysql.ErrMalformPacket},
+		{[]byte{0x1, 0x1, 0x0, 0x0}, nil, "", mysql.ErrMalformPacket},
+		{[]byte{0x1, 0x1, 0x0, 0x1, 0xfe, 0x0, 0xa, 'a'}, nil, "", mysql.ErrMalformPacket},
+		{[]byte{0x1, 0x1, 0x0, 0x1, 0x8, 0x0, 0x1, 'n', 0x2a}, nil, "", mysql.ErrMalformPacket},
+	}
+
+	for _, t := range tests {
+		attrs, query, err := parseQueryAttributes(&stmtctx.StatementContext{}, t.arg)
+		c.Assert(err, Equals, t.err)
+		if err != nil {
+			continue
+		}
+		if t.attrs == nil {
+			c.Assert(attrs, IsNil)
+		} else {
+			c.Assert(attrs, DeepEquals, t.attrs)
+		}
+		c.Assert(string(query), Equals, t.query)
+	}
+}
@@ -17,9 +17,17 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/binary"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"path/filepath"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
@@ -133,6 +141,19 @@ func (ts *ConnTestSuite) TestParseHandshakeResponse(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(p.User, Equals, "pam")
 	c.Assert(p.DBName, Equals, "test")
+	c.Assert(p.ZstdLevel, Equals, defaultZstdCompressionLevel)
+
+	// Test the compression level of zstd, which follows the connection attributes.
+	data[3] |= byte(clientZstdCompressionAlgorithm >> 24)
+	data = append(data, 0x07)
+	p = handshakeResponse41{}
+	offset, err = parseHandshakeResponseHeader(context.Background(), &p, data)
+	c.Assert(err, IsNil)
+	c.Assert(p.Capability&clientZstdCompressionAlgorithm, Equals, clientZstdCompressionAlgorithm)
+	err = parseHandshakeResponseBody(context.Background(), &p, data, offset)
+	c.Assert(err, IsNil)
+	c.Assert(p.DBName, Equals, "test")
+	c.Assert(p.ZstdLevel, Equals, 7)
 
 	// Test for compatibility of Protocol::HandshakeResponse320
 	data = []byte{
@@ -490,6 +511,128 @@ func (ts *ConnTestSuite) TestDispatchClientProtocol41(c *C) {
 	ts.testDispatch(c, inputs, mysql.ClientProtocol41)
 }
 
+func (ts *ConnTestSuite) TestDispatchSessionTrack(c *C) {
+	inputs := []dispatchInput{
+		{
+			com: mysql.ComQuery,
+			in:  []byte("set @@time_zone = '+08:00'"),
+			err: nil,
+			out: []byte{
+				0x1c, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2, 0x40, 0x0, 0x0, 0x0, 0x13, 0x0, 0x11,
+				0x9, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x6, 0x2b, 0x30, 0x38, 0x3a, 0x30, 0x30,
+			},
+		},
+		{
+			com: mysql.ComInitDB,
+			in:  []byte("test"),
+			err: nil,
+			out: []byte{0x10, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x2, 0x40, 0x0, 0x0, 0x0, 0x7, 0x1, 0x5, 0x4, 0x74, 0x65, 0x73, 0x74},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte("set session_track_transaction_info = 'STATE'"),
+			err: nil,
+			out: []byte{
+				0x14, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0, 0x2, 0x40, 0x0, 0x0, 0x0, 0xb, 0x5, 0x9, 0x8,
+				0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f,
+			},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte("begin"),
+			err: nil,
+			out: []byte{
+				0x14, 0x0, 0x0, 0x3, 0x0, 0x0, 0x0, 0x3, 0x40, 0x0, 0x0, 0x0, 0xb, 0x5, 0x9, 0x8,
+				0x54, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f,
+			},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte("insert into t values (2)"),
+			err: nil,
+			out: []byte{
+				0x14, 0x0, 0x0, 0x4, 0x0, 0x1, 0x0, 0x3, 0x40, 0x0, 0x0, 0x0, 0xb, 0x5, 0x9, 0x8,
+				0x54, 0x5f, 0x5f, 0x5f, 0x57, 0x5f, 0x5f, 0x5f,
+			},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte("commit"),
+			err: nil,
+			out: []byte{
+				0x14, 0x0, 0x0, 0x5, 0x0, 0x0, 0x0, 0x2, 0x40, 0x0, 0x0, 0x0, 0xb, 0x5, 0x9, 0x8,
+				0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f, 0x5f,
+			},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte("do 1"),
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x6, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+	}
+
+	ts.testDispatch(c, inputs, mysql.ClientProtocol41|clientSessionTrack)
+}
+
+func (ts *ConnTestSuite) TestDispatchQueryAttributes(c *C) {
+	// The query attributes is trace_id = 'abc', which is sent as a string.
+	attrs := append([]byte{0x1, 0x1, 0x0, 0x1, 0xfe, 0x0, 0x8}, "trace_id\x03abc"...)
+	inputs := []dispatchInput{
+		{
+			com: mysql.ComQuery,
+			in:  append(attrs, "delete from test.t where mysql_query_attribute_string('trace_id') = 'abc'"...),
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  append([]byte{0x0, 0x1}, "insert into test.t values (2)"...),
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x1, 0x0, 0x1, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+		{
+			// The query attributes are reset for each command.
+			com: mysql.ComQuery,
+			in:  append([]byte{0x0, 0x1}, "delete from test.t where mysql_query_attribute_string('trace_id') is not null"...),
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+		{
+			com: mysql.ComStmtPrepare,
+			in:  []byte("delete from test.t where mysql_query_attribute_string('trace_id') = 'abc'"),
+			err: nil,
+			out: []byte{0xc, 0x0, 0x0, 0x3, 0x0, 0x1, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+		},
+		{
+			com: mysql.ComStmtExecute,
+			in:  []byte{0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0},
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x4, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+		{
+			// The flag PARAMETER_COUNT_AVAILABLE is set and the parameter count is 1.
+			com: mysql.ComStmtExecute,
+			in:  append([]byte{0x1, 0x0, 0x0, 0x0, 0x8, 0x1, 0x0, 0x0, 0x0, 0x1}, attrs[2:]...),
+			err: nil,
+			out: []byte{0x7, 0x0, 0x0, 0x5, 0x0, 0x1, 0x0, 0x2, 0x0, 0x0, 0x0},
+		},
+		{
+			com: mysql.ComQuery,
+			in:  []byte{0xfc},
+			err: mysql.ErrMalformPacket,
+		},
+		{
+			// The types of the query attributes are not sent.
+			com: mysql.ComStmtExecute,
+			in:  []byte{0x1, 0x0, 0x0, 0x0, 0x8, 0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0},
+			err: mysql.ErrMalformPacket,
+		},
+	}
+
+	ts.testDispatch(c, inputs, mysql.ClientProtocol41|clientQueryAttributes)
+}
+
 func (ts *ConnTestSuite) testDispatch(c *C, inputs []dispatchInput, capability uint32) {
 	store, err := mockstore.NewMockStore()
 	c.Assert(err, IsNil)
@@ -877,3 +1020,161 @@ func (ts *ConnTestSuite) TestShowErrors(c *C) {
 	c.Assert(err, NotNil)
 	tk.MustQuery("show errors").Check(testkit.Rows("Error 1051 Unknown table 'test.idontexist'"))
 }
+
+func (ts *ConnTestSuite) TestSha2PasswordAuth(c *C) {
+	tk := testkit.NewTestKit(c, ts.store)
+	tk.MustExec("CREATE USER 'sha2conn'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'sha2pass'")
+	tk.MustExec("CREATE USER 'sha256conn'@'localhost' IDENTIFIED WITH 'sha256_password' BY 'sha256pass'")
+
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	defer server.Close()
+	keys, err := server.getRSAKeyPair()
+	c.Assert(err, IsNil)
+
+	salt := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14}
+	scramble := func(password string) []byte {
+		stage1 := sha256.Sum256([]byte(password))
+		stage2 := sha256.Sum256(stage1[:])
+		h := sha256.New()
+		h.Write(stage2[:])
+		h.Write(salt)
+		data := h.Sum(nil)
+		for i := range data {
+			data[i] ^= stage1[i]
+		}
+		return data
+	}
+	encrypt := func(password string) []byte {
+		data := append([]byte(password), 0)
+		for i := range data {
+			data[i] ^= salt[i%len(salt)]
+		}
+		data, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, &keys.privateKey.PublicKey, data, nil)
+		c.Assert(err, IsNil)
+		return data
+	}
+	packet := func(sequence byte, payload ...byte) []byte {
+		return append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequence}, payload...)
+	}
+	publicKeyPayload := append([]byte{authMoreData}, keys.publicKey...)
+
+	tests := []struct {
+		user     string
+		authData []byte
+		secure   bool
+		// inputs are the packets sent by the client after the auth data, their sequences are 1, 3, 5...
+		inputs [][]byte
+		out    []byte
+		denied bool
+	}{
+		// The fast authentication fails until the user passes the full authentication.
+		{"sha2conn", scramble("sha2pass"), false, [][]byte{{cachingSha2RequestPublicKey}, encrypt("sha2pass")},
+			append(packet(0, authMoreData, cachingSha2PerformFullAuth), packet(2, publicKeyPayload...)...), false},
+		{"sha2conn", scramble("sha2pass"), false, nil, packet(0, authMoreData, cachingSha2FastAuthOK), false},
+		{"sha2conn", scramble("wrongpass"), false, [][]byte{{cachingSha2RequestPublicKey}, encrypt("wrongpass")},
+			append(packet(0, authMoreData, cachingSha2PerformFullAuth), packet(2, publicKeyPayload...)...), true},
+		{"sha2conn", scramble("wrongpass"), true, [][]byte{[]byte("sha2pass\x00")}, packet(0, authMoreData, cachingSha2PerformFullAuth), false},
+		{"sha2conn", nil, false, nil, nil, true},
+		{"sha256conn", []byte{sha256RequestPublicKey}, false, [][]byte{encrypt("sha256pass")}, packet(0, publicKeyPayload...), false},
+		{"sha256conn", encrypt("sha256pass"), false, nil, nil, false},
+		{"sha256conn", []byte("sha256pass\x00"), true, nil, nil, false},
+		{"sha256conn", encrypt("wrongpass"), false, nil, nil, true},
+		{"sha256conn", []byte("sha256pass\x00"), false, nil, nil, true},
+	}
+	for i, t := range tests {
+		var outBuffer bytes.Buffer
+		conn := &bytesConn{}
+		for j, input := range t.inputs {
+			conn.b.Write(packet(byte(j*2+1), input...))
+		}
+		cc := &clientConn{
+			connectionID: 1,
+			salt:         salt,
+			server:       server,
+			pkt:          newPacketIO(newBufferedReadConn(conn)),
+			collation:    mysql.DefaultCollationID,
+			peerHost:     "localhost",
+			alloc:        arena.NewAllocator(512),
+			user:         t.user,
+			isUnixSocket: t.secure,
+		}
+		cc.pkt.bufWriter = bufio.NewWriter(&outBuffer)
+		comment := Commentf("case %d", i)
+		if t.user == "sha2conn" {
+			err = cc.authCachingSha2Password(context.Background(), t.authData)
+		} else {
+			err = cc.authSha256Password(context.Background(), t.authData)
+		}
+		if t.denied {
+			c.Assert(errAccessDenied.Equal(err), IsTrue, comment)
+		} else {
+			c.Assert(err, IsNil, comment)
+			c.Assert(cc.ctx.GetSessionVars().User.AuthUsername, Equals, t.user, comment)
+		}
+		c.Assert(cc.flush(context.Background()), IsNil)
+		c.Assert(outBuffer.Bytes(), DeepEquals, t.out, comment)
+		c.Assert(conn.b.Len(), Equals, 0, comment)
+		if cc.ctx != nil {
+			c.Assert(cc.ctx.Close(), IsNil)
+		}
+	}
+}
+
+func (ts *ConnTestSuite) TestLoadRSAKeyPair(c *C) {
+	dir := c.MkDir()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, IsNil)
+	privateKeyPath := filepath.Join(dir, "private_key.pem")
+	err = ioutil.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}), 0600)
+	c.Assert(err, IsNil)
+
+	// The public key is derived from the private key.
+	keys, err := loadRSAKeyPair(privateKeyPath, "")
+	c.Assert(err, IsNil)
+	c.Assert(keys.privateKey.Equal(privateKey), IsTrue)
+	block, _ := pem.Decode(keys.publicKey)
+	c.Assert(block, NotNil)
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	c.Assert(err, IsNil)
+	c.Assert(privateKey.PublicKey.Equal(publicKey), IsTrue)
+
+	publicKeyPath := filepath.Join(dir, "public_key.pem")
+	c.Assert(ioutil.WriteFile(publicKeyPath, keys.publicKey, 0600), IsNil)
+	publicKeyPEM := keys.publicKey
+	keys, err = loadRSAKeyPair(privateKeyPath, publicKeyPath)
+	c.Assert(err, IsNil)
+	c.Assert(keys.publicKey, DeepEquals, publicKeyPEM)
+
+	// The public key must match the private key.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, IsNil)
+	other, err := newRSAKeyPair(otherKey)
+	c.Assert(err, IsNil)
+	c.Assert(ioutil.WriteFile(publicKeyPath, other.publicKey, 0600), IsNil)
+	_, err = loadRSAKeyPair(privateKeyPath, publicKeyPath)
+	c.Assert(err, ErrorMatches, ".*doesn't match the private key.*")
+
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	cfg.Security.RSAPrivateKeyPath = privateKeyPath
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	keys, err = server.getRSAKeyPair()
+	c.Assert(err, IsNil)
+	c.Assert(keys.privateKey.Equal(privateKey), IsTrue)
+	server.Close()
+
+	// A key pair is generated if it's not configured.
+	cfg.Security.RSAPrivateKeyPath = ""
+	server, err = NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	keys, err = server.getRSAKeyPair()
+	c.Assert(err, IsNil)
+	c.Assert(keys.privateKey.Equal(privateKey), IsFalse)
+	server.Close()
+}
@@ -58,6 +58,8 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+	// compressedIO is not nil if the compressed protocol is used.
+	compressedIO *compressedIO
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -75,6 +77,33 @@ func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompression switches the connection to the compressed protocol, it should be called after the handshake.
+func (p *packetIO) setCompression(algorithm int, level int) {
+	p.compressedIO = newCompressedIO(p, algorithm, level)
+}
+
+// resetSequence resets the sequences when a new command begins.
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	if p.compressedIO != nil {
+		p.compressedIO.sequence = 0
+	}
+}
+
+func (p *packetIO) reader() io.Reader {
+	if p.compressedIO != nil {
+		return p.compressedIO
+	}
+	return p.bufReadConn
+}
+
+func (p *packetIO) writer() io.Writer {
+	if p.compressedIO != nil {
+		return p.compressedIO
+	}
+	return p.bufWriter
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
 	if p.readTimeout > 0 {
@@ -82,16 +111,18 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if _, err := io.ReadFull(p.reader(), header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	sequence := header[3]
-	if sequence != p.sequence {
+	// The sequence of the packets wrapped by compressed packets is not checked, which is the same as MySQL,
+	// the sequence of the compressed packets is checked instead.
+	if sequence != p.sequence && p.compressedIO == nil {
 		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
 	}
 
-	p.sequence++
+	p.sequence = sequence + 1
 
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
@@ -101,7 +132,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(p.reader(), data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -145,6 +176,7 @@ func (p *packetIO) readPacket() ([]byte, error) {
 func (p *packetIO) writePacket(data []byte) error {
 	length := len(data) - 4
 	writePacketBytes.Observe(float64(len(data)))
+	w := p.writer()
 
 	for length >= mysql.MaxPayloadLen {
 		data[0] = 0xff
@@ -153,7 +185,7 @@ func (p *packetIO) writePacket(data []byte) error {
 
 		data[3] = p.sequence
 
-		if n, err := p.bufWriter.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
+		if n, err := w.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
 			return errors.Trace(mysql.ErrBadConn)
 		} else if n != (4 + mysql.MaxPayloadLen) {
 			return errors.Trace(mysql.ErrBadConn)
@@ -169,7 +201,7 @@ func (p *packetIO) writePacket(data []byte) error {
 	data[2] = byte(length >> 16)
 	data[3] = p.sequence
 
-	if n, err := p.bufWriter.Write(data); err != nil {
+	if n, err := w.Write(data); err != nil {
 		terror.Log(errors.Trace(err))
 		return errors.Trace(mysql.ErrBadConn)
 	} else if n != len(data) {
@@ -181,6 +213,11 @@ func (p *packetIO) writePacket(data []byte) error {
 }
 
 func (p *packetIO) flush() error {
+	if p.compressedIO != nil {
+		if err := p.compressedIO.flush(); err != nil {
+			return err
+		}
+	}
 	err := p.bufWriter.Flush()
 	if err != nil {
 		return errors.Trace(err)
@@ -19,7 +19,9 @@ import (
 	"net"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 )
 
@@ -89,6 +91,86 @@ func (s *PacketIOTestSuite) TestRead(c *C) {
 	c.Assert(bytes[mysql.MaxPayloadLen], DeepEquals, byte(0x0a))
 }
 
+func (s *PacketIOTestSuite) TestCompressedReadWrite(c *C) {
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		var outBuffer bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		pkt.setCompression(algorithm, defaultZstdCompressionLevel)
+		smallPayload := []byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03}
+		c.Assert(pkt.writePacket(smallPayload), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		// Small payloads are not compressed.
+		c.Assert(outBuffer.Bytes(), DeepEquals, []byte{0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03})
+
+		largePayload := make([]byte, mysql.MaxPayloadLen+1024)
+		for i := 4; i < len(largePayload); i++ {
+			largePayload[i] = byte(i % 10)
+		}
+		expected := append([]byte{}, largePayload[4:]...)
+		c.Assert(pkt.writePacket(largePayload), IsNil)
+		c.Assert(pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x04}), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		c.Assert(outBuffer.Len() < mysql.MaxPayloadLen/10, IsTrue)
+		c.Assert(pkt.sequence, Equals, uint8(4))
+		c.Assert(pkt.compressedIO.sequence, Equals, uint8(3))
+
+		brc := newBufferedReadConn(&bytesConn{outBuffer})
+		pkt = newPacketIO(brc)
+		pkt.setCompression(algorithm, defaultZstdCompressionLevel)
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, []byte{0x01, 0x02, 0x03})
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(bytes.Equal(data, expected), IsTrue)
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, []byte{0x04})
+		c.Assert(pkt.sequence, Equals, uint8(4))
+		c.Assert(pkt.compressedIO.sequence, Equals, uint8(3))
+
+		// The sequence of compressed packets is checked.
+		var inBuffer bytes.Buffer
+		_, err = inBuffer.Write([]byte{0x05, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01})
+		c.Assert(err, IsNil)
+		brc = newBufferedReadConn(&bytesConn{inBuffer})
+		pkt = newPacketIO(brc)
+		pkt.setCompression(algorithm, defaultZstdCompressionLevel)
+		_, err = pkt.readPacket()
+		c.Assert(errInvalidSequence.Equal(err), IsTrue)
+	}
+}
+
+func (s *PacketIOTestSuite) TestZstdDecompressionBomb(c *C) {
+	payload := make([]byte, mysql.MaxPayloadLen+1)
+	encoder, err := getZstdEncoder(defaultZstdCompressionLevel)
+	c.Assert(err, IsNil)
+	// The frame content size is written by EncodeAll but not by the streaming encoder.
+	frames := [][]byte{encoder.EncodeAll(payload, nil)}
+	var streamed bytes.Buffer
+	w, err := zstd.NewWriter(&streamed)
+	c.Assert(err, IsNil)
+	_, err = w.Write(payload)
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+	frames = append(frames, streamed.Bytes())
+
+	for _, frame := range frames {
+		c.Assert(len(frame) < 1<<16, IsTrue)
+		var inBuffer bytes.Buffer
+		uncompressedLength := 1024
+		_, err = inBuffer.Write([]byte{byte(len(frame)), byte(len(frame) >> 8), byte(len(frame) >> 16), 0x00,
+			byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16)})
+		c.Assert(err, IsNil)
+		_, err = inBuffer.Write(frame)
+		c.Assert(err, IsNil)
+		pkt := newPacketIO(newBufferedReadConn(&bytesConn{inBuffer}))
+		pkt.setCompression(compressionZstd, defaultZstdCompressionLevel)
+		_, err = pkt.readPacket()
+		c.Assert(errors.Cause(err), Equals, zstd.ErrDecoderSizeExceeded)
+	}
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
@@ -100,6 +100,20 @@ var (
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errMustChangePassword      = dbterror.ClassServer.NewStd(errno.ErrMustChangePassword)
+	errMustChangePasswordLogin = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
+
+	errAccountBlockedByPasswordLock = dbterror.ClassServer.NewStd(errno.ErrUserAccessDeniedForUserAccountBlockedByPasswordLock)
+)
+
+// The capability flags and server status flags which are not defined in the parser yet.
+const (
+	clientCanHandleExpiredPasswords uint32 = 1 << 22
+	clientSessionTrack              uint32 = 1 << 23
+	clientZstdCompressionAlgorithm  uint32 = 1 << 26
+	clientQueryAttributes           uint32 = 1 << 27
+
+	serverSessionStateChanged uint16 = 0x4000
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -108,7 +122,9 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientCompress | clientZstdCompressionAlgorithm | clientSessionTrack | clientQueryAttributes |
+	clientCanHandleExpiredPasswords
 
 // Server is the MySQL protocol server
 type Server struct {
@@ -124,6 +140,12 @@ type Server struct {
 	dom               *domain.Domain
 	globalConnID      util.GlobalConnID
 
+	// rsaKeys is used to exchange the password of caching_sha2_password and sha256_password, it's loaded
+	// from the configured files or generated at the first time it's used.
+	rsaKeys    *rsaKeyPair
+	rsaKeyErr  error
+	rsaKeyOnce sync.Once
+
 	statusAddr     string
 	statusListener net.Listener
 	statusServer   *http.Server
@@ -205,6 +227,12 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		return nil, errSecureTransportRequired.FastGenByArgs()
 	}
 
+	if cfg.Security.RSAPrivateKeyPath != "" {
+		if s.rsaKeys, err = loadRSAKeyPair(cfg.Security.RSAPrivateKeyPath, cfg.Security.RSAPublicKeyPath); err != nil {
+			logutil.BgLogger().Error("RSA key pair load fail, a key pair will be generated instead", zap.Error(err))
+		}
+	}
+
 	setSystemTimeZoneVariable()
 
 	s.capability = defaultCapability
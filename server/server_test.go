@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1600,6 +1601,92 @@ func (cli *testServerClient) runTestAuth(c *C) {
 	}, func(dbt *DBTest) {
 		dbt.mustExec(`USE information_schema;`)
 	})
+
+	// The password of caching_sha2_password and sha256_password is encrypted by the RSA public key
+	// of the server over insecure connections.
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'authtest3'@'%' IDENTIFIED WITH 'caching_sha2_password' BY '123';`)
+		dbt.mustExec(`CREATE USER 'authtest4'@'%' IDENTIFIED WITH 'sha256_password' BY '123';`)
+		dbt.mustExec(`GRANT ALL on test.* to 'authtest3', 'authtest4'`)
+	})
+	for _, user := range []string{"authtest3", "authtest3", "authtest4"} {
+		cli.runTests(c, func(config *mysql.Config) {
+			config.User = user
+			config.Passwd = "123"
+		}, func(dbt *DBTest) {
+			dbt.mustExec(`USE information_schema;`)
+		})
+		db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+			config.User = user
+			config.Passwd = "456"
+		}))
+		c.Assert(err, IsNil)
+		c.Assert(db.Ping(), NotNil, Commentf("Wrong password should be failed"))
+		c.Assert(db.Close(), IsNil)
+	}
+
+	// The account is locked after consecutive failed logins.
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'authtest5'@'%' IDENTIFIED BY '123';`)
+		dbt.mustExec(`GRANT ALL on test.* to 'authtest5'`)
+		dbt.mustExec(`UPDATE mysql.user SET Failed_login_attempts = 2, Password_lock_time = 1 WHERE User = 'authtest5';`)
+		dbt.mustExec(`FLUSH PRIVILEGES;`)
+	})
+	pingAs := func(user, passwd string) error {
+		db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+			config.User = user
+			config.Passwd = passwd
+		}))
+		c.Assert(err, IsNil)
+		defer func() {
+			c.Assert(db.Close(), IsNil)
+		}()
+		return db.Ping()
+	}
+	c.Assert(pingAs("authtest5", "456"), NotNil)
+	c.Assert(pingAs("authtest5", "123"), IsNil)
+	c.Assert(pingAs("authtest5", "456"), NotNil)
+	err = pingAs("authtest5", "456")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "Error 3955: Access denied for user 'authtest5'@'%'. Account is blocked for 1 day(s) (1 day(s) remaining) due to 2 consecutive failed logins.")
+	err = pingAs("authtest5", "123")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, "Error 3955: .*")
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`ALTER USER 'authtest5'@'%' ACCOUNT UNLOCK;`)
+	})
+	c.Assert(pingAs("authtest5", "123"), IsNil)
+
+	// The concurrent failed logins are all counted, so the account is locked by the last one.
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'authtest6'@'%' IDENTIFIED BY '123';`)
+		dbt.mustExec(`UPDATE mysql.user SET Failed_login_attempts = 8, Password_lock_time = 1 WHERE User = 'authtest6';`)
+		dbt.mustExec(`FLUSH PRIVILEGES;`)
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Check(pingAs("authtest6", "456"), NotNil)
+		}()
+	}
+	wg.Wait()
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		rows := dbt.mustQuery(`SELECT Failed_count, Locked_time IS NOT NULL FROM mysql.login_failures WHERE User = 'authtest6';`)
+		cli.checkRows(c, rows, "8 1")
+	})
+	err = pingAs("authtest6", "123")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, "Error 3955: .*")
+
+	// The clients which can't handle the sandbox mode are rejected if the password has expired.
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`ALTER USER 'authtest5'@'%' PASSWORD EXPIRE;`)
+	})
+	err = pingAs("authtest5", "123")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, "Error 1862: .*")
 }
 
 func (cli *testServerClient) runTestIssue3662(c *C) {
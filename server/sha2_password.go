@@ -0,0 +1,221 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The packets of caching_sha2_password and sha256_password, see
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_caching_sha2_authentication_exchanges.html.
+const (
+	// authMoreData is the header of the packets sent by the server during the authentication.
+	authMoreData byte = 0x01
+	// cachingSha2FastAuthOK and cachingSha2PerformFullAuth are the results of the fast authentication.
+	cachingSha2FastAuthOK      byte = 0x03
+	cachingSha2PerformFullAuth byte = 0x04
+	// cachingSha2RequestPublicKey and sha256RequestPublicKey are sent by the client to ask for the RSA public key.
+	cachingSha2RequestPublicKey byte = 0x02
+	sha256RequestPublicKey      byte = 0x01
+)
+
+const rsaKeyBits = 2048
+
+// rsaKeyPair is the RSA key pair used to encrypt the password over insecure connections.
+type rsaKeyPair struct {
+	privateKey *rsa.PrivateKey
+	// publicKey is the public key in PEM format, which is sent to the client.
+	publicKey []byte
+}
+
+// loadRSAKeyPair loads the RSA key pair from the PEM files, the public key is derived from the private key
+// if its path is empty.
+func loadRSAKeyPair(privateKeyPath, publicKeyPath string) (*rsaKeyPair, error) {
+	data, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("failed to decode the PEM block of RSA private key %s", privateKeyPath)
+	}
+	var privateKey *rsa.PrivateKey
+	if block.Type == "RSA PRIVATE KEY" {
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		var key interface{}
+		if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			var ok bool
+			if privateKey, ok = key.(*rsa.PrivateKey); !ok {
+				err = errors.Errorf("%s is not a RSA private key", privateKeyPath)
+			}
+		}
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if publicKeyPath == "" {
+		return newRSAKeyPair(privateKey)
+	}
+
+	data, err = ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ = pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("failed to decode the PEM block of RSA public key %s", publicKeyPath)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if key, ok := publicKey.(*rsa.PublicKey); !ok || !privateKey.PublicKey.Equal(key) {
+		return nil, errors.Errorf("RSA public key %s doesn't match the private key %s", publicKeyPath, privateKeyPath)
+	}
+	return &rsaKeyPair{privateKey: privateKey, publicKey: data}, nil
+}
+
+func newRSAKeyPair(privateKey *rsa.PrivateKey) (*rsaKeyPair, error) {
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	return &rsaKeyPair{privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+// getRSAKeyPair returns the RSA key pair of the server, it's generated at the first time if it's not configured.
+func (s *Server) getRSAKeyPair() (*rsaKeyPair, error) {
+	s.rsaKeyOnce.Do(func() {
+		if s.rsaKeys != nil {
+			return
+		}
+		privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			s.rsaKeyErr = errors.Trace(err)
+			return
+		}
+		s.rsaKeys, s.rsaKeyErr = newRSAKeyPair(privateKey)
+	})
+	return s.rsaKeys, s.rsaKeyErr
+}
+
+// isSecureTransport returns whether the password can be sent in cleartext.
+func (cc *clientConn) isSecureTransport() bool {
+	return cc.tlsConn != nil || cc.isUnixSocket
+}
+
+// writeAuthMoreData writes a packet of the authentication exchanges to the client.
+func (cc *clientConn) writeAuthMoreData(ctx context.Context, payload []byte) error {
+	data := cc.alloc.AllocWithLen(4, 4+1+len(payload))
+	data = append(data, authMoreData)
+	data = append(data, payload...)
+	if err := cc.writePacket(data); err != nil {
+		return err
+	}
+	return cc.flush(ctx)
+}
+
+// authCachingSha2Password authenticates the user of caching_sha2_password, the auth data is the scramble
+// of the password. It tries the fast authentication at first, and asks the client for the password if the
+// scramble can't be verified by the cached digest of the password.
+func (cc *clientConn) authCachingSha2Password(ctx context.Context, authData []byte) error {
+	// The client sends nothing if the password is empty.
+	if len(authData) == 0 {
+		return cc.openSessionAndDoAuth(nil, nil)
+	}
+	err := cc.openSessionAndDoAuth(authData, cc.salt)
+	if err == nil {
+		return cc.writeAuthMoreData(ctx, []byte{cachingSha2FastAuthOK})
+	}
+	if !errAccessDenied.Equal(err) {
+		return err
+	}
+
+	if err = cc.writeAuthMoreData(ctx, []byte{cachingSha2PerformFullAuth}); err != nil {
+		return err
+	}
+	data, err := cc.readPacket()
+	if err != nil {
+		return err
+	}
+	password, err := cc.readSha2Password(ctx, data, cachingSha2RequestPublicKey)
+	if err != nil {
+		return err
+	}
+	return cc.openSessionAndDoAuth(password, nil)
+}
+
+// authSha256Password authenticates the user of sha256_password, the auth data is the first packet of
+// the password exchange.
+func (cc *clientConn) authSha256Password(ctx context.Context, authData []byte) error {
+	password, err := cc.readSha2Password(ctx, authData, sha256RequestPublicKey)
+	if err != nil {
+		return err
+	}
+	return cc.openSessionAndDoAuth(password, nil)
+}
+
+// readSha2Password returns the cleartext password sent by the client, the password is sent in cleartext
+// over secure connections, otherwise it's encrypted by the RSA public key of the server and the client
+// may ask for the public key at first.
+func (cc *clientConn) readSha2Password(ctx context.Context, data []byte, requestPublicKey byte) ([]byte, error) {
+	// The empty password is sent as a single NUL.
+	if len(data) == 0 || (len(data) == 1 && data[0] == 0) {
+		return nil, nil
+	}
+	if cc.isSecureTransport() {
+		return bytes.TrimSuffix(data, []byte{0}), nil
+	}
+
+	keys, err := cc.server.getRSAKeyPair()
+	if err != nil {
+		logutil.Logger(ctx).Error("failed to get the RSA key pair", zap.Error(err))
+		return nil, err
+	}
+	if len(data) == 1 && data[0] == requestPublicKey {
+		if err = cc.writeAuthMoreData(ctx, keys.publicKey); err != nil {
+			return nil, err
+		}
+		if data, err = cc.readPacket(); err != nil {
+			return nil, err
+		}
+	}
+	// The client encrypts XOR(password + NUL, salt) with RSA_PKCS1_OAEP_PADDING.
+	password, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, keys.privateKey, data, nil)
+	if err != nil {
+		logutil.Logger(ctx).Warn("failed to decrypt the password", zap.Error(err))
+		host, _, err1 := cc.PeerHost("YES")
+		if err1 != nil {
+			return nil, err1
+		}
+		return nil, errAccessDenied.FastGenByArgs(cc.user, host, "YES")
+	}
+	for i := range password {
+		password[i] ^= cc.salt[i%len(cc.salt)]
+	}
+	return bytes.TrimSuffix(password, []byte{0}), nil
+}
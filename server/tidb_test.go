@@ -125,6 +125,7 @@ func (ts *tidbTestSuiteBase) SetUpSuite(c *C) {
 	cfg.Status.ReportStatus = true
 	cfg.Status.StatusPort = ts.statusPort
 	cfg.Performance.TCPKeepAlive = true
+	cfg.Log.SlowQueryFile = filepath.Join(c.MkDir(), "tidb-slow.log")
 	err = logutil.InitLogger(cfg.Log.ToLogConfig())
 	c.Assert(err, IsNil)
 
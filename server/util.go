@@ -96,6 +96,30 @@ func parseLengthEncodedInt(b []byte) (num uint64, isNull bool, n int) {
 	return
 }
 
+// parseLengthEncodedIntChecked is the same as parseLengthEncodedInt except that it checks the length of the data
+// and treats NULL as malformed.
+func parseLengthEncodedIntChecked(b []byte) (num uint64, n int, err error) {
+	if len(b) == 0 {
+		return 0, 0, mysql.ErrMalformPacket
+	}
+	size := 1
+	switch b[0] {
+	case 0xfb:
+		return 0, 0, mysql.ErrMalformPacket
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	}
+	if len(b) < size {
+		return 0, 0, mysql.ErrMalformPacket
+	}
+	num, _, n = parseLengthEncodedInt(b)
+	return num, n, nil
+}
+
 func dumpLengthEncodedInt(buffer []byte, n uint64) []byte {
 	switch {
 	case n <= 250:
@@ -89,6 +89,13 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),
+		password_lifetime		SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_history	SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED DEFAULT NULL,
+		Failed_login_attempts	SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Password_lock_time		SMALLINT NOT NULL DEFAULT 0,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -339,8 +346,87 @@ const (
 		WITH_GRANT_OPTION enum('N','Y') NOT NULL DEFAULT 'N',
 		PRIMARY KEY (USER,HOST,PRIV)
 	  );`
+	// CreateStmtSummaryHistoryAllView unions the persisted statement summaries and the in-memory ones
+	// which are not persisted yet.
+	CreateStmtSummaryHistoryAllView = `CREATE OR REPLACE SQL SECURITY INVOKER VIEW mysql.cluster_statements_summary_history_all AS
+		SELECT * FROM mysql.statements_summary_history
+		UNION ALL
+		SELECT * FROM information_schema.cluster_statements_summary_history c WHERE NOT EXISTS (
+			SELECT 1 FROM mysql.statements_summary_history h WHERE h.INSTANCE = c.INSTANCE AND h.SUMMARY_END_TIME >= c.SUMMARY_END_TIME
+		);`
+	// CreateDeadlockHistoryTable stores the deadlock events of all TiDB instances.
+	// Each row is an item in the wait chain of a deadlock, which is the same as information_schema.cluster_deadlocks.
+	CreateDeadlockHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.deadlock_history (
+		INSTANCE VARCHAR(64) NOT NULL,
+		DEADLOCK_ID BIGINT(21) NOT NULL,
+		OCCUR_TIME TIMESTAMP(6) NOT NULL,
+		RETRYABLE TINYINT(1) NOT NULL,
+		TRY_LOCK_TRX_ID BIGINT(21) UNSIGNED NOT NULL,
+		CURRENT_SQL_DIGEST VARCHAR(64),
+		` + "`KEY`" + ` TEXT,
+		TRX_HOLDING_LOCK BIGINT(21) UNSIGNED NOT NULL,
+		RESOURCE_GROUP_TAG TEXT,
+		DEADLOCK_KEY TEXT NOT NULL,
+		KEY idx_occur_time (OCCUR_TIME),
+		KEY idx_deadlock_key (DEADLOCK_KEY(255))
+	);`
+	// CreateClusterDeadlocksSummaryView aggregates the persisted deadlock events by DEADLOCK_KEY, so that the same
+	// deadlock seen from multiple TiDB instances can be correlated. The IDs of deadlock events are allocated from 1
+	// again after an instance restarts, so an event is identified by the instance, the ID and the occur time.
+	CreateClusterDeadlocksSummaryView = `CREATE OR REPLACE SQL SECURITY INVOKER VIEW mysql.cluster_deadlocks_summary AS
+		SELECT DEADLOCK_KEY,
+			MIN(OCCUR_TIME) AS FIRST_OCCUR_TIME,
+			MAX(OCCUR_TIME) AS LAST_OCCUR_TIME,
+			COUNT(DISTINCT INSTANCE, DEADLOCK_ID, OCCUR_TIME) AS OCCUR_COUNT,
+			COUNT(DISTINCT INSTANCE) AS INSTANCE_COUNT,
+			GROUP_CONCAT(DISTINCT INSTANCE ORDER BY INSTANCE) AS INSTANCES,
+			GROUP_CONCAT(DISTINCT CURRENT_SQL_DIGEST ORDER BY CURRENT_SQL_DIGEST) AS SQL_DIGESTS,
+			MAX(RETRYABLE) AS RETRYABLE
+		FROM mysql.deadlock_history GROUP BY DEADLOCK_KEY;`
+	// CreatePasswordHistoryTable is the SQL statement creates the table storing the previous passwords of the
+	// accounts, which are checked against the password reuse policy.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host				CHAR(64) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp	TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password			TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp));`
+	// CreateLoginFailuresTable is the SQL statement creates the table tracking the consecutive failed logins of
+	// the accounts which have FAILED_LOGIN_ATTEMPTS set, it's shared by all TiDB instances.
+	CreateLoginFailuresTable = `CREATE TABLE IF NOT EXISTS mysql.login_failures (
+		Host				CHAR(64) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Failed_count		INT UNSIGNED NOT NULL DEFAULT 0,
+		Locked_time			TIMESTAMP NULL DEFAULT NULL,
+		PRIMARY KEY (Host, User));`
 )
 
+// CreateStmtSummaryHistoryTable stores the expired statement summaries of all TiDB instances.
+// The columns are built from information_schema.cluster_statements_summary_history, so that the rows read from it
+// can be persisted as they are.
+var CreateStmtSummaryHistoryTable = buildCreateStmtSummaryHistoryTable()
+
+func buildCreateStmtSummaryHistoryTable() string {
+	var sql strings.Builder
+	sql.WriteString("CREATE TABLE IF NOT EXISTS mysql.statements_summary_history (\n")
+	for _, col := range infoschema.GetMemTableColumns(infoschema.ClusterTableStatementsSummaryHistory) {
+		tp := col.GetTypeDesc()
+		// The texts are not limited in length in the memory table.
+		if col.Tp == mysql.TypeBlob {
+			tp = "longtext"
+		}
+		fmt.Fprintf(&sql, "\t\t`%s` %s", col.Name.O, tp)
+		if mysql.HasNotNullFlag(col.Flag) {
+			sql.WriteString(" NOT NULL")
+		}
+		sql.WriteString(",\n")
+	}
+	sql.WriteString("\t\tKEY idx_end_time (SUMMARY_END_TIME),\n")
+	sql.WriteString("\t\tKEY idx_instance_end_time (INSTANCE, SUMMARY_END_TIME)\n")
+	sql.WriteString("\t);")
+	return sql.String()
+}
+
 // bootstrap initiates system DB for a store.
 func bootstrap(s Session) {
 	startTime := time.Now()
@@ -498,11 +584,18 @@ const (
 	version71 = 71
 	// version72 adds snapshot column for mysql.stats_meta
 	version72 = 72
+	// version73 adds mysql.statements_summary_history table and mysql.cluster_statements_summary_history_all view.
+	version73 = 73
+	// version74 adds mysql.deadlock_history table and mysql.cluster_deadlocks_summary view.
+	version74 = 74
+	// version75 adds the password management columns to mysql.user, and adds mysql.password_history and
+	// mysql.login_failures tables.
+	version75 = 75
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version72
+var currentBootstrapVersion int64 = version75
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -578,6 +671,9 @@ var (
 		upgradeToVer70,
 		upgradeToVer71,
 		upgradeToVer72,
+		upgradeToVer73,
+		upgradeToVer74,
+		upgradeToVer75,
 	}
 )
 
@@ -1529,6 +1625,37 @@ func upgradeToVer72(s Session, ver int64) {
 	doReentrantDDL(s, "ALTER TABLE mysql.stats_meta ADD COLUMN snapshot BIGINT(64) UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
 }
 
+func upgradeToVer73(s Session, ver int64) {
+	if ver >= version73 {
+		return
+	}
+	doReentrantDDL(s, CreateStmtSummaryHistoryTable)
+	doReentrantDDL(s, CreateStmtSummaryHistoryAllView)
+}
+
+func upgradeToVer74(s Session, ver int64) {
+	if ver >= version74 {
+		return
+	}
+	doReentrantDDL(s, CreateDeadlockHistoryTable)
+	doReentrantDDL(s, CreateClusterDeadlocksSummaryView)
+}
+
+func upgradeToVer75(s Session, ver int64) {
+	if ver >= version75 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N'", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP()", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_lifetime` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Failed_login_attempts` SMALLINT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lock_time` SMALLINT NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+	doReentrantDDL(s, CreateLoginFailuresTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1607,6 +1734,15 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateStatsFMSketchTable)
 	// Create global_grants
 	mustExecute(s, CreateGlobalGrantsTable)
+	// Create statements_summary_history and the view over it.
+	mustExecute(s, CreateStmtSummaryHistoryTable)
+	mustExecute(s, CreateStmtSummaryHistoryAllView)
+	// Create deadlock_history and the view over it.
+	mustExecute(s, CreateDeadlockHistoryTable)
+	mustExecute(s, CreateClusterDeadlocksSummaryView)
+	// Create password_history and login_failures tables.
+	mustExecute(s, CreatePasswordHistoryTable)
+	mustExecute(s, CreateLoginFailuresTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1617,7 +1753,7 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", DEFAULT, NULL, NULL, NULL, 0, 0)`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.GetSysVars()))
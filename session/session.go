@@ -572,10 +572,20 @@ func (s *session) commitTxnWithTemporaryData(ctx context.Context, txn kv.Transac
 			continue
 		}
 
-		if tbl.GetMeta().TempTableType != model.TempTableLocal {
+		if !tableutil.KeepsSessionData(tbl.GetMeta().TempTableType) {
 			continue
 		}
 
+		// Global temporary tables don't init the session data when they are created.
+		if sessionData == nil {
+			bufferTxn, err := s.store.BeginWithOption(tikv.DefaultStartTSOption().SetStartTS(0))
+			if err != nil {
+				return err
+			}
+			sessionData = bufferTxn.GetMemBuffer()
+			s.sessionVars.TemporaryTableData = sessionData
+		}
+
 		if stage == kv.InvalidStagingHandle {
 			stage = sessionData.Staging()
 		}
@@ -621,11 +631,118 @@ func (s *session) commitTxnWithTemporaryData(ctx context.Context, txn kv.Transac
 	if stage != kv.InvalidStagingHandle {
 		sessionData.Release(stage)
 		stage = kv.InvalidStagingHandle
+		s.sessionVars.UpdateTemporaryTableSizes(txnTempTables)
+		s.spillTemporaryTableData(txnTempTables)
 	}
 
 	return nil
 }
 
+// spillTemporaryTableData moves the committed data of the global temporary tables with "on commit preserve rows"
+// from the session memory to disk when it exceeds tmp_table_size. The transaction has been committed,
+// so the data is kept in memory if it fails to spill.
+func (s *session) spillTemporaryTableData(txnTempTables map[int64]tableutil.TempTable) {
+	sessVars := s.sessionVars
+	spilled := make(map[int64]*tableutil.TempTableDiskData)
+	closeSpilled := func() {
+		for _, diskData := range spilled {
+			terror.Call(diskData.Close)
+		}
+	}
+	for tblID, tbl := range txnTempTables {
+		if tbl.GetMeta().TempTableType != model.TempTableGlobalPreserveRows || sessVars.TemporaryTableSizes[tblID] <= sessVars.TMPTableSize {
+			continue
+		}
+		diskData, err := s.spillTemporaryTable(tblID)
+		if err != nil {
+			logutil.BgLogger().Warn("spill temporary table data failed", zap.Int64("tableID", tblID), zap.Error(err))
+			closeSpilled()
+			return
+		}
+		spilled[tblID] = diskData
+	}
+	if len(spilled) == 0 {
+		return
+	}
+
+	// The memory of the mem buffer can't be released by deleting keys, so rebuild it without the spilled tables.
+	sessionData, err := s.rebuildTemporaryTableData(spilled)
+	if err != nil {
+		logutil.BgLogger().Warn("rebuild temporary table data failed", zap.Error(err))
+		closeSpilled()
+		return
+	}
+	sessVars.TemporaryTableData = sessionData
+	if sessVars.TemporaryTableDiskData == nil {
+		sessVars.TemporaryTableDiskData = make(map[int64]*tableutil.TempTableDiskData)
+	}
+	for tblID, diskData := range spilled {
+		if oldData := sessVars.TemporaryTableDiskData[tblID]; oldData != nil {
+			terror.Call(oldData.Close)
+		}
+		sessVars.TemporaryTableDiskData[tblID] = diskData
+		sessVars.TemporaryTableSizes[tblID] = 0
+		logutil.BgLogger().Info("spill temporary table data to disk", zap.Uint64("conn", sessVars.ConnectionID),
+			zap.Int64("tableID", tblID), zap.Int64("diskUsage", diskData.DiskUsage()))
+	}
+}
+
+// spillTemporaryTable writes all the committed data of a temporary table to a new file,
+// including the data which has been spilled before.
+func (s *session) spillTemporaryTable(tblID int64) (*tableutil.TempTableDiskData, error) {
+	tblPrefix := tablecodec.EncodeTablePrefix(tblID)
+	endKey := tablecodec.EncodeTablePrefix(tblID + 1)
+	var iter kv.Iterator
+	iter, err := s.sessionVars.TemporaryTableData.Iter(tblPrefix, endKey)
+	if err != nil {
+		return nil, err
+	}
+	if oldData := s.sessionVars.TemporaryTableDiskData[tblID]; oldData != nil {
+		spilledIter, err := oldData.Iter(tblPrefix, endKey)
+		if err != nil {
+			iter.Close()
+			return nil, err
+		}
+		if iter, err = tableutil.NewUnionIter(iter, spilledIter); err != nil {
+			return nil, err
+		}
+	}
+	defer iter.Close()
+	return tableutil.NewTempTableDiskData(iter)
+}
+
+// rebuildTemporaryTableData copies the committed data of the temporary tables which are not spilled to a new mem buffer.
+func (s *session) rebuildTemporaryTableData(spilled map[int64]*tableutil.TempTableDiskData) (kv.MemBuffer, error) {
+	bufferTxn, err := s.store.BeginWithOption(tikv.DefaultStartTSOption().SetStartTS(0))
+	if err != nil {
+		return nil, err
+	}
+	sessionData := bufferTxn.GetMemBuffer()
+	iter, err := s.sessionVars.TemporaryTableData.Iter(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	for iter.Valid() {
+		key := iter.Key()
+		if _, ok := spilled[tablecodec.DecodeTableID(key)]; !ok {
+			// Keep the deleted keys, they may overwrite the data spilled before.
+			if value := iter.Value(); len(value) == 0 {
+				err = sessionData.Delete(key)
+			} else {
+				err = sessionData.Set(key, value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err = iter.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return sessionData, nil
+}
+
 type temporaryTableKVFilter map[int64]tableutil.TempTable
 
 func (m temporaryTableKVFilter) IsUnnecessaryKeyValue(key, value []byte, flags tikvstore.KeyFlags) bool {
@@ -1952,6 +2069,11 @@ func (s *session) IsCachedExecOk(ctx context.Context, preparedStmt *plannercore.
 			return false, nil
 		}
 	}
+	// check whether the bindings are changed
+	if !plannercore.CheckCachedPointPlanBinding(s, preparedStmt) {
+		prepared.CachedPlan = nil
+		return false, nil
+	}
 	// maybe we'd better check cached plan type here, current
 	// only point select/update will be cached, see "getPhysicalPlan" func
 	var ok bool
@@ -2220,6 +2342,13 @@ func (s *session) Close() {
 	s.RollbackTxn(ctx)
 	if s.sessionVars != nil {
 		s.sessionVars.WithdrawAllPreparedStmt()
+		// Release the rows of the temporary tables which are only visible to this session.
+		s.sessionVars.TemporaryTableData = nil
+		s.sessionVars.TemporaryTableSizes = nil
+		for _, diskData := range s.sessionVars.TemporaryTableDiskData {
+			terror.Call(diskData.Close)
+		}
+		s.sessionVars.TemporaryTableDiskData = nil
 	}
 }
 
@@ -2581,6 +2710,20 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.StmtSummary.EnablePersistent {
+		se8, err := createSession(store)
+		if err != nil {
+			return nil, err
+		}
+		dom.StmtSummaryPersistLoop(se8)
+	}
+	if cfg.PessimisticTxn.DeadlockHistoryEnablePersistent {
+		se9, err := createSession(store)
+		if err != nil {
+			return nil, err
+		}
+		dom.DeadlockHistoryPersistLoop(se9)
+	}
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
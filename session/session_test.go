@@ -4265,6 +4265,94 @@ func (s *testSessionSuite3) TestGlobalTemporaryTable(c *C) {
 	tk.MustQuery("select * from g_tmp").Check(testkit.Rows())
 }
 
+func (s *testSessionSuite3) TestGlobalTemporaryTablePreserveRows(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("set tidb_enable_global_temporary_table=true")
+	tk.MustExec("create global temporary table g_tmp (a int primary key, b int, c int, index i_b(b)) on commit preserve rows")
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp values (3, 3, 3)")
+	tk.MustExec("insert into g_tmp values (4, 7, 9)")
+	tk.MustExec("commit")
+
+	// The data is kept after the transaction commit.
+	// Cover table scan.
+	tk.MustQuery("select * from g_tmp").Check(testkit.Rows("3 3 3", "4 7 9"))
+	// Cover index reader.
+	tk.MustQuery("select b from g_tmp where b > 3").Check(testkit.Rows("7"))
+	// Cover index lookup.
+	tk.MustQuery("select c from g_tmp where b = 3").Check(testkit.Rows("3"))
+	// Cover point get.
+	tk.MustQuery("select * from g_tmp where a = 3").Check(testkit.Rows("3 3 3"))
+	// Cover batch point get.
+	tk.MustQuery("select * from g_tmp where a in (2,3,4)").Check(testkit.Rows("3 3 3", "4 7 9"))
+
+	// The data in the txn is merged with the committed data.
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp values (1, 1, 1)")
+	tk.MustExec("update g_tmp set c = 10 where a = 4")
+	tk.MustExec("delete from g_tmp where a = 3")
+	tk.MustQuery("select * from g_tmp").Check(testkit.Rows("1 1 1", "4 7 10"))
+	tk.MustQuery("select * from g_tmp where a in (1,3,4)").Check(testkit.Rows("1 1 1", "4 7 10"))
+	tk.MustQuery("select c from g_tmp use index(i_b) where b < 10").Check(testkit.Rows("1", "10"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from g_tmp").Check(testkit.Rows("3 3 3", "4 7 9"))
+
+	tk.MustExec("update g_tmp set c = 10 where a = 4")
+	tk.MustExec("delete from g_tmp where a = 3")
+	tk.MustQuery("select * from g_tmp").Check(testkit.Rows("4 7 10"))
+	tk.MustQuery("select * from g_tmp where b = 3").Check(testkit.Rows())
+	tk.MustGetErrCode("insert into g_tmp values (4, 4, 4)", errno.ErrDupEntry)
+
+	// The data is only visible to the owning session.
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.MustQuery("select * from g_tmp").Check(testkit.Rows())
+	tk2.MustQuery("select * from g_tmp where a = 4").Check(testkit.Rows())
+	tk2.MustExec("insert into g_tmp values (4, 4, 4)")
+	tk2.MustQuery("select * from g_tmp").Check(testkit.Rows("4 4 4"))
+	tk.MustQuery("select * from g_tmp").Check(testkit.Rows("4 7 10"))
+
+	// The data beyond tmp_table_size is spilled to disk.
+	tk.MustExec("create global temporary table g_tmp2 (a int primary key, b varchar(255), c int, index i_c(c)) on commit preserve rows")
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("g_tmp2"))
+	c.Assert(err, IsNil)
+	tblID := tbl.Meta().ID
+	tk.MustExec("set @@tmp_table_size = 1024")
+	tk.MustExec("insert into g_tmp2 values (1, repeat('a', 255), 1), (2, repeat('b', 255), 2)")
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableDiskData[tblID], IsNil)
+	tk.MustExec("insert into g_tmp2 values (3, repeat('c', 255), 3), (4, repeat('d', 255), 4), (5, repeat('e', 255), 5)")
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableDiskData[tblID], NotNil)
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableSizes[tblID], Equals, int64(0))
+	tk.MustQuery("select a, left(b, 1) from g_tmp2").Check(testkit.Rows("1 a", "2 b", "3 c", "4 d", "5 e"))
+	tk.MustQuery("select a, left(b, 1) from g_tmp2 where c = 3").Check(testkit.Rows("3 c"))
+	tk.MustQuery("select c from g_tmp2 where c > 3").Check(testkit.Rows("4", "5"))
+	tk.MustQuery("select left(b, 1) from g_tmp2 where a = 4").Check(testkit.Rows("d"))
+	tk.MustQuery("select a, left(b, 1) from g_tmp2 where a in (1, 5, 6)").Check(testkit.Rows("1 a", "5 e"))
+	tk.MustGetErrCode("insert into g_tmp2 values (1, 'a', 1)", errno.ErrDupEntry)
+
+	// The data in memory overwrites the data spilled to disk.
+	tk.MustExec("delete from g_tmp2 where a = 2")
+	tk.MustExec("update g_tmp2 set c = 40 where a = 4")
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableSizes[tblID] > 0, IsTrue)
+	tk.MustQuery("select a, left(b, 1), c from g_tmp2").Check(testkit.Rows("1 a 1", "3 c 3", "4 d 40", "5 e 5"))
+	tk.MustQuery("select a from g_tmp2 where c = 40").Check(testkit.Rows("4"))
+	tk.MustQuery("select a from g_tmp2 where c = 4").Check(testkit.Rows())
+	tk.MustQuery("select a from g_tmp2 where a = 2").Check(testkit.Rows())
+	tk.MustQuery("select a, c from g_tmp2 where a in (2, 4)").Check(testkit.Rows("4 40"))
+
+	// The data spilled before is merged with the data in memory when it's spilled again.
+	tk.MustExec("insert into g_tmp2 values (2, repeat('y', 255), 20), (6, repeat('f', 255), 6), (7, repeat('g', 255), 7)")
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableSizes[tblID], Equals, int64(0))
+	tk.MustQuery("select a, left(b, 1), c from g_tmp2").Check(testkit.Rows("1 a 1", "2 y 20", "3 c 3", "4 d 40", "5 e 5", "6 f 6", "7 g 7"))
+	tk.MustQuery("select a from g_tmp2 use index(i_c) where c > 4").Check(testkit.Rows("5", "6", "7", "2", "4"))
+
+	// The data of the other sessions is not affected.
+	tk2.MustQuery("select * from g_tmp2").Check(testkit.Rows())
+
+	// The data is released when the session is closed.
+	tk.Se.Close()
+	c.Assert(tk.Se.GetSessionVars().TemporaryTableData, IsNil)
+}
+
 type testTxnStateSerialSuite struct {
 	testSessionSuiteBase
 }
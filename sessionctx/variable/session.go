@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
@@ -42,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
@@ -442,6 +444,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// PasswordExpired is true if the session logged in with an expired password, the session is in the sandbox mode
+	// until the password is reset, see https://dev.mysql.com/doc/refman/8.0/en/expired-password-handling.html.
+	PasswordExpired bool
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -683,6 +689,10 @@ type SessionVars struct {
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
+	// QueryAttributes is the query attributes sent by the client with the current command, the attributes whose
+	// values are NULL are not included. It's nil if the client doesn't send any attribute.
+	QueryAttributes map[string]string
+
 	// use noop funcs or not
 	EnableNoopFuncs bool
 
@@ -767,6 +777,19 @@ type SessionVars struct {
 	// see https://dev.mysql.com/doc/refman/8.0/en/window-function-optimization.html for more details.
 	WindowingUseHighPrecision bool
 
+	// ForeignKeyChecks indicates whether the foreign key constraints are checked and their referential actions are applied
+	// when writing rows.
+	ForeignKeyChecks bool
+
+	// SessionTrackSystemVariables is the names of the system variables whose changes are tracked, "*" means all.
+	SessionTrackSystemVariables []string
+	// SessionTrackSchema indicates whether the changes of the current schema are tracked.
+	SessionTrackSchema bool
+	// SessionTrackTransactionInfo indicates how the transaction information is tracked, it's OFF, STATE or CHARACTERISTICS.
+	SessionTrackTransactionInfo string
+	// StateTracker records the changes of the session states which are reported to the client.
+	StateTracker SessionStateTracker
+
 	// FoundInPlanCache indicates whether this statement was found in plan cache.
 	FoundInPlanCache bool
 	// PrevFoundInPlanCache indicates whether the last statement was found in plan cache.
@@ -780,6 +803,10 @@ type SessionVars struct {
 	// OptimizerUseInvisibleIndexes indicates whether optimizer can use invisible index
 	OptimizerUseInvisibleIndexes bool
 
+	// HypoIndexes are the hypothetical indexes which are only visible to the optimizer, keyed by table ID.
+	// They are used by the index advisor to estimate the benefit of an index without creating it.
+	HypoIndexes map[int64][]*model.IndexInfo
+
 	// SelectLimit limits the max counts of select statement's output
 	SelectLimit uint64
 
@@ -857,12 +884,23 @@ type SessionVars struct {
 	// EnableStableResultMode if stabilize query results.
 	EnableStableResultMode bool
 
+	// EnableForeignKey indicates whether the foreign keys created by the session are enforced.
+	EnableForeignKey bool
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
 
 	// TemporaryTableData stores committed kv values for temporary table for current session.
 	TemporaryTableData kv.MemBuffer
+
+	// TemporaryTableSizes stores the size of the committed data in TemporaryTableData for each temporary table,
+	// so that tmp_table_size limits the data of a table across transactions.
+	TemporaryTableSizes map[int64]int64
+
+	// TemporaryTableDiskData stores the committed data of the temporary tables which is spilled to disk
+	// because it exceeds tmp_table_size. The data in TemporaryTableData overwrites it.
+	TemporaryTableDiskData map[int64]*tableutil.TempTableDiskData
 }
 
 // AllocMPPTaskID allocates task id for mpp tasks. It will reset the task id if the query's
@@ -1052,6 +1090,9 @@ func NewSessionVars() *SessionVars {
 		MetricSchemaRangeDuration:   DefTiDBMetricSchemaRangeDuration,
 		SequenceState:               NewSequenceState(),
 		WindowingUseHighPrecision:   true,
+		SessionTrackSystemVariables: strings.Split(DefSessionTrackSystemVariables, ","),
+		SessionTrackSchema:          true,
+		SessionTrackTransactionInfo: Off,
 		PrevFoundInPlanCache:        DefTiDBFoundInPlanCache,
 		FoundInPlanCache:            DefTiDBFoundInPlanCache,
 		PrevFoundInBinding:          DefTiDBFoundInBinding,
@@ -1500,6 +1541,9 @@ func (s *SessionVars) GetTemporaryTable(tblInfo *model.TableInfo) tableutil.Temp
 		tempTable, ok := tempTables[tblInfo.ID]
 		if !ok {
 			tempTable = tableutil.TempTableFromMeta(tblInfo)
+			if size, ok := s.TemporaryTableSizes[tblInfo.ID]; ok {
+				tempTable.SetSize(size)
+			}
 			tempTables[tblInfo.ID] = tempTable
 		}
 		return tempTable
@@ -1508,6 +1552,19 @@ func (s *SessionVars) GetTemporaryTable(tblInfo *model.TableInfo) tableutil.Temp
 	return nil
 }
 
+// UpdateTemporaryTableSizes records the sizes of the temporary tables whose data is committed into TemporaryTableData.
+func (s *SessionVars) UpdateTemporaryTableSizes(tempTables map[int64]tableutil.TempTable) {
+	for tblID, tbl := range tempTables {
+		if !tbl.GetModified() || !tableutil.KeepsSessionData(tbl.GetMeta().TempTableType) {
+			continue
+		}
+		if s.TemporaryTableSizes == nil {
+			s.TemporaryTableSizes = make(map[int64]int64)
+		}
+		s.TemporaryTableSizes[tblID] = tbl.GetSize()
+	}
+}
+
 // special session variables.
 const (
 	SQLModeVar           = "sql_mode"
@@ -1778,6 +1835,8 @@ const (
 	SlowLogTimeStr = "Time"
 	// SlowLogStartPrefixStr is slow log start row prefix.
 	SlowLogStartPrefixStr = SlowLogRowPrefixStr + SlowLogTimeStr + SlowLogSpaceMarkStr
+	// SlowLogJSONStartPrefixStr is the prefix of slow log entries in JSON format, the time is always the first key.
+	SlowLogJSONStartPrefixStr = `{"` + SlowLogTimeStr + `":"`
 	// SlowLogTxnStartTSStr is slow log field name.
 	SlowLogTxnStartTSStr = "Txn_start_ts"
 	// SlowLogUserAndHostStr is the user and host field name, which is compatible with MySQL.
@@ -1856,6 +1915,10 @@ const (
 	SlowLogPlan = "Plan"
 	// SlowLogPlanDigest is used to record the query plan digest.
 	SlowLogPlanDigest = "Plan_digest"
+	// SlowLogQueryAttributes is used to record the query attributes sent by the client.
+	SlowLogQueryAttributes = "Query_attributes"
+	// SlowLogQueryAttributesPrefix is the prefix of Query_attributes in slow log file.
+	SlowLogQueryAttributesPrefix = SlowLogQueryAttributes + SlowLogSpaceMarkStr
 	// SlowLogPlanPrefix is the prefix of the plan value.
 	SlowLogPlanPrefix = ast.TiDBDecodePlan + "('"
 	// SlowLogPlanSuffix is the suffix of the plan value.
@@ -1903,6 +1966,7 @@ type SlowQueryLogItems struct {
 	PrevStmt          string
 	Plan              string
 	PlanDigest        string
+	QueryAttributes   map[string]string
 	RewriteInfo       RewritePhaseInfo
 	KVTotal           time.Duration
 	PDTotal           time.Duration
@@ -1935,61 +1999,67 @@ type SlowQueryLogItems struct {
 // select * from t_slim;
 func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	var buf bytes.Buffer
+	s.writeSlowLog(&textSlowLogWriter{buf: &buf}, logItems)
+	return buf.String()
+}
+
+// SlowLogJSONFormat uses for formatting slow log as a JSON object, whose keys are the same as the field names
+// in the slow log formatted by SlowLogFormat, and whose values are all strings. The query is kept in the key
+// "Query" and the time is left to the slow log encoder. The output is like below:
+// {"Txn_start_ts":"406315658548871171","User@Host":"root[root] @ localhost [127.0.0.1]",...,"Query":"select * from t_slim;"}
+func (s *SessionVars) SlowLogJSONFormat(logItems *SlowQueryLogItems) string {
+	w := &jsonSlowLogWriter{}
+	s.writeSlowLog(w, logItems)
+	return w.String()
+}
 
-	writeSlowLogItem(&buf, SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
+func (s *SessionVars) writeSlowLog(w slowLogWriter, logItems *SlowQueryLogItems) {
+	w.writeItems(SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
 	if s.User != nil {
 		hostAddress := s.User.Hostname
 		if s.ConnectionInfo != nil {
 			hostAddress = s.ConnectionInfo.ClientIP
 		}
-		writeSlowLogItem(&buf, SlowLogUserAndHostStr, fmt.Sprintf("%s[%s] @ %s [%s]", s.User.Username, s.User.Username, s.User.Hostname, hostAddress))
+		w.writeItems(SlowLogUserAndHostStr, fmt.Sprintf("%s[%s] @ %s [%s]", s.User.Username, s.User.Username, s.User.Hostname, hostAddress))
 	}
 	if s.ConnectionID != 0 {
-		writeSlowLogItem(&buf, SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
+		w.writeItems(SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
 	}
 	if logItems.ExecRetryCount > 0 {
-		buf.WriteString(SlowLogRowPrefixStr)
-		buf.WriteString(SlowLogExecRetryTime)
-		buf.WriteString(SlowLogSpaceMarkStr)
-		buf.WriteString(strconv.FormatFloat(logItems.ExecRetryTime.Seconds(), 'f', -1, 64))
-		buf.WriteString(" ")
-		buf.WriteString(SlowLogExecRetryCount)
-		buf.WriteString(SlowLogSpaceMarkStr)
-		buf.WriteString(strconv.Itoa(int(logItems.ExecRetryCount)))
-		buf.WriteString("\n")
-	}
-	writeSlowLogItem(&buf, SlowLogQueryTimeStr, strconv.FormatFloat(logItems.TimeTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogParseTimeStr, strconv.FormatFloat(logItems.TimeParse.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogCompileTimeStr, strconv.FormatFloat(logItems.TimeCompile.Seconds(), 'f', -1, 64))
-
-	buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v", SlowLogRewriteTimeStr,
-		SlowLogSpaceMarkStr, strconv.FormatFloat(logItems.RewriteInfo.DurationRewrite.Seconds(), 'f', -1, 64)))
+		w.writeItems(SlowLogExecRetryTime, strconv.FormatFloat(logItems.ExecRetryTime.Seconds(), 'f', -1, 64),
+			SlowLogExecRetryCount, strconv.Itoa(int(logItems.ExecRetryCount)))
+	}
+	w.writeItems(SlowLogQueryTimeStr, strconv.FormatFloat(logItems.TimeTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogParseTimeStr, strconv.FormatFloat(logItems.TimeParse.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogCompileTimeStr, strconv.FormatFloat(logItems.TimeCompile.Seconds(), 'f', -1, 64))
+
+	rewriteItems := []string{SlowLogRewriteTimeStr, strconv.FormatFloat(logItems.RewriteInfo.DurationRewrite.Seconds(), 'f', -1, 64)}
 	if logItems.RewriteInfo.PreprocessSubQueries > 0 {
-		buf.WriteString(fmt.Sprintf(" %v%v%v %v%v%v", SlowLogPreprocSubQueriesStr, SlowLogSpaceMarkStr, logItems.RewriteInfo.PreprocessSubQueries,
-			SlowLogPreProcSubQueryTimeStr, SlowLogSpaceMarkStr, strconv.FormatFloat(logItems.RewriteInfo.DurationPreprocessSubQuery.Seconds(), 'f', -1, 64)))
+		rewriteItems = append(rewriteItems, SlowLogPreprocSubQueriesStr, strconv.Itoa(logItems.RewriteInfo.PreprocessSubQueries),
+			SlowLogPreProcSubQueryTimeStr, strconv.FormatFloat(logItems.RewriteInfo.DurationPreprocessSubQuery.Seconds(), 'f', -1, 64))
 	}
-	buf.WriteString("\n")
+	w.writeItems(rewriteItems...)
 
-	writeSlowLogItem(&buf, SlowLogOptimizeTimeStr, strconv.FormatFloat(logItems.TimeOptimize.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogWaitTSTimeStr, strconv.FormatFloat(logItems.TimeWaitTS.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogOptimizeTimeStr, strconv.FormatFloat(logItems.TimeOptimize.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogWaitTSTimeStr, strconv.FormatFloat(logItems.TimeWaitTS.Seconds(), 'f', -1, 64))
 
 	if execDetailStr := logItems.ExecDetail.String(); len(execDetailStr) > 0 {
-		buf.WriteString(SlowLogRowPrefixStr + execDetailStr + "\n")
+		w.writeItems(splitSlowLogItems(execDetailStr)...)
 	}
 
 	if len(s.CurrentDB) > 0 {
-		writeSlowLogItem(&buf, SlowLogDBStr, s.CurrentDB)
+		w.writeItems(SlowLogDBStr, s.CurrentDB)
 	}
 	if len(logItems.IndexNames) > 0 {
-		writeSlowLogItem(&buf, SlowLogIndexNamesStr, logItems.IndexNames)
+		w.writeItems(SlowLogIndexNamesStr, logItems.IndexNames)
 	}
 
-	writeSlowLogItem(&buf, SlowLogIsInternalStr, strconv.FormatBool(s.InRestrictedSQL))
+	w.writeItems(SlowLogIsInternalStr, strconv.FormatBool(s.InRestrictedSQL))
 	if len(logItems.Digest) > 0 {
-		writeSlowLogItem(&buf, SlowLogDigestStr, logItems.Digest)
+		w.writeItems(SlowLogDigestStr, logItems.Digest)
 	}
 	if len(logItems.StatsInfos) > 0 {
-		buf.WriteString(SlowLogRowPrefixStr + SlowLogStatsInfoStr + SlowLogSpaceMarkStr)
+		var statsBuf strings.Builder
 		firstComma := false
 		vStr := ""
 		for k, v := range logItems.StatsInfos {
@@ -2000,16 +2070,16 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 
 			}
 			if firstComma {
-				buf.WriteString("," + k + ":" + vStr)
+				statsBuf.WriteString("," + k + ":" + vStr)
 			} else {
-				buf.WriteString(k + ":" + vStr)
+				statsBuf.WriteString(k + ":" + vStr)
 				firstComma = true
 			}
 		}
-		buf.WriteString("\n")
+		w.writeItems(SlowLogStatsInfoStr, statsBuf.String())
 	}
 	if logItems.CopTasks != nil {
-		writeSlowLogItem(&buf, SlowLogNumCopTasksStr, strconv.FormatInt(int64(logItems.CopTasks.NumCopTasks), 10))
+		w.writeItems(SlowLogNumCopTasksStr, strconv.FormatInt(int64(logItems.CopTasks.NumCopTasks), 10))
 		if logItems.CopTasks.NumCopTasks > 0 {
 			// make the result stable
 			backoffs := make([]string, 0, 3)
@@ -2019,86 +2089,186 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 			sort.Strings(backoffs)
 
 			if logItems.CopTasks.NumCopTasks == 1 {
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v",
-					SlowLogCopProcAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgProcessTime.Seconds(),
-					SlowLogCopProcAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessAddress) + "\n")
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v",
-					SlowLogCopWaitAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgWaitTime.Seconds(),
-					SlowLogCopWaitAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitAddress) + "\n")
+				w.writeItems(
+					SlowLogCopProcAvg, fmt.Sprint(logItems.CopTasks.AvgProcessTime.Seconds()),
+					SlowLogCopProcAddr, logItems.CopTasks.MaxProcessAddress)
+				w.writeItems(
+					SlowLogCopWaitAvg, fmt.Sprint(logItems.CopTasks.AvgWaitTime.Seconds()),
+					SlowLogCopWaitAddr, logItems.CopTasks.MaxWaitAddress)
 				for _, backoff := range backoffs {
 					backoffPrefix := SlowLogCopBackoffPrefix + backoff + "_"
-					buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v\n",
-						backoffPrefix+"total_times", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTimes[backoff],
-						backoffPrefix+"total_time", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTime[backoff].Seconds(),
-					))
+					w.writeItems(
+						backoffPrefix+"total_times", fmt.Sprint(logItems.CopTasks.TotBackoffTimes[backoff]),
+						backoffPrefix+"total_time", fmt.Sprint(logItems.CopTasks.TotBackoffTime[backoff].Seconds()),
+					)
 				}
 			} else {
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v",
-					SlowLogCopProcAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgProcessTime.Seconds(),
-					SlowLogCopProcP90, SlowLogSpaceMarkStr, logItems.CopTasks.P90ProcessTime.Seconds(),
-					SlowLogCopProcMax, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessTime.Seconds(),
-					SlowLogCopProcAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessAddress) + "\n")
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v",
-					SlowLogCopWaitAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgWaitTime.Seconds(),
-					SlowLogCopWaitP90, SlowLogSpaceMarkStr, logItems.CopTasks.P90WaitTime.Seconds(),
-					SlowLogCopWaitMax, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitTime.Seconds(),
-					SlowLogCopWaitAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitAddress) + "\n")
+				w.writeItems(
+					SlowLogCopProcAvg, fmt.Sprint(logItems.CopTasks.AvgProcessTime.Seconds()),
+					SlowLogCopProcP90, fmt.Sprint(logItems.CopTasks.P90ProcessTime.Seconds()),
+					SlowLogCopProcMax, fmt.Sprint(logItems.CopTasks.MaxProcessTime.Seconds()),
+					SlowLogCopProcAddr, logItems.CopTasks.MaxProcessAddress)
+				w.writeItems(
+					SlowLogCopWaitAvg, fmt.Sprint(logItems.CopTasks.AvgWaitTime.Seconds()),
+					SlowLogCopWaitP90, fmt.Sprint(logItems.CopTasks.P90WaitTime.Seconds()),
+					SlowLogCopWaitMax, fmt.Sprint(logItems.CopTasks.MaxWaitTime.Seconds()),
+					SlowLogCopWaitAddr, logItems.CopTasks.MaxWaitAddress)
 				for _, backoff := range backoffs {
 					backoffPrefix := SlowLogCopBackoffPrefix + backoff + "_"
-					buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v %v%v%v %v%v%v\n",
-						backoffPrefix+"total_times", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTimes[backoff],
-						backoffPrefix+"total_time", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTime[backoff].Seconds(),
-						backoffPrefix+"max_time", SlowLogSpaceMarkStr, logItems.CopTasks.MaxBackoffTime[backoff].Seconds(),
-						backoffPrefix+"max_addr", SlowLogSpaceMarkStr, logItems.CopTasks.MaxBackoffAddress[backoff],
-						backoffPrefix+"avg_time", SlowLogSpaceMarkStr, logItems.CopTasks.AvgBackoffTime[backoff].Seconds(),
-						backoffPrefix+"p90_time", SlowLogSpaceMarkStr, logItems.CopTasks.P90BackoffTime[backoff].Seconds(),
-					))
+					w.writeItems(
+						backoffPrefix+"total_times", fmt.Sprint(logItems.CopTasks.TotBackoffTimes[backoff]),
+						backoffPrefix+"total_time", fmt.Sprint(logItems.CopTasks.TotBackoffTime[backoff].Seconds()),
+						backoffPrefix+"max_time", fmt.Sprint(logItems.CopTasks.MaxBackoffTime[backoff].Seconds()),
+						backoffPrefix+"max_addr", logItems.CopTasks.MaxBackoffAddress[backoff],
+						backoffPrefix+"avg_time", fmt.Sprint(logItems.CopTasks.AvgBackoffTime[backoff].Seconds()),
+						backoffPrefix+"p90_time", fmt.Sprint(logItems.CopTasks.P90BackoffTime[backoff].Seconds()),
+					)
 				}
 			}
 		}
 	}
 	if logItems.MemMax > 0 {
-		writeSlowLogItem(&buf, SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
+		w.writeItems(SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
 	}
 	if logItems.DiskMax > 0 {
-		writeSlowLogItem(&buf, SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
-	}
-
-	writeSlowLogItem(&buf, SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
-	writeSlowLogItem(&buf, SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
-	writeSlowLogItem(&buf, SlowLogPlanFromBinding, strconv.FormatBool(logItems.PlanFromBinding))
-	writeSlowLogItem(&buf, SlowLogHasMoreResults, strconv.FormatBool(logItems.HasMoreResults))
-	writeSlowLogItem(&buf, SlowLogKVTotal, strconv.FormatFloat(logItems.KVTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogPDTotal, strconv.FormatFloat(logItems.PDTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogBackoffTotal, strconv.FormatFloat(logItems.BackoffTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogWriteSQLRespTotal, strconv.FormatFloat(logItems.WriteSQLRespTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogSucc, strconv.FormatBool(logItems.Succ))
+		w.writeItems(SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
+	}
+
+	w.writeItems(SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
+	w.writeItems(SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
+	w.writeItems(SlowLogPlanFromBinding, strconv.FormatBool(logItems.PlanFromBinding))
+	w.writeItems(SlowLogHasMoreResults, strconv.FormatBool(logItems.HasMoreResults))
+	w.writeItems(SlowLogKVTotal, strconv.FormatFloat(logItems.KVTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogPDTotal, strconv.FormatFloat(logItems.PDTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogBackoffTotal, strconv.FormatFloat(logItems.BackoffTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogWriteSQLRespTotal, strconv.FormatFloat(logItems.WriteSQLRespTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogSucc, strconv.FormatBool(logItems.Succ))
 	if len(logItems.Plan) != 0 {
-		writeSlowLogItem(&buf, SlowLogPlan, logItems.Plan)
+		w.writeItems(SlowLogPlan, logItems.Plan)
 	}
 	if len(logItems.PlanDigest) != 0 {
-		writeSlowLogItem(&buf, SlowLogPlanDigest, logItems.PlanDigest)
+		w.writeItems(SlowLogPlanDigest, logItems.PlanDigest)
+	}
+	if len(logItems.QueryAttributes) != 0 {
+		// The attributes are encoded as a JSON object to keep them in one line.
+		if attrs, err := json.Marshal(logItems.QueryAttributes); err == nil {
+			w.writeItems(SlowLogQueryAttributes, string(attrs))
+		}
 	}
 
 	if logItems.PrevStmt != "" {
-		writeSlowLogItem(&buf, SlowLogPrevStmt, logItems.PrevStmt)
+		w.writeItems(SlowLogPrevStmt, logItems.PrevStmt)
 	}
 
 	if s.CurrentDBChanged {
-		buf.WriteString(fmt.Sprintf("use %s;\n", s.CurrentDB))
+		w.writeUseDB(s.CurrentDB)
 		s.CurrentDBChanged = false
 	}
 
-	buf.WriteString(logItems.SQL)
-	if len(logItems.SQL) == 0 || logItems.SQL[len(logItems.SQL)-1] != ';' {
-		buf.WriteString(";")
+	sql := logItems.SQL
+	if len(sql) == 0 || sql[len(sql)-1] != ';' {
+		sql += ";"
 	}
-	return buf.String()
+	w.writeSQL(sql)
+}
+
+// slowLogWriter writes the items of a slow log entry in a specific format.
+type slowLogWriter interface {
+	// writeItems writes a group of items, kvs are the keys and values of them in turn.
+	writeItems(kvs ...string)
+	// writeUseDB writes the `use DB` statement when the current DB is changed.
+	writeUseDB(db string)
+	// writeSQL writes the SQL, it's the last item of a slow log entry.
+	writeSQL(sql string)
 }
 
-// writeSlowLogItem writes a slow log item in the form of: "# ${key}:${value}"
-func writeSlowLogItem(buf *bytes.Buffer, key, value string) {
-	buf.WriteString(SlowLogRowPrefixStr + key + SlowLogSpaceMarkStr + value + "\n")
+// textSlowLogWriter writes each group of items in a line like "# ${key1}: ${value1} ${key2}: ${value2}",
+// and the SQL in the last line.
+type textSlowLogWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *textSlowLogWriter) writeItems(kvs ...string) {
+	w.buf.WriteString(SlowLogRowPrefixStr)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if i > 0 {
+			w.buf.WriteString(" ")
+		}
+		w.buf.WriteString(kvs[i] + SlowLogSpaceMarkStr + kvs[i+1])
+	}
+	w.buf.WriteString("\n")
+}
+
+func (w *textSlowLogWriter) writeUseDB(db string) {
+	w.buf.WriteString(fmt.Sprintf("use %s;\n", db))
+}
+
+func (w *textSlowLogWriter) writeSQL(sql string) {
+	w.buf.WriteString(sql)
+}
+
+// jsonSlowLogWriter writes all items into a JSON object in order, the SQL is kept in the key "Query".
+type jsonSlowLogWriter struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+func (w *jsonSlowLogWriter) writeItems(kvs ...string) {
+	for i := 0; i+1 < len(kvs); i += 2 {
+		w.writeItem(kvs[i], kvs[i+1])
+	}
+}
+
+// writeUseDB does nothing because the current DB is already kept in the key "DB".
+func (w *jsonSlowLogWriter) writeUseDB(string) {}
+
+func (w *jsonSlowLogWriter) writeSQL(sql string) {
+	w.writeItem(SlowLogQuerySQLStr, sql)
+}
+
+func (w *jsonSlowLogWriter) writeItem(key, value string) {
+	if w.enc == nil {
+		w.enc = json.NewEncoder(&w.buf)
+		w.enc.SetEscapeHTML(false)
+		w.buf.WriteString("{")
+	} else {
+		w.buf.WriteString(",")
+	}
+	w.writeString(key)
+	w.buf.WriteString(":")
+	w.writeString(value)
+}
+
+func (w *jsonSlowLogWriter) writeString(s string) {
+	// Encoding a string never fails, and the encoder appends a newline which should be trimmed.
+	_ = w.enc.Encode(s)
+	w.buf.Truncate(w.buf.Len() - 1)
+}
+
+// String returns the JSON object.
+func (w *jsonSlowLogWriter) String() string {
+	if w.enc == nil {
+		return "{}"
+	}
+	return w.buf.String() + "}"
+}
+
+// splitSlowLogItems splits a line like "${key1}: ${value1} ${key2}: ${value2}" into keys and values in turn.
+// A value may contain spaces, such as the backoff types "[regionMiss txnLock]".
+func splitSlowLogItems(line string) []string {
+	fields := strings.Split(line, " ")
+	kvs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if strings.HasSuffix(field, ":") {
+			kvs = append(kvs, strings.TrimSuffix(field, ":"), "")
+		} else if len(kvs) > 0 {
+			if len(kvs[len(kvs)-1]) > 0 {
+				kvs[len(kvs)-1] += " "
+			}
+			kvs[len(kvs)-1] += field
+		}
+	}
+	return kvs
 }
 
 // QueryInfo represents the information of last executed query. It's used to expose information for test purpose.
@@ -2212,6 +2382,11 @@ func (s *SessionVars) GetTemporaryTableSnapshotValue(ctx context.Context, key kv
 	}
 
 	v, err := memData.Get(ctx, key)
+	if kv.IsErrNotFound(err) {
+		if diskData := s.TemporaryTableDiskData[tablecodec.DecodeTableID(key)]; diskData != nil {
+			return diskData.Get(key)
+		}
+	}
 	if err != nil {
 		return v, err
 	}
@@ -14,6 +14,8 @@
 package variable_test
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -267,6 +269,36 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 	logString = seVar.SlowLogFormat(logItems)
 	c.Assert(logString, Equals, resultFields+"\n"+"use test;\n"+sql)
 	c.Assert(seVar.CurrentDBChanged, IsFalse)
+
+	// The JSON format contains the same fields in the same order.
+	seVar.CurrentDBChanged = true
+	logString = seVar.SlowLogJSONFormat(logItems)
+	c.Assert(seVar.CurrentDBChanged, IsFalse)
+	c.Assert(strings.Contains(logString, "\n"), IsFalse)
+	dec := json.NewDecoder(strings.NewReader(logString))
+	_, err := dec.Token()
+	c.Assert(err, IsNil)
+	var keys []string
+	for dec.More() {
+		key, err := dec.Token()
+		c.Assert(err, IsNil)
+		var value string
+		c.Assert(dec.Decode(&value), IsNil)
+		keys = append(keys, key.(string))
+		if key == variable.SlowLogQuerySQLStr {
+			c.Assert(value, Equals, sql)
+			continue
+		}
+		c.Assert(strings.Contains(resultFields, key.(string)+": "+value), IsTrue, Commentf("key: %s, value: %s", key, value))
+	}
+	c.Assert(keys[0], Equals, variable.SlowLogTxnStartTSStr)
+	c.Assert(keys[len(keys)-1], Equals, variable.SlowLogQuerySQLStr)
+	c.Assert(keys, HasLen, strings.Count(resultFields, ": ")+1)
+
+	// The query attributes are encoded as a JSON object in one line.
+	logItems.QueryAttributes = map[string]string{"trace_id": "a b", "span": "1\n2"}
+	logString = seVar.SlowLogFormat(logItems)
+	c.Assert(logString, Equals, resultFields+"\n"+`# Query_attributes: {"span":"1\n2","trace_id":"a b"}`+"\n"+sql)
 }
 
 func (*testSessionSuite) TestIsolationRead(c *C) {
@@ -0,0 +1,153 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+// The values of session_track_transaction_info.
+const (
+	// TrackTxnInfoState tracks the transaction state.
+	TrackTxnInfoState = "STATE"
+	// TrackTxnInfoCharacteristics tracks the transaction state and characteristics.
+	TrackTxnInfoCharacteristics = "CHARACTERISTICS"
+)
+
+// SessionStateTracker records the changes of the session states which are reported to the client in the OK packet,
+// see https://dev.mysql.com/doc/refman/8.0/en/session-state-tracking.html.
+type SessionStateTracker struct {
+	// sysVars is the names of the changed system variables in the order of changing.
+	sysVars       []string
+	schemaChanged bool
+	// txnState and txnCharacteristics are the transaction information reported to the client last time.
+	txnState           string
+	txnCharacteristics string
+}
+
+// TrackSysVar records the change of a system variable in session scope.
+func (t *SessionStateTracker) TrackSysVar(name string) {
+	for _, n := range t.sysVars {
+		if n == name {
+			return
+		}
+	}
+	t.sysVars = append(t.sysVars, name)
+}
+
+// TrackSchema records the change of the current schema.
+func (t *SessionStateTracker) TrackSchema() {
+	t.schemaChanged = true
+}
+
+// Reset discards the changes of the system variables and the current schema.
+func (t *SessionStateTracker) Reset() {
+	t.sysVars = t.sysVars[:0]
+	t.schemaChanged = false
+}
+
+// SessionStateChanges is the changes of the session states which are tracked by session_track_system_variables,
+// session_track_schema and session_track_transaction_info.
+type SessionStateChanges struct {
+	// SysVars is the changed system variables, each one is a pair of name and value.
+	SysVars [][2]string
+	// Schema is the current schema, it's valid only if SchemaChanged is true.
+	Schema        string
+	SchemaChanged bool
+	// TxnState is the transaction state if it's changed, otherwise it's empty.
+	TxnState string
+	// TxnCharacteristics is the statements to restart the transaction with the same characteristics,
+	// it's valid only if TxnCharacteristicsChanged is true.
+	TxnCharacteristics        string
+	TxnCharacteristicsChanged bool
+}
+
+// Empty returns whether there is no change.
+func (c *SessionStateChanges) Empty() bool {
+	return len(c.SysVars) == 0 && !c.SchemaChanged && len(c.TxnState) == 0 && !c.TxnCharacteristicsChanged
+}
+
+// isSysVarTracked returns whether the changes of the system variable are tracked by session_track_system_variables.
+func (s *SessionVars) isSysVarTracked(name string) bool {
+	for _, n := range s.SessionTrackSystemVariables {
+		if n == "*" || n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectSessionStateChanges returns the tracked changes of the session states since the last call.
+func (s *SessionVars) CollectSessionStateChanges() *SessionStateChanges {
+	t := &s.StateTracker
+	changes := &SessionStateChanges{}
+	for _, name := range t.sysVars {
+		if !s.isSysVarTracked(name) {
+			continue
+		}
+		if val, ok := s.GetSystemVar(name); ok {
+			changes.SysVars = append(changes.SysVars, [2]string{name, val})
+		}
+	}
+	t.sysVars = t.sysVars[:0]
+
+	if t.schemaChanged && s.SessionTrackSchema {
+		changes.Schema = s.CurrentDB
+		changes.SchemaChanged = true
+	}
+	t.schemaChanged = false
+
+	if s.SessionTrackTransactionInfo == Off {
+		t.txnState, t.txnCharacteristics = "", ""
+		return changes
+	}
+	if state := s.txnState(); state != t.txnState {
+		changes.TxnState = state
+		t.txnState = state
+	}
+	if s.SessionTrackTransactionInfo == TrackTxnInfoCharacteristics {
+		if characteristics := s.txnCharacteristics(); characteristics != t.txnCharacteristics {
+			changes.TxnCharacteristics = characteristics
+			changes.TxnCharacteristicsChanged = true
+			t.txnCharacteristics = characteristics
+		}
+	}
+	return changes
+}
+
+// txnState returns the transaction state in the format of MySQL, which has 8 characters and each one
+// stands for a kind of state. Only the transaction type and transactional writes are tracked for now.
+func (s *SessionVars) txnState() string {
+	state := []byte("________")
+	if !s.InTxn() {
+		return string(state)
+	}
+	// The transaction is started explicitly by BEGIN if the autocommit is on, otherwise it's started implicitly.
+	if s.IsAutocommit() {
+		state[0] = 'T'
+	} else {
+		state[0] = 'I'
+	}
+	if len(s.TxnCtx.TableDeltaMap) > 0 {
+		state[4] = 'W'
+	}
+	return string(state)
+}
+
+// txnCharacteristics returns the statement to restart the current explicit transaction.
+func (s *SessionVars) txnCharacteristics() string {
+	if !s.InTxn() || !s.IsAutocommit() {
+		return ""
+	}
+	if s.TxnCtx.IsStaleness {
+		return "START TRANSACTION READ ONLY;"
+	}
+	return "START TRANSACTION;"
+}
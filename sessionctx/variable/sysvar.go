@@ -666,16 +666,9 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{Scope: ScopeNone, Name: SystemTimeZone, Value: "CST"},
-	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: Off, Type: TypeBool, skipInit: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
-		if TiDBOptOn(normalizedValue) {
-			// TiDB does not yet support foreign keys.
-			// Return the original value in the warning, so that users are not confused.
-			vars.StmtCtx.AppendWarning(ErrUnsupportedValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue))
-			return Off, nil
-		} else if !TiDBOptOn(normalizedValue) {
-			return Off, nil
-		}
-		return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue)
+	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: Off, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ForeignKeyChecks = TiDBOptOn(val)
+		return nil
 	}},
 	{Scope: ScopeNone, Name: Hostname, Value: DefHostname},
 	{Scope: ScopeSession, Name: Timestamp, Value: "", skipInit: true},
@@ -1757,10 +1750,46 @@ var defaultSysVars = []*SysVar{
 	}},
 	{Scope: ScopeGlobal, Name: SkipNameResolve, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal, Name: DefaultAuthPlugin, Value: mysql.AuthNativePassword, Type: TypeEnum, PossibleValues: []string{mysql.AuthNativePassword, mysql.AuthCachingSha2Password}},
+	{Scope: ScopeGlobal, Name: DefaultPasswordLifetime, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint16},
+	{Scope: ScopeGlobal, Name: PasswordHistory, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal, Name: PasswordReuseInterval, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackSystemVariables, Value: DefSessionTrackSystemVariables, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		names := make([]string, 0, 8)
+		for _, name := range strings.Split(normalizedValue, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if len(name) == 0 {
+				continue
+			}
+			if name != "*" && GetSysVar(name) == nil {
+				return normalizedValue, ErrUnknownSystemVar.GenWithStackByArgs(name)
+			}
+			names = append(names, name)
+		}
+		return strings.Join(names, ","), nil
+	}, SetSession: func(s *SessionVars, val string) error {
+		if len(val) == 0 {
+			s.SessionTrackSystemVariables = nil
+		} else {
+			s.SessionTrackSystemVariables = strings.Split(val, ",")
+		}
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackSchema, Value: On, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTrackSchema = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: SessionTrackTransactionInfo, Value: Off, Type: TypeEnum, PossibleValues: []string{Off, TrackTxnInfoState, TrackTxnInfoCharacteristics}, SetSession: func(s *SessionVars, val string) error {
+		s.SessionTrackTransactionInfo = val
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableOrderedResultMode, Value: BoolToOnOff(DefTiDBEnableOrderedResultMode), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableStableResultMode = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableForeignKey, Value: BoolToOnOff(DefTiDBEnableForeignKey), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableForeignKey = TiDBOptOn(val)
+		return nil
+	}},
 }
 
 // FeedbackProbability points to the FeedbackProbability in statistics package.
@@ -1847,6 +1876,12 @@ const (
 	LowerCaseTableNames = "lower_case_table_names"
 	// SessionTrackGtids is the name for 'session_track_gtids' system variable.
 	SessionTrackGtids = "session_track_gtids"
+	// SessionTrackSystemVariables is the name for 'session_track_system_variables' system variable.
+	SessionTrackSystemVariables = "session_track_system_variables"
+	// SessionTrackSchema is the name for 'session_track_schema' system variable.
+	SessionTrackSchema = "session_track_schema"
+	// SessionTrackTransactionInfo is the name for 'session_track_transaction_info' system variable.
+	SessionTrackTransactionInfo = "session_track_transaction_info"
 	// OldPasswords is the name for 'old_passwords' system variable.
 	OldPasswords = "old_passwords"
 	// MaxConnections is the name for 'max_connections' system variable.
@@ -2041,6 +2076,12 @@ const (
 	CTEMaxRecursionDepth = "cte_max_recursion_depth"
 	// DefaultAuthPlugin is the name of 'default_authentication_plugin' system variable.
 	DefaultAuthPlugin = "default_authentication_plugin"
+	// DefaultPasswordLifetime is the name of 'default_password_lifetime' system variable.
+	DefaultPasswordLifetime = "default_password_lifetime"
+	// PasswordHistory is the name of 'password_history' system variable.
+	PasswordHistory = "password_history"
+	// PasswordReuseInterval is the name of 'password_reuse_interval' system variable.
+	PasswordReuseInterval = "password_reuse_interval"
 )
 
 // GlobalVarAccessor is the interface for accessing global scope system and status variables.
@@ -369,14 +369,17 @@ func (*testSysVarSuite) TestTimeZone(c *C) {
 func (*testSysVarSuite) TestForeignKeyChecks(c *C) {
 	sv := GetSysVar(ForeignKeyChecks)
 	vars := NewSessionVars()
+	c.Assert(vars.ForeignKeyChecks, IsFalse)
 
 	val, err := sv.Validate(vars, "on", ScopeSession)
 	c.Assert(err, IsNil)
-	c.Assert(val, Equals, "OFF") // warns and refuses to set ON.
-
-	warn := vars.StmtCtx.GetWarnings()[0].Err
-	c.Assert(warn.Error(), Equals, "[variable:8047]variable 'foreign_key_checks' does not yet support value: on")
+	c.Assert(val, Equals, "ON")
+	c.Assert(vars.StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(sv.SetSessionFromHook(vars, val), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsTrue)
 
+	c.Assert(sv.SetSessionFromHook(vars, "OFF"), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsFalse)
 }
 
 func (*testSysVarSuite) TestTxnIsolation(c *C) {
@@ -622,3 +625,23 @@ func (*testSysVarSuite) TestDefaultValuesAreSettable(c *C) {
 		}
 	}
 }
+
+func (*testSysVarSuite) TestSessionTrackSystemVariables(c *C) {
+	vars := NewSessionVars()
+	sv := GetSysVar(SessionTrackSystemVariables)
+	val, err := sv.Validate(vars, " Time_Zone, ,AUTOCOMMIT ", ScopeSession)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "time_zone,autocommit")
+	val, err = sv.Validate(vars, "*", ScopeSession)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "*")
+	_, err = sv.Validate(vars, "time_zone,unknown_var", ScopeSession)
+	c.Assert(ErrUnknownSystemVar.Equal(err), IsTrue)
+
+	c.Assert(SetSessionSystemVar(vars, SessionTrackSystemVariables, "time_zone"), IsNil)
+	c.Assert(SetSessionSystemVar(vars, TimeZone, "+08:00"), IsNil)
+	c.Assert(SetSessionSystemVar(vars, SQLModeVar, ""), IsNil)
+	changes := vars.CollectSessionStateChanges()
+	c.Assert(changes.SysVars, DeepEquals, [][2]string{{TimeZone, "+08:00"}})
+	c.Assert(vars.CollectSessionStateChanges().Empty(), IsTrue)
+}
@@ -567,6 +567,10 @@ const (
 
 	// TiDBEnableOrderedResultMode indicates if stabilize query results.
 	TiDBEnableOrderedResultMode = "tidb_enable_ordered_result_mode"
+
+	// TiDBEnableForeignKey indicates whether the foreign keys created from now on are enforced.
+	// The foreign keys created when it's off are only metadata, like in the older versions.
+	TiDBEnableForeignKey = "tidb_enable_foreign_key"
 )
 
 // TiDB vars that have only global scope
@@ -722,6 +726,8 @@ const (
 	DefTMPTableSize                    = 16777216
 	DefTiDBEnableLocalTxn              = false
 	DefTiDBEnableOrderedResultMode     = false
+	DefTiDBEnableForeignKey            = false
+	DefSessionTrackSystemVariables     = "time_zone,autocommit,character_set_client,character_set_results,character_set_connection"
 )
 
 // Process global variables.
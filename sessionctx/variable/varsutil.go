@@ -210,7 +210,11 @@ func SetSessionSystemVar(vars *SessionVars, name string, value string) error {
 	if err != nil {
 		return err
 	}
-	return vars.SetSystemVar(name, sVal)
+	if err = vars.SetSystemVar(name, sVal); err != nil {
+		return err
+	}
+	vars.StateTracker.TrackSysVar(name)
+	return nil
 }
 
 // SetStmtVar sets system variable and updates SessionVars states.
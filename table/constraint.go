@@ -0,0 +1,71 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta and evaluable expression describing a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	ConstraintExpr expression.Expression
+}
+
+// ToConstraint converts model.ConstraintInfo to Constraint.
+func ToConstraint(ctx sessionctx.Context, constraintInfo *model.ConstraintInfo, tblInfo *model.TableInfo) (*Constraint, error) {
+	expr, err := expression.ParseSimpleExprWithTableInfo(ctx, constraintInfo.ExprString, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Constraint{
+		ConstraintInfo: constraintInfo,
+		ConstraintExpr: expr,
+	}, nil
+}
+
+// CheckConstraintTable is implemented by the tables which can enforce check constraints.
+type CheckConstraintTable interface {
+	// WritableConstraint returns the enforced constraints which the written rows must satisfy.
+	WritableConstraint() []*Constraint
+}
+
+// CheckRowConstraint checks whether the row satisfies all the enforced check constraints of the table.
+// A constraint is violated only when its expression evaluates to FALSE, NULL is regarded as satisfied.
+func CheckRowConstraint(ctx sessionctx.Context, t Table, row []types.Datum) error {
+	ct, ok := t.(CheckConstraintTable)
+	if !ok {
+		return nil
+	}
+	constraints := ct.WritableConstraint()
+	if len(constraints) == 0 {
+		return nil
+	}
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, constraint := range constraints {
+		val, isNull, err := constraint.ConstraintExpr.EvalInt(ctx, r)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !isNull && val == 0 {
+			return ErrCheckConstraintViolated.GenWithStackByArgs(constraint.Name.O)
+		}
+	}
+	return nil
+}
@@ -99,6 +99,14 @@ var (
 	ErrRowDoesNotMatchGivenPartitionSet = dbterror.ClassTable.NewStd(mysql.ErrRowDoesNotMatchGivenPartitionSet)
 	// ErrTempTableFull returns a table is full error, it's used by temporary table now.
 	ErrTempTableFull = dbterror.ClassTable.NewStd(mysql.ErrRecordFileFull)
+	// ErrCheckConstraintViolated returns when a row violates an enforced check constraint.
+	ErrCheckConstraintViolated = dbterror.ClassTable.NewStd(mysql.ErrCheckConstraintViolated)
+	// ErrNoReferencedRow returns when a child row refers to a parent row which does not exist.
+	ErrNoReferencedRow = dbterror.ClassTable.NewStd(mysql.ErrNoReferencedRow2)
+	// ErrRowIsReferenced returns when a parent row is still referred by the child rows.
+	ErrRowIsReferenced = dbterror.ClassTable.NewStd(mysql.ErrRowIsReferenced2)
+	// ErrForeignKeyCascadeDepthExceeded returns when the cascading foreign key actions nest too deep.
+	ErrForeignKeyCascadeDepthExceeded = dbterror.ClassTable.NewStd(mysql.ErrForeignKeyCascadeDepthExceeded)
 )
 
 // RecordIterFunc is used for low-level record iteration.
@@ -18,6 +18,7 @@ import (
 	"context"
 	stderr "errors"
 	"fmt"
+	"hash/crc32"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
@@ -80,6 +82,19 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+
+	// The following fields are only set when the partitions of the table are being reorganized
+	// by 'ALTER TABLE ... REORGANIZE PARTITION', 'PARTITION BY' or 'REMOVE PARTITIONING'. Writes to the partitions in reorgSources
+	// are also applied to reorgPartitions, which are located by reorgPartitionInfo and
+	// reorgPartitionExpr.
+	reorgPartitionInfo *model.PartitionInfo
+	reorgPartitionExpr *PartitionExpr
+	reorgPartitions    map[int64]*partition
+	reorgSources       map[int64]struct{}
+	// reorgReversed means the new partitions have replaced the old ones.
+	reorgReversed bool
+	// reorgDeleteOnly means only deletions are applied to reorgPartitions.
+	reorgDeleteOnly bool
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -109,9 +124,73 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if err := initReorgPartitions(ret, tbl, tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return ret, nil
 }
 
+// initReorgPartitions prepares the double writes of a table under 'ALTER TABLE ... REORGANIZE PARTITION',
+// 'ALTER TABLE ... PARTITION BY' or 'ALTER TABLE ... REMOVE PARTITIONING'.
+// Before the new partitions replace the old ones, the writes to the old partitions are also applied to
+// the new partitions. After that, the writes to the new partitions are also applied to the old partitions,
+// so the servers which haven't loaded the latest schema still read consistent data.
+func initReorgPartitions(t *partitionedTable, tbl *TableCommon, tblInfo *model.TableInfo) error {
+	pi := tblInfo.GetPartitionInfo()
+	if len(pi.AddingDefinitions) == 0 || len(pi.DroppingDefinitions) == 0 || len(pi.States) == 0 {
+		return nil
+	}
+	sources, targets := pi.DroppingDefinitions, pi.AddingDefinitions
+	if _, ok := t.partitions[pi.AddingDefinitions[0].ID]; ok {
+		sources, targets = targets, sources
+		t.reorgReversed = true
+	}
+	reorgPi := *pi
+	reorgPi.Definitions = ReplacePartitionDefinitions(pi.Definitions, sources, targets)
+	reorgPi.AddingDefinitions, reorgPi.DroppingDefinitions, reorgPi.States = nil, nil, nil
+	if reorgPi.Type == model.PartitionTypeHash || reorgPi.Type == model.PartitionTypeKey {
+		reorgPi.Num = uint64(len(reorgPi.Definitions))
+	}
+	reorgTblInfo := *tblInfo
+	reorgTblInfo.Partition = &reorgPi
+	partitionExpr, err := newPartitionExpr(&reorgTblInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t.reorgPartitionInfo = &reorgPi
+	t.reorgPartitionExpr = partitionExpr
+	t.reorgSources = make(map[int64]struct{}, len(sources))
+	for _, def := range sources {
+		t.reorgSources[def.ID] = struct{}{}
+	}
+	t.reorgPartitions = make(map[int64]*partition, len(targets))
+	for _, def := range targets {
+		var p partition
+		err := initTableCommonWithIndices(&p.TableCommon, tblInfo, def.ID, tbl.Columns, tbl.allocs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		t.reorgPartitions[def.ID] = &p
+	}
+	t.reorgDeleteOnly = pi.States[0].State == model.StateDeleteOnly
+	return nil
+}
+
+// ReplacePartitionDefinitions returns a copy of defs in which olds are replaced by news.
+// olds must be consecutive in defs.
+func ReplacePartitionDefinitions(defs, olds, news []model.PartitionDefinition) []model.PartitionDefinition {
+	ret := make([]model.PartitionDefinition, 0, len(defs)-len(olds)+len(news))
+	for i := 0; i < len(defs); i++ {
+		if defs[i].ID != olds[0].ID {
+			ret = append(ret, defs[i])
+			continue
+		}
+		ret = append(ret, news...)
+		i += len(olds) - 1
+	}
+	return ret
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -125,6 +204,8 @@ func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 		return generateRangePartitionExpr(ctx, pi, columns, names)
 	case model.PartitionTypeHash:
 		return generateHashPartitionExpr(ctx, pi, columns, names)
+	case model.PartitionTypeKey:
+		return generateKeyPartitionExpr(tblInfo, pi, columns)
 	case model.PartitionTypeList:
 		return generateListPartitionExpr(ctx, tblInfo, columns, names)
 	}
@@ -886,6 +967,27 @@ func generateHashPartitionExpr(ctx sessionctx.Context, pi *model.PartitionInfo,
 	}, nil
 }
 
+func generateKeyPartitionExpr(tblInfo *model.TableInfo, pi *model.PartitionInfo, columns []*expression.Column) (*PartitionExpr, error) {
+	// The caller should assure partition info is not nil.
+	offset := make([]int, 0, len(pi.Columns))
+	for _, col := range pi.Columns {
+		colInfo := model.FindColumnInfo(tblInfo.Cols(), col.L)
+		if colInfo == nil {
+			logutil.BgLogger().Error("wrong table partition column", zap.String("column", col.O))
+			return nil, errors.Trace(table.ErrUnknownColumn.GenWithStackByArgs(col.O, "partition function"))
+		}
+		for i, c := range columns {
+			if c.ID == colInfo.ID {
+				offset = append(offset, i)
+				break
+			}
+		}
+	}
+	return &PartitionExpr{
+		ColumnOffset: offset,
+	}, nil
+}
+
 // PartitionExpr returns the partition expression.
 func (t *partitionedTable) PartitionExpr() (*PartitionExpr, error) {
 	return t.partitionExpr, nil
@@ -899,19 +1001,27 @@ func PartitionRecordKey(pid int64, handle int64) kv.Key {
 
 // locatePartition returns the partition ID of the input record.
 func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int64, error) {
+	return t.locatePartitionByExpr(ctx, pi, t.partitionExpr, r)
+}
+
+// locatePartitionByExpr returns the partition ID of the input record in the partition layout of pi,
+// partExpr must be built from the same layout.
+func (t *partitionedTable) locatePartitionByExpr(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int64, error) {
 	var err error
 	var idx int
-	switch t.meta.Partition.Type {
+	switch pi.Type {
 	case model.PartitionTypeRange:
 		if len(pi.Columns) == 0 {
-			idx, err = t.locateRangePartition(ctx, pi, r)
+			idx, err = t.locateRangePartition(ctx, pi, partExpr, r)
 		} else {
-			idx, err = t.locateRangeColumnPartition(ctx, pi, r)
+			idx, err = t.locateRangeColumnPartition(ctx, pi, partExpr, r)
 		}
 	case model.PartitionTypeHash:
-		idx, err = t.locateHashPartition(ctx, pi, r)
+		idx, err = t.locateHashPartition(ctx, pi, partExpr, r)
+	case model.PartitionTypeKey:
+		idx, err = t.locateKeyPartition(ctx, pi, partExpr, r)
 	case model.PartitionTypeList:
-		idx, err = t.locateListPartition(ctx, pi, r)
+		idx, err = t.locateListPartition(ctx, pi, partExpr, r)
 	}
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -919,10 +1029,10 @@ func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.Par
 	return pi.Definitions[idx].ID, nil
 }
 
-func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
+func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
 	var err error
 	var isNull bool
-	partitionExprs := t.partitionExpr.UpperBounds
+	partitionExprs := partExpr.UpperBounds
 	evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 	defer t.evalBufferPool.Put(evalBuffer)
 	idx := sort.Search(len(partitionExprs), func(i int) bool {
@@ -964,22 +1074,22 @@ func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi
 	return idx, nil
 }
 
-func (t *partitionedTable) locateListPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
-	lp := t.partitionExpr.ForListPruning
+func (t *partitionedTable) locateListPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
+	lp := partExpr.ForListPruning
 	if len(lp.ColPrunes) == 0 {
 		return lp.locateListPartitionByRow(ctx, r)
 	}
 	return lp.locateListColumnsPartitionByRow(ctx, r)
 }
 
-func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
+func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
 	var (
 		ret    int64
 		val    int64
 		isNull bool
 		err    error
 	)
-	if col, ok := t.partitionExpr.Expr.(*expression.Column); ok {
+	if col, ok := partExpr.Expr.(*expression.Column); ok {
 		if r[col.Index].IsNull() {
 			isNull = true
 		}
@@ -988,14 +1098,14 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *mode
 		evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 		defer t.evalBufferPool.Put(evalBuffer)
 		evalBuffer.SetDatums(r...)
-		val, isNull, err = t.partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
+		val, isNull, err = partExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
 		if err != nil {
 			return 0, err
 		}
 		ret = val
 	}
-	unsigned := mysql.HasUnsignedFlag(t.partitionExpr.Expr.GetType().Flag)
-	ranges := t.partitionExpr.ForRangePruning
+	unsigned := mysql.HasUnsignedFlag(partExpr.Expr.GetType().Flag)
+	ranges := partExpr.ForRangePruning
 	length := len(ranges.LessThan)
 	pos := sort.Search(length, func(i int) bool {
 		if isNull {
@@ -1027,8 +1137,8 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *mode
 }
 
 // TODO: supports linear hashing
-func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
-	if col, ok := t.partitionExpr.Expr.(*expression.Column); ok {
+func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
+	if col, ok := partExpr.Expr.(*expression.Column); ok {
 		var data types.Datum
 		switch r[col.Index].Kind() {
 		case types.KindInt64, types.KindUint64:
@@ -1041,7 +1151,7 @@ func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model
 			}
 		}
 		ret := data.GetInt64()
-		ret = ret % int64(t.meta.Partition.Num)
+		ret = ret % int64(pi.Num)
 		if ret < 0 {
 			ret = -ret
 		}
@@ -1050,20 +1160,77 @@ func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model
 	evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 	defer t.evalBufferPool.Put(evalBuffer)
 	evalBuffer.SetDatums(r...)
-	ret, isNull, err := t.partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
+	ret, isNull, err := partExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
 	if err != nil {
 		return 0, err
 	}
 	if isNull {
 		return 0, nil
 	}
-	ret = ret % int64(t.meta.Partition.Num)
+	ret = ret % int64(pi.Num)
 	if ret < 0 {
 		ret = -ret
 	}
 	return int(ret), nil
 }
 
+func (t *partitionedTable) locateKeyPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
+	cols := t.Cols()
+	fts := make([]*types.FieldType, 0, len(partExpr.ColumnOffset))
+	vals := make([]types.Datum, 0, len(partExpr.ColumnOffset))
+	for _, offset := range partExpr.ColumnOffset {
+		fts = append(fts, &cols[offset].FieldType)
+		vals = append(vals, r[offset])
+	}
+	return LocateKeyPartition(ctx.GetSessionVars().StmtCtx, pi.Num, fts, vals)
+}
+
+// LocateKeyPartition returns the index of the KEY partition which the values of the partitioning columns belong to.
+// The values are hashed by their memory-comparable encodings, and strings are encoded by the collations of their
+// columns, so the values which are equal under the collation are always located in the same partition.
+// The planner uses it to prune partitions, so the values are converted to the types of the columns first.
+func LocateKeyPartition(sc *stmtctx.StatementContext, num uint64, fts []*types.FieldType, vals []types.Datum) (int, error) {
+	buf := make([]byte, 0, 64)
+	for i, val := range vals {
+		ft := fts[i]
+		if val.IsNull() {
+			buf = append(buf, codec.NilFlag)
+			continue
+		}
+		switch ft.Tp {
+		case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString:
+			if val.Kind() != types.KindString && val.Kind() != types.KindBytes {
+				converted, err := val.ConvertTo(sc, ft)
+				if err != nil {
+					return 0, errors.Trace(err)
+				}
+				val = converted
+			}
+			buf = codec.EncodeBytes(buf, collate.GetCollator(ft.Collate).Key(val.GetString()))
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+			if val.Kind() != types.KindInt64 && val.Kind() != types.KindUint64 {
+				converted, err := val.ConvertTo(sc, ft)
+				if err != nil {
+					return 0, errors.Trace(err)
+				}
+				val = converted
+			}
+			// Signed and unsigned values of the same column share the encoding of their bits.
+			buf = codec.EncodeInt(buf, val.GetInt64())
+		default:
+			converted, err := val.ConvertTo(sc, ft)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			buf, err = codec.EncodeKey(sc, buf, converted)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+		}
+	}
+	return int(crc32.ChecksumIEEE(buf) % uint32(num)), nil
+}
+
 // GetPartition returns a Table, which is actually a partition.
 func (t *partitionedTable) GetPartition(pid int64) table.PhysicalTable {
 	// Attention, can't simply use `return t.partitions[pid]` here.
@@ -1114,7 +1281,52 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return recordID, err
+	}
+	return recordID, t.addReorgRecord(ctx, pid, recordID, r)
+}
+
+// reorgPartition returns the partition to which the writes of row r in partition pid are also applied.
+// It returns nil if the table isn't being reorganized or pid isn't one of the reorganized partitions.
+func (t *partitionedTable) reorgPartition(ctx sessionctx.Context, pid int64, r []types.Datum) (*partition, error) {
+	if _, ok := t.reorgSources[pid]; !ok {
+		return nil, nil
+	}
+	reorgPid, err := t.locatePartitionByExpr(ctx, t.reorgPartitionInfo, t.reorgPartitionExpr, r)
+	if err != nil {
+		// The new partitions may accept more values than the old ones.
+		if t.reorgReversed && table.ErrNoPartitionForGivenValue.Equal(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return t.reorgPartitions[reorgPid], nil
+}
+
+func (t *partitionedTable) addReorgRecord(ctx sessionctx.Context, pid int64, h kv.Handle, r []types.Datum) error {
+	if t.reorgDeleteOnly {
+		return nil
+	}
+	p, err := t.reorgPartition(ctx, pid, r)
+	if err != nil || p == nil {
+		return err
+	}
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle && len(r) == len(t.Cols()) {
+		// Keep the _tidb_rowid, so the row can be found by the same handle in both partitions.
+		r = append(r[:len(r):len(r)], types.NewIntDatum(h.IntValue()))
+	}
+	_, err = p.AddRecord(ctx, r)
+	return err
+}
+
+func (t *partitionedTable) removeReorgRecord(ctx sessionctx.Context, pid int64, h kv.Handle, r []types.Datum) error {
+	p, err := t.reorgPartition(ctx, pid, r)
+	if err != nil || p == nil {
+		return err
+	}
+	return p.RemoveRecord(ctx, h, r)
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1158,7 +1370,11 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	err = tbl.RemoveRecord(ctx, h, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return t.removeReorgRecord(ctx, pid, h, r)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1203,7 +1419,7 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err := t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1218,11 +1434,41 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
-		return nil
+		return t.updateReorgRecord(gctx, ctx, from, to, h, newHandle, currData, newData, touched)
 	}
 
 	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return t.updateReorgRecord(gctx, ctx, from, to, h, h, currData, newData, touched)
+}
+
+func (t *partitionedTable) updateReorgRecord(gctx context.Context, ctx sessionctx.Context, from, to int64, h, newHandle kv.Handle, currData, newData []types.Datum, touched []bool) error {
+	fromPart, err := t.reorgPartition(ctx, from, currData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var toPart *partition
+	if !t.reorgDeleteOnly {
+		toPart, err = t.reorgPartition(ctx, to, newData)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if fromPart != nil && fromPart == toPart && h.Equal(newHandle) {
+		return fromPart.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	}
+	if fromPart != nil {
+		if err = fromPart.RemoveRecord(ctx, h, currData); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if toPart != nil {
+		return t.addReorgRecord(ctx, to, newHandle, newData)
+	}
+	return nil
 }
 
 // FindPartitionByName finds partition in table meta by name.
@@ -222,6 +222,57 @@ func (ts *testSuite) TestHashPartitionAddRecord(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ts *testSuite) TestKeyPartitionAddRecord(c *C) {
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec(`create table t1 (a int unsigned, b varchar(10), c decimal(10, 2), index(a)) partition by key(a, b, c) partitions 4`)
+	tb, err := ts.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	tbInfo := tb.Meta()
+	fts := []*types.FieldType{&tbInfo.Columns[0].FieldType, &tbInfo.Columns[1].FieldType, &tbInfo.Columns[2].FieldType}
+	sc := tk.Se.GetSessionVars().StmtCtx
+	rows := [][]types.Datum{
+		types.MakeDatums(uint64(1), "a", types.NewDecFromInt(1)),
+		types.MakeDatums(uint64(2), "b", types.NewDecFromFloatForTest(2.5)),
+		types.MakeDatums(uint64(3), nil, nil),
+		types.MakeDatums(nil, nil, nil),
+		types.MakeDatums(uint64(18446744073709551615), "zzzzzzzzzz", types.NewDecFromInt(-99999999)),
+	}
+	for _, row := range rows {
+		c.Assert(ts.se.NewTxn(context.Background()), IsNil)
+		rid, err := tb.AddRecord(ts.se, row)
+		c.Assert(err, IsNil)
+		idx, err := tables.LocateKeyPartition(sc, tbInfo.Partition.Num, fts, row)
+		c.Assert(err, IsNil)
+		txn, err := ts.se.Txn(true)
+		c.Assert(err, IsNil)
+		val, err := txn.Get(context.TODO(), tables.PartitionRecordKey(tbInfo.Partition.Definitions[idx].ID, rid.IntValue()))
+		c.Assert(err, IsNil)
+		c.Assert(len(val), Greater, 0)
+		_, err = ts.se.Execute(context.Background(), "commit")
+		c.Assert(err, IsNil)
+	}
+
+	// Values are converted to the types of the columns before they are hashed.
+	idx1, err := tables.LocateKeyPartition(sc, tbInfo.Partition.Num, fts, types.MakeDatums(int64(2), []byte("b"), "2.50"))
+	c.Assert(err, IsNil)
+	idx2, err := tables.LocateKeyPartition(sc, tbInfo.Partition.Num, fts, rows[1])
+	c.Assert(err, IsNil)
+	c.Assert(idx1, Equals, idx2)
+
+	// The rows are located by SQL in the same way.
+	tk.MustExec("insert into t1 values (5, 'e', 5.5), (6, 'f', 6.6), (7, 'g', 7.7), (8, 'h', 8.8)")
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("9"))
+	tk.MustQuery("select a from t1 where a = 6 and b = 'f' and c = 6.6").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t1 where a in (2, 7) and b in ('b', 'g') and c in (2.5, 7.7) order by a").Check(testkit.Rows("2", "7"))
+	tk.MustQuery("select count(*) from t1 where a is null and b is null and c is null").Check(testkit.Rows("1"))
+	tk.MustExec("update t1 set b = 'ee' where a = 5")
+	tk.MustQuery("select a from t1 where a = 5 and b = 'ee' and c = 5.5").Check(testkit.Rows("5"))
+	tk.MustExec("admin check table t1")
+	tk.MustExec("drop table if exists t1")
+}
+
 // TestPartitionGetPhysicalID tests partition.GetPhysicalID().
 func (ts *testSuite) TestPartitionGetPhysicalID(c *C) {
 	createTable1 := `CREATE TABLE test.t1 (id int(11), index(id))
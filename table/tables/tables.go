@@ -45,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/generatedexpr"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tipb/go-binlog"
@@ -67,6 +68,7 @@ type TableCommon struct {
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
 	sequence                        *sequenceCommon
+	Constraints                     []*table.Constraint
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -140,6 +142,9 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs)
+	if err := initTableConstraints(&t); err != nil {
+		return nil, err
+	}
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
@@ -184,6 +189,40 @@ func initTableIndices(t *TableCommon) error {
 	return nil
 }
 
+// initTableConstraints initializes the check constraints of the TableCommon.
+func initTableConstraints(t *TableCommon) error {
+	tblInfo := t.meta
+	if len(tblInfo.Constraints) == 0 {
+		return nil
+	}
+	ctx := mock.NewContext()
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.State == model.StateNone {
+			continue
+		}
+		constraint, err := table.ToConstraint(ctx, constraintInfo, tblInfo)
+		if err != nil {
+			return err
+		}
+		t.Constraints = append(t.Constraints, constraint)
+	}
+	return nil
+}
+
+// WritableConstraint implements table.CheckConstraintTable WritableConstraint interface.
+func (t *TableCommon) WritableConstraint() []*table.Constraint {
+	writableConstraints := make([]*table.Constraint, 0, len(t.Constraints))
+	for _, constraint := range t.Constraints {
+		if !constraint.Enforced {
+			continue
+		}
+		if constraint.State == model.StateWriteOnly || constraint.State == model.StatePublic {
+			writableConstraints = append(writableConstraints, constraint)
+		}
+	}
+	return writableConstraints
+}
+
 func initTableCommonWithIndices(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators) error {
 	initTableCommon(t, tblInfo, physicalTableID, cols, allocs)
 	return initTableIndices(t)
@@ -325,8 +364,8 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 
 	if m := t.Meta(); m.TempTableType != model.TempTableNone {
 		if tmpTable := addTemporaryTable(sctx, m); tmpTable != nil {
-			if tmpTable.GetSize() > sctx.GetSessionVars().TMPTableSize {
-				return table.ErrTempTableFull.GenWithStackByArgs(m.Name.O)
+			if err := checkTempTableSize(sctx, tmpTable, m); err != nil {
+				return err
 			}
 			defer handleTempTableSize(tmpTable, txn.Size(), txn)
 		}
@@ -601,6 +640,19 @@ func addTemporaryTable(sctx sessionctx.Context, tblInfo *model.TableInfo) tableu
 	return tempTable
 }
 
+// checkTempTableSize checks whether the data of a temporary table exceeds tmp_table_size.
+// The data of a global temporary table with "on commit preserve rows" is not limited,
+// because it's spilled to disk when the transaction commits.
+func checkTempTableSize(sctx sessionctx.Context, tmpTable tableutil.TempTable, tblInfo *model.TableInfo) error {
+	if tblInfo.TempTableType == model.TempTableGlobalPreserveRows {
+		return nil
+	}
+	if tmpTable.GetSize() > sctx.GetSessionVars().TMPTableSize {
+		return table.ErrTempTableFull.GenWithStackByArgs(tblInfo.Name.O)
+	}
+	return nil
+}
+
 // The size of a temporary table is calculated by accumulating the transaction size delta.
 func handleTempTableSize(t tableutil.TempTable, txnSizeBefore int, txn kv.Transaction) {
 	txnSizeNow := txn.Size()
@@ -625,8 +677,8 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 
 	if m := t.Meta(); m.TempTableType != model.TempTableNone {
 		if tmpTable := addTemporaryTable(sctx, m); tmpTable != nil {
-			if tmpTable.GetSize() > sctx.GetSessionVars().TMPTableSize {
-				return nil, table.ErrTempTableFull.GenWithStackByArgs(m.Name.O)
+			if err := checkTempTableSize(sctx, tmpTable, m); err != nil {
+				return nil, err
 			}
 			defer handleTempTableSize(tmpTable, txn.Size(), txn)
 		}
@@ -1039,8 +1091,8 @@ func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []type
 	}
 	if m := t.Meta(); m.TempTableType != model.TempTableNone {
 		if tmpTable := addTemporaryTable(ctx, m); tmpTable != nil {
-			if tmpTable.GetSize() > ctx.GetSessionVars().TMPTableSize {
-				return table.ErrTempTableFull.GenWithStackByArgs(m.Name.O)
+			if err := checkTempTableSize(ctx, tmpTable, m); err != nil {
+				return err
 			}
 			defer handleTempTableSize(tmpTable, txn.Size(), txn)
 		}
@@ -1415,7 +1467,7 @@ func (t *TableCommon) Allocators(ctx sessionctx.Context) autoid.Allocators {
 		return t.allocs
 	} else if ctx.GetSessionVars().IDAllocator == nil {
 		// Use an independent allocator for global temporary tables.
-		if t.meta.TempTableType == model.TempTableGlobal {
+		if tableutil.IsGlobalTemporaryTable(t.meta.TempTableType) {
 			if alloc := ctx.GetSessionVars().GetTemporaryTable(t.meta).GetAutoIDAllocator(); alloc != nil {
 				return autoid.Allocators{alloc}
 			}
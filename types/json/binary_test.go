@@ -88,6 +88,36 @@ func (s *testJSONSuite) TestBinaryJSONExtract(c *C) {
 	}
 }
 
+func (s *testJSONSuite) TestBinaryJSONExtractAll(c *C) {
+	c.Parallel()
+	bj1 := mustParseBinaryFromString(c, `{"a": [1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}], "b": true}`)
+	bj2 := mustParseBinaryFromString(c, `[{"a": 1}, {"a": [2, 3]}, {"b": 4}]`)
+
+	var tests = []struct {
+		bj             BinaryJSON
+		pathExprString string
+		expected       []string
+	}{
+		{bj1, "$", []string{`{"a": [1, "2", {"aa": "bb"}, 4, {"aa": "cc"}], "b": true}`}},
+		{bj1, "$.a", []string{`[1, "2", {"aa": "bb"}, 4, {"aa": "cc"}]`}},
+		{bj1, "$.a[*]", []string{`1`, `"2"`, `{"aa": "bb"}`, `4`, `{"aa": "cc"}`}},
+		{bj1, "$.a[*].aa", []string{`"bb"`, `"cc"`}},
+		{bj1, "$.c", nil},
+		{bj2, "$[*].a", []string{`1`, `[2, 3]`}},
+		{bj2, "$[*].a[*]", []string{`2`, `3`}},
+	}
+
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.pathExprString)
+		c.Assert(err, IsNil)
+		result := tt.bj.ExtractAll(pe)
+		c.Assert(result, HasLen, len(tt.expected), Commentf("path %s", tt.pathExprString))
+		for i, r := range result {
+			c.Assert(r.String(), Equals, tt.expected[i])
+		}
+	}
+}
+
 func (s *testJSONSuite) TestBinaryJSONType(c *C) {
 	c.Parallel()
 	var tests = []struct {
@@ -15,6 +15,8 @@ package deadlockhistory
 
 import (
 	"encoding/hex"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,11 +31,12 @@ import (
 
 // WaitChainItem represents an entry in a deadlock's wait chain.
 type WaitChainItem struct {
-	TryLockTxn     uint64
-	SQLDigest      string
-	Key            []byte
-	AllSQLDigests  []string
-	TxnHoldingLock uint64
+	TryLockTxn       uint64
+	SQLDigest        string
+	Key              []byte
+	AllSQLDigests    []string
+	TxnHoldingLock   uint64
+	ResourceGroupTag []byte
 }
 
 // DeadlockRecord represents a deadlock events, and contains multiple transactions' information.
@@ -46,6 +49,24 @@ type DeadlockRecord struct {
 	WaitChain   []WaitChainItem
 }
 
+// ChainKey returns a key made up of the transactions in the wait chain. The same deadlock seen from multiple TiDB
+// instances has the same key, since the transaction IDs are unique in the cluster.
+func (r *DeadlockRecord) ChainKey() string {
+	txns := make([]uint64, 0, len(r.WaitChain))
+	for _, item := range r.WaitChain {
+		txns = append(txns, item.TryLockTxn)
+	}
+	sort.Slice(txns, func(i, j int) bool { return txns[i] < txns[j] })
+	var buf strings.Builder
+	for i, txn := range txns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.FormatUint(txn, 10))
+	}
+	return buf.String()
+}
+
 // DeadlockHistory is a collection for maintaining recent several deadlock events. All its public APIs are thread safe.
 type DeadlockHistory struct {
 	sync.RWMutex
@@ -204,11 +225,12 @@ func ErrDeadlockToDeadlockRecord(dl *tikverr.ErrDeadlock) *DeadlockRecord {
 			logutil.BgLogger().Warn("decoding resource group tag encounters error", zap.Error(err))
 		}
 		waitChain = append(waitChain, WaitChainItem{
-			TryLockTxn:     rawItem.Txn,
-			SQLDigest:      hex.EncodeToString(sqlDigest),
-			Key:            rawItem.Key,
-			AllSQLDigests:  nil,
-			TxnHoldingLock: rawItem.WaitForTxn,
+			TryLockTxn:       rawItem.Txn,
+			SQLDigest:        hex.EncodeToString(sqlDigest),
+			Key:              rawItem.Key,
+			AllSQLDigests:    nil,
+			TxnHoldingLock:   rawItem.WaitForTxn,
+			ResourceGroupTag: rawItem.ResourceGroupTag,
 		})
 	}
 	rec := &DeadlockRecord{
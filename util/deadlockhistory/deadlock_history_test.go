@@ -257,16 +257,18 @@ func (s *testDeadlockHistorySuite) TestErrDeadlockToDeadlockRecord(c *C) {
 		IsRetryable: true,
 		WaitChain: []WaitChainItem{
 			{
-				TryLockTxn:     100,
-				SQLDigest:      digest1.String(),
-				Key:            []byte("k2"),
-				TxnHoldingLock: 101,
+				TryLockTxn:       100,
+				SQLDigest:        digest1.String(),
+				Key:              []byte("k2"),
+				TxnHoldingLock:   101,
+				ResourceGroupTag: tag1Data,
 			},
 			{
-				TryLockTxn:     101,
-				SQLDigest:      digest2.String(),
-				Key:            []byte("k1"),
-				TxnHoldingLock: 100,
+				TryLockTxn:       101,
+				SQLDigest:        digest2.String(),
+				Key:              []byte("k1"),
+				TxnHoldingLock:   100,
+				ResourceGroupTag: tag2Data,
 			},
 		},
 	}
@@ -278,6 +280,27 @@ func (s *testDeadlockHistorySuite) TestErrDeadlockToDeadlockRecord(c *C) {
 	c.Assert(record, DeepEquals, expectedRecord)
 }
 
+func (s *testDeadlockHistorySuite) TestChainKey(c *C) {
+	rec1 := &DeadlockRecord{
+		WaitChain: []WaitChainItem{
+			{TryLockTxn: 102, TxnHoldingLock: 101},
+			{TryLockTxn: 101, TxnHoldingLock: 103},
+			{TryLockTxn: 103, TxnHoldingLock: 102},
+		},
+	}
+	// The same deadlock reported from another instance, whose wait chain starts from another transaction.
+	rec2 := &DeadlockRecord{
+		WaitChain: []WaitChainItem{
+			{TryLockTxn: 101, TxnHoldingLock: 103},
+			{TryLockTxn: 103, TxnHoldingLock: 102},
+			{TryLockTxn: 102, TxnHoldingLock: 101},
+		},
+	}
+	c.Assert(rec1.ChainKey(), Equals, "101,102,103")
+	c.Assert(rec2.ChainKey(), Equals, rec1.ChainKey())
+	c.Assert((&DeadlockRecord{}).ChainKey(), Equals, "")
+}
+
 func dummyRecord() *DeadlockRecord {
 	return &DeadlockRecord{}
 }
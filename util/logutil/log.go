@@ -42,6 +42,12 @@ const (
 	DefaultRecordPlanInSlowLog = 1
 	// DefaultTiDBEnableSlowLog enables TiDB to log slow queries.
 	DefaultTiDBEnableSlowLog = true
+	// SlowLogFormatText is the slow log format made up of "# Key: value" lines followed by the query.
+	SlowLogFormatText = "text"
+	// SlowLogFormatJSON is the slow log format which writes one JSON object per line for each query.
+	SlowLogFormatJSON = "json"
+	// DefaultSlowLogFormat is the default format of the slow log.
+	DefaultSlowLogFormat = SlowLogFormatText
 	// GRPCLogDebugVerbosity enables max verbosity when debugging grpc code.
 	GRPCLogDebugVerbosity = 99
 )
@@ -68,6 +74,9 @@ type LogConfig struct {
 
 	// SlowQueryFile filename, default to File log config on empty.
 	SlowQueryFile string
+
+	// SlowQueryFormat is the format of the slow query log, one of text or json, default to text on empty.
+	SlowQueryFormat string
 }
 
 // NewLogConfig creates a LogConfig.
@@ -122,3 +122,28 @@ func (s *testLogSuite) TestSetLevel(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(log.GetLevel(), Equals, zap.DebugLevel)
 }
+
+func (s *testLogSuite) TestSlowQueryLoggerJSON(c *C) {
+	fileName := "slow_query_json"
+	conf := NewLogConfig("info", DefaultLogFormat, fileName, EmptyFileLogConfig, false)
+	conf.SlowQueryFormat = SlowLogFormatJSON
+	err := InitLogger(conf)
+	c.Assert(err, IsNil)
+	defer os.Remove(fileName)
+
+	SlowQueryLogger.Warn(`{"Query":"select 1;"}`)
+	SlowQueryLogger.Warn(`{}`)
+	c.Assert(SlowQueryLogger.Sync(), IsNil)
+
+	f, err := os.Open(fileName)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for _, pattern := range []string{`\{"Time":"[^"]+","Query":"select 1;"\}\n`, `\{"Time":"[^"]+"\}\n`} {
+		str, err := r.ReadString('\n')
+		c.Assert(err, IsNil)
+		c.Assert(str, Matches, pattern)
+	}
+	_, err = r.ReadString('\n')
+	c.Assert(err, Equals, io.EOF)
+}
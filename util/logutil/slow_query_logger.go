@@ -2,6 +2,7 @@ package logutil
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -32,8 +33,12 @@ func newSlowQueryLogger(cfg *LogConfig) (*zap.Logger, error) {
 	}
 
 	// replace 2018-12-19-unified-log-format text encoder with slow log encoder
+	var encoder zapcore.Encoder = &slowLogEncoder{}
+	if cfg.SlowQueryFormat == SlowLogFormatJSON {
+		encoder = &slowLogJSONEncoder{}
+	}
 	sqLogger = sqLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-		return log.NewTextCore(&slowLogEncoder{}, prop.Syncer, prop.Level)
+		return log.NewTextCore(encoder, prop.Syncer, prop.Level)
 	}))
 
 	return sqLogger, nil
@@ -48,6 +53,25 @@ func (e *slowLogEncoder) EncodeEntry(entry zapcore.Entry, _ []zapcore.Field) (*b
 	return b, nil
 }
 
+// slowLogJSONEncoder writes a slow log entry as a single line of JSON object. The message is expected to be
+// a JSON object formatted by SessionVars.SlowLogJSONFormat, and the time of the entry is added as its first key.
+type slowLogJSONEncoder struct {
+	slowLogEncoder
+}
+
+func (e *slowLogJSONEncoder) EncodeEntry(entry zapcore.Entry, _ []zapcore.Field) (*buffer.Buffer, error) {
+	b := _pool.Get()
+	fmt.Fprintf(b, "{\"Time\":\"%s\"", entry.Time.Format(SlowLogTimeFormat))
+	msg := strings.TrimPrefix(entry.Message, "{")
+	if len(msg) > 0 && msg[0] != '}' {
+		b.AppendByte(',')
+	}
+	fmt.Fprintf(b, "%s\n", msg)
+	return b, nil
+}
+
+func (e *slowLogJSONEncoder) Clone() zapcore.Encoder { return e }
+
 func (e *slowLogEncoder) Clone() zapcore.Encoder                          { return e }
 func (e *slowLogEncoder) AddArray(string, zapcore.ArrayMarshaler) error   { return nil }
 func (e *slowLogEncoder) AddObject(string, zapcore.ObjectMarshaler) error { return nil }
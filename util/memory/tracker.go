@@ -551,4 +551,6 @@ const (
 	LabelForSimpleTask int = -18
 	// LabelForCTEStorage represents the label of CTE storage
 	LabelForCTEStorage int = -19
+	// LabelForHashAggWorker represents the label of the hash aggregation worker
+	LabelForHashAggWorker int = -20
 )
@@ -126,6 +126,8 @@ const (
 	TypeCTE = "CTEFullScan"
 	// TypeCTEDefinition is the type of CTE definition
 	TypeCTEDefinition = "CTE"
+	// TypeJSONTable is the type of JSONTable.
+	TypeJSONTable = "JSONTable"
 )
 
 // plan id.
@@ -183,6 +185,7 @@ const (
 	typeCTE                   int = 50
 	typeCTEDefinition         int = 51
 	typeCTETable              int = 52
+	typeJSONTable             int = 53
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -292,6 +295,8 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeCTEDefinition
 	case TypeCTETable:
 		return typeCTETable
+	case TypeJSONTable:
+		return typeJSONTable
 	}
 	// Should never reach here.
 	return 0
@@ -402,6 +407,8 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeCTEDefinition
 	case typeCTETable:
 		return TypeCTETable
+	case typeJSONTable:
+		return TypeJSONTable
 	}
 
 	// Should never reach here.
@@ -107,6 +107,43 @@ func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 	return rows
 }
 
+// GetExpiredStmtSummaryHistoryRows gets the history statement summaries rows whose end time is in (after, before],
+// grouped by the end time. These summaries won't be updated any more, so they can be persisted.
+func (ssr *stmtSummaryReader) GetExpiredStmtSummaryHistoryRows(after, before int64) map[int64][][]types.Datum {
+	ssMap := ssr.ssMap
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	other := ssMap.other
+	ssMap.Unlock()
+
+	rows := make(map[int64][][]types.Datum)
+	appendIfExpiredInRange := func(ssElement *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) {
+		ssElement.Lock()
+		endTime := ssElement.endTime
+		ssElement.Unlock()
+		if endTime > after && endTime <= before {
+			rows[endTime] = append(rows[endTime], ssr.getStmtByDigestElementRow(ssElement, ssbd))
+		}
+	}
+
+	historySize := ssMap.historySize()
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		for _, ssElement := range ssbd.collectHistorySummaries(historySize) {
+			appendIfExpiredInRange(ssElement, ssbd)
+		}
+	}
+
+	other.Lock()
+	seElements := other.collectHistorySummaries(historySize)
+	other.Unlock()
+	ssbd := new(stmtSummaryByDigest)
+	for _, seElement := range seElements {
+		appendIfExpiredInRange(seElement.otherSummary, ssbd)
+	}
+	return rows
+}
+
 func (ssr *stmtSummaryReader) getStmtByDigestRow(ssbd *stmtSummaryByDigest, beginTimeForCurInterval int64) []types.Datum {
 	var ssElement *stmtSummaryByDigestElement
 
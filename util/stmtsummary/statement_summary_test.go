@@ -1283,6 +1283,57 @@ func (s *testStmtSummarySuite) TestSummaryHistory(c *C) {
 	c.Assert(len(datum), Equals, 6)
 }
 
+// Test reading the expired summaries which are going to be persisted.
+func (s *testStmtSummarySuite) TestExpiredSummaryHistory(c *C) {
+	s.ssMap.Clear()
+	now := time.Now().Unix()
+	err := s.ssMap.SetRefreshInterval("10", false)
+	c.Assert(err, IsNil)
+	defer func() {
+		err := s.ssMap.SetRefreshInterval("1800", false)
+		c.Assert(err, IsNil)
+	}()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	for i := 0; i < 5; i++ {
+		s.ssMap.beginTimeForCurInterval = now + int64(i)*10
+		s.ssMap.AddStatement(stmtExecInfo1)
+	}
+	// The end time of the summaries are now+10, now+20, ..., now+50.
+	reader := newStmtSummaryReaderForTest(s.ssMap)
+	countRows := func(after, before int64) int {
+		count := 0
+		for _, rows := range reader.GetExpiredStmtSummaryHistoryRows(after, before) {
+			count += len(rows)
+		}
+		return count
+	}
+	c.Assert(countRows(0, now), Equals, 0)
+	c.Assert(countRows(0, now+10), Equals, 1)
+	c.Assert(countRows(now+10, now+35), Equals, 2)
+	c.Assert(countRows(now+35, now+100), Equals, 2)
+	rows := reader.GetExpiredStmtSummaryHistoryRows(now+10, now+35)
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rows[now+20], HasLen, 1)
+	c.Assert(rows[now+30], HasLen, 1)
+
+	// The evicted summaries are also read.
+	err = s.ssMap.SetMaxStmtCount("1", false)
+	c.Assert(err, IsNil)
+	defer func() {
+		err := s.ssMap.SetMaxStmtCount("", false)
+		c.Assert(err, IsNil)
+	}()
+	stmtExecInfo2 := stmtExecInfo1
+	stmtExecInfo2.Digest = "bandit digest"
+	s.ssMap.AddStatement(stmtExecInfo2)
+	c.Assert(s.ssMap.other.history.Len(), Equals, 5)
+	// 5 evicted summaries and the current summary of `stmtExecInfo2`.
+	c.Assert(countRows(0, now+100), Equals, 6)
+	c.Assert(countRows(now+35, now+100), Equals, 3)
+	c.Assert(reader.GetExpiredStmtSummaryHistoryRows(now+35, now+100)[now+50], HasLen, 2)
+}
+
 // Test summary when PrevSQL is not empty.
 func (s *testStmtSummarySuite) TestPrevSQL(c *C) {
 	s.ssMap.Clear()
@@ -0,0 +1,320 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/checksum"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/encrypt"
+)
+
+var defaultTempTableDiskDataPath = "tableutil.TempTableDiskData"
+
+// tempTableDiskBlockSize is the size of a block in TempTableDiskData, only the first key of each block is kept in memory.
+const tempTableDiskBlockSize = 32 * 1024
+
+// TempTableDiskData stores the committed session data of a temporary table in a temporary file.
+// It's used when the data of the table exceeds tmp_table_size.
+// The key-value pairs are sorted by key and grouped into blocks, it's immutable once it's built.
+type TempTableDiskData struct {
+	file   *os.File
+	reader io.ReaderAt
+	// blockKeys and blockOffsets store the first key and the offset of each block.
+	blockKeys    []kv.Key
+	blockOffsets []int64
+	size         int64
+}
+
+// NewTempTableDiskData writes the key-value pairs of iter into a temporary file.
+// The keys with empty values are deleted, so they are skipped. The iter is not closed.
+func NewTempTableDiskData(iter kv.Iterator) (*TempTableDiskData, error) {
+	err := disk.CheckAndInitTempDir()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(config.GetGlobalConfig().TempStoragePath, defaultTempTableDiskDataPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d := &TempTableDiskData{}
+	var underlying io.WriteCloser = file
+	var ctrCipher *encrypt.CtrCipher
+	if config.GetGlobalConfig().Security.SpilledFileEncryptionMethod != config.SpilledFileEncryptionMethodPlaintext {
+		ctrCipher, err = encrypt.NewCtrCipher()
+		if err != nil {
+			terror.Call(file.Close)
+			terror.Log(os.Remove(file.Name()))
+			return nil, err
+		}
+		underlying = encrypt.NewWriter(file, ctrCipher)
+	}
+	w := checksum.NewWriter(underlying)
+	if err = d.writeFrom(w, iter); err != nil {
+		terror.Call(w.Close)
+		terror.Log(os.Remove(file.Name()))
+		return nil, err
+	}
+	// Closing the writer flushes the data and closes the underlying file.
+	if err = w.Close(); err != nil {
+		terror.Log(os.Remove(file.Name()))
+		return nil, errors.Trace(err)
+	}
+	d.file, err = os.Open(file.Name())
+	if err != nil {
+		terror.Log(os.Remove(file.Name()))
+		return nil, errors.Trace(err)
+	}
+	d.reader = d.file
+	if ctrCipher != nil {
+		d.reader = encrypt.NewReader(d.reader, ctrCipher)
+	}
+	d.reader = checksum.NewReader(d.reader)
+	return d, nil
+}
+
+// writeFrom writes the key-value pairs in the format of [key length, value length, key, value].
+func (d *TempTableDiskData) writeFrom(w io.Writer, iter kv.Iterator) error {
+	var buf []byte
+	for iter.Valid() {
+		value := iter.Value()
+		if len(value) > 0 {
+			key := iter.Key()
+			if len(d.blockOffsets) == 0 || d.size-d.blockOffsets[len(d.blockOffsets)-1] >= tempTableDiskBlockSize {
+				d.blockKeys = append(d.blockKeys, key.Clone())
+				d.blockOffsets = append(d.blockOffsets, d.size)
+			}
+			buf = codec.EncodeUvarint(buf[:0], uint64(len(key)))
+			buf = codec.EncodeUvarint(buf, uint64(len(value)))
+			buf = append(buf, key...)
+			buf = append(buf, value...)
+			n, err := w.Write(buf)
+			d.size += int64(n)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if err := iter.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBlock reads the idx-th block from the file.
+func (d *TempTableDiskData) readBlock(idx int) ([]byte, error) {
+	end := d.size
+	if idx+1 < len(d.blockOffsets) {
+		end = d.blockOffsets[idx+1]
+	}
+	block := make([]byte, end-d.blockOffsets[idx])
+	_, err := io.ReadFull(io.NewSectionReader(d.reader, d.blockOffsets[idx], int64(len(block))), block)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return block, nil
+}
+
+// Get returns the value of the key, kv.ErrNotExist is returned if the key doesn't exist.
+func (d *TempTableDiskData) Get(key kv.Key) ([]byte, error) {
+	iter, err := d.Iter(key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	if iter.Valid() && bytes.Equal(iter.Key(), key) {
+		return iter.Value(), nil
+	}
+	return nil, kv.ErrNotExist
+}
+
+// Iter creates an Iterator positioned on the first entry that k <= entry's key.
+// The Iterator stops before upperBound, it's unbounded if upperBound is empty.
+func (d *TempTableDiskData) Iter(k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	// Start from the last block whose first key is not greater than k.
+	blockIdx := sort.Search(len(d.blockKeys), func(i int) bool {
+		return d.blockKeys[i].Cmp(k) > 0
+	}) - 1
+	if blockIdx < 0 {
+		blockIdx = 0
+	}
+	iter := &tempTableDiskIter{d: d, upperBound: upperBound, blockIdx: blockIdx - 1}
+	for {
+		if err := iter.Next(); err != nil {
+			return nil, err
+		}
+		if !iter.Valid() || iter.key.Cmp(k) >= 0 {
+			return iter, nil
+		}
+	}
+}
+
+// DiskUsage returns the size of the data in the file.
+func (d *TempTableDiskData) DiskUsage() int64 {
+	return d.size
+}
+
+// Close releases the disk resource.
+func (d *TempTableDiskData) Close() error {
+	if d.file != nil {
+		terror.Call(d.file.Close)
+		terror.Log(os.Remove(d.file.Name()))
+	}
+	return nil
+}
+
+type tempTableDiskIter struct {
+	d          *TempTableDiskData
+	upperBound kv.Key
+	blockIdx   int
+	// block is the remaining data of the current block.
+	block []byte
+	key   kv.Key
+	value []byte
+	valid bool
+}
+
+// Valid implements the kv.Iterator interface.
+func (it *tempTableDiskIter) Valid() bool {
+	return it.valid
+}
+
+// Key implements the kv.Iterator interface.
+func (it *tempTableDiskIter) Key() kv.Key {
+	return it.key
+}
+
+// Value implements the kv.Iterator interface.
+func (it *tempTableDiskIter) Value() []byte {
+	return it.value
+}
+
+// Next implements the kv.Iterator interface.
+func (it *tempTableDiskIter) Next() (err error) {
+	for len(it.block) == 0 {
+		it.blockIdx++
+		if it.blockIdx >= len(it.d.blockOffsets) {
+			it.valid = false
+			return nil
+		}
+		if it.block, err = it.d.readBlock(it.blockIdx); err != nil {
+			it.valid = false
+			return err
+		}
+	}
+	var keyLen, valueLen uint64
+	if it.block, keyLen, err = codec.DecodeUvarint(it.block); err != nil {
+		return err
+	}
+	if it.block, valueLen, err = codec.DecodeUvarint(it.block); err != nil {
+		return err
+	}
+	it.key = it.block[:keyLen]
+	it.value = it.block[keyLen : keyLen+valueLen]
+	it.block = it.block[keyLen+valueLen:]
+	it.valid = len(it.upperBound) == 0 || it.key.Cmp(it.upperBound) < 0
+	return nil
+}
+
+// Close implements the kv.Iterator interface.
+func (it *tempTableDiskIter) Close() {
+	it.valid = false
+	it.block = nil
+}
+
+// UnionIter merges the data written in a transaction with the data committed in the session.
+// The value in the dirty iterator wins if a key exists in both of them. The deleted keys, whose values are empty,
+// are not skipped, so they still hide the committed keys when this iterator is merged with another one.
+type UnionIter struct {
+	dirty      kv.Iterator
+	committed  kv.Iterator
+	curIsDirty bool
+}
+
+// NewUnionIter creates a UnionIter.
+func NewUnionIter(dirty, committed kv.Iterator) (*UnionIter, error) {
+	it := &UnionIter{dirty: dirty, committed: committed}
+	if err := it.updateCur(); err != nil {
+		it.Close()
+		return nil, err
+	}
+	return it, nil
+}
+
+func (it *UnionIter) updateCur() error {
+	for it.dirty.Valid() && it.committed.Valid() {
+		cmp := bytes.Compare(it.dirty.Key(), it.committed.Key())
+		if cmp == 0 {
+			// The committed value is overwritten by the dirty one.
+			if err := it.committed.Next(); err != nil {
+				return err
+			}
+			continue
+		}
+		it.curIsDirty = cmp < 0
+		return nil
+	}
+	it.curIsDirty = it.dirty.Valid()
+	return nil
+}
+
+// Valid implements the kv.Iterator interface.
+func (it *UnionIter) Valid() bool {
+	return it.dirty.Valid() || it.committed.Valid()
+}
+
+// Key implements the kv.Iterator interface.
+func (it *UnionIter) Key() kv.Key {
+	if it.curIsDirty {
+		return it.dirty.Key()
+	}
+	return it.committed.Key()
+}
+
+// Value implements the kv.Iterator interface.
+func (it *UnionIter) Value() []byte {
+	if it.curIsDirty {
+		return it.dirty.Value()
+	}
+	return it.committed.Value()
+}
+
+// Next implements the kv.Iterator interface.
+func (it *UnionIter) Next() error {
+	var err error
+	if it.curIsDirty {
+		err = it.dirty.Next()
+	} else {
+		err = it.committed.Next()
+	}
+	if err != nil {
+		return err
+	}
+	return it.updateCur()
+}
+
+// Close implements the kv.Iterator interface.
+func (it *UnionIter) Close() {
+	it.dirty.Close()
+	it.committed.Close()
+}
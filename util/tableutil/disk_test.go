@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tableutil
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceIter struct {
+	keys   []kv.Key
+	values [][]byte
+	idx    int
+}
+
+func (it *sliceIter) Valid() bool   { return it.idx < len(it.keys) }
+func (it *sliceIter) Key() kv.Key   { return it.keys[it.idx] }
+func (it *sliceIter) Value() []byte { return it.values[it.idx] }
+func (it *sliceIter) Next() error   { it.idx++; return nil }
+func (it *sliceIter) Close()        {}
+func (it *sliceIter) add(k, v string) {
+	it.keys = append(it.keys, kv.Key(k))
+	it.values = append(it.values, []byte(v))
+}
+
+func collect(t *testing.T, iter kv.Iterator) []string {
+	var kvs []string
+	for iter.Valid() {
+		kvs = append(kvs, string(iter.Key())+"="+string(iter.Value()))
+		require.NoError(t, iter.Next())
+	}
+	iter.Close()
+	return kvs
+}
+
+func TestTempTableDiskData(t *testing.T) {
+	for _, method := range []string{config.SpilledFileEncryptionMethodPlaintext, config.SpilledFileEncryptionMethodAES128CTR} {
+		t.Run(method, func(t *testing.T) {
+			restore := config.RestoreFunc()
+			defer restore()
+			tmpDir := t.TempDir()
+			config.UpdateGlobal(func(conf *config.Config) {
+				conf.TempStoragePath = tmpDir
+				conf.Security.SpilledFileEncryptionMethod = method
+			})
+
+			const n = 10000
+			iter := &sliceIter{}
+			for i := 0; i < n; i++ {
+				value := fmt.Sprintf("value%08d", i)
+				if i%3 == 0 {
+					// The deleted keys are skipped.
+					value = ""
+				}
+				iter.add(fmt.Sprintf("key%08d", i), value)
+			}
+			d, err := NewTempTableDiskData(iter)
+			require.NoError(t, err)
+			require.Greater(t, len(d.blockOffsets), 1)
+			require.Greater(t, d.DiskUsage(), int64(0))
+
+			for _, i := range []int{1, 2, 3, n / 2, n - 1} {
+				value, err := d.Get(kv.Key(fmt.Sprintf("key%08d", i)))
+				if i%3 == 0 {
+					require.True(t, kv.ErrNotExist.Equal(err))
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, fmt.Sprintf("value%08d", i), string(value))
+			}
+			_, err = d.Get(kv.Key("key"))
+			require.True(t, kv.ErrNotExist.Equal(err))
+			_, err = d.Get(kv.Key("kez"))
+			require.True(t, kv.ErrNotExist.Equal(err))
+
+			it, err := d.Iter(nil, nil)
+			require.NoError(t, err)
+			require.Len(t, collect(t, it), n-(n+2)/3)
+
+			// The range crosses the blocks.
+			it, err = d.Iter(kv.Key(fmt.Sprintf("key%08d", 3000)), kv.Key(fmt.Sprintf("key%08d", 7000)))
+			require.NoError(t, err)
+			kvs := collect(t, it)
+			require.Len(t, kvs, 2666)
+			require.Equal(t, fmt.Sprintf("key%08d=value%08d", 3001, 3001), kvs[0])
+			require.Equal(t, fmt.Sprintf("key%08d=value%08d", 6998, 6998), kvs[len(kvs)-1])
+
+			it, err = d.Iter(kv.Key("kez"), nil)
+			require.NoError(t, err)
+			require.False(t, it.Valid())
+
+			fileName := d.file.Name()
+			require.NoError(t, d.Close())
+			_, err = os.Stat(fileName)
+			require.True(t, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestUnionIter(t *testing.T) {
+	dirty, committed := &sliceIter{}, &sliceIter{}
+	dirty.add("a", "1")
+	dirty.add("c", "")
+	dirty.add("d", "4")
+	committed.add("b", "2")
+	committed.add("c", "3")
+	committed.add("d", "5")
+	committed.add("e", "6")
+	iter, err := NewUnionIter(dirty, committed)
+	require.NoError(t, err)
+	// The deleted key is kept to hide the committed one.
+	require.Equal(t, []string{"a=1", "b=2", "c=", "d=4", "e=6"}, collect(t, iter))
+}
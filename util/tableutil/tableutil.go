@@ -43,3 +43,15 @@ type TempTable interface {
 // TempTableFromMeta builds a TempTable from *model.TableInfo.
 // Currently, it is assigned to tables.TempTableFromMeta in tidb package's init function.
 var TempTableFromMeta func(tblInfo *model.TableInfo) TempTable
+
+// IsGlobalTemporaryTable returns whether the table definition is shared by all sessions
+// while the data is private to each session.
+func IsGlobalTemporaryTable(tp model.TempTableType) bool {
+	return tp == model.TempTableGlobal || tp == model.TempTableGlobalPreserveRows
+}
+
+// KeepsSessionData returns whether the committed rows of the temporary table are kept
+// in the session until it is closed.
+func KeepsSessionData(tp model.TempTableType) bool {
+	return tp == model.TempTableLocal || tp == model.TempTableGlobalPreserveRows
+}